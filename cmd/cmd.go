@@ -2,19 +2,18 @@ package cmd
 
 import (
 	goflag "flag"
+	"fmt"
 	"log"
 	"os"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/peterbourgon/ff/v3"
+	"gorm.io/gorm"
 
 	"github.com/metatube-community/metatube-sdk-go/database"
 	"github.com/metatube-community/metatube-sdk-go/engine"
-	"github.com/metatube-community/metatube-sdk-go/internal/envconfig"
 	"github.com/metatube-community/metatube-sdk-go/route"
-	"github.com/metatube-community/metatube-sdk-go/route/auth"
-
 	"github.com/metatube-community/metatube-sdk-go/task"
 )
 
@@ -27,6 +26,11 @@ var Config = &struct {
 
 	// engine config
 	RequestTimeout time.Duration
+	FeedInterval   time.Duration
+
+	// job queue config
+	JobWorkers           int
+	NumberStatusSchedule string
 
 	// database config
 	DBMaxIdleConns int
@@ -34,8 +38,27 @@ var Config = &struct {
 	DBAutoMigrate  bool
 	DBPreparedStmt bool
 
+	// database migration action
+	DBMigrate      string
+	DBMigrateSteps int
+
+	// database export and import
+	DBExport         string
+	DBExportTables   string
+	DBExportProvider string
+	DBExportSince    string
+	DBImport         string
+	DBImportPolicy   string
+
+	// config file
+	ConfigFile          string
+	ConfigWatchInterval time.Duration
+
 	// version flag
 	VersionFlag bool
+
+	// dump config flag
+	DumpConfigFlag bool
 }{}
 
 func init() {
@@ -51,22 +74,47 @@ func init() {
 	flag.StringVar(&Config.Token, "token", "", "Token to access server")
 	flag.StringVar(&Config.DSN, "dsn", "", "Database Service Name")
 	flag.DurationVar(&Config.RequestTimeout, "request-timeout", engine.DefaultRequestTimeout, "Timeout per request")
+	flag.DurationVar(&Config.FeedInterval, "feed-interval", 0, "Release feed ingest interval, 0 to disable")
+	flag.IntVar(&Config.JobWorkers, "job-workers", task.DefaultWorkers, "Number of jobs run at a time")
+	flag.StringVar(&Config.NumberStatusSchedule, "number-status-schedule", task.DefaultNumberStatusSchedule, "Cron schedule of number status job, empty to run manually")
 	flag.IntVar(&Config.DBMaxIdleConns, "db-max-idle-conns", 0, "Database max idle connections")
 	flag.IntVar(&Config.DBMaxOpenConns, "db-max-open-conns", 0, "Database max open connections")
 	flag.BoolVar(&Config.DBAutoMigrate, "db-auto-migrate", false, "Database auto migration")
 	flag.BoolVar(&Config.DBPreparedStmt, "db-prepared-stmt", false, "Database prepared statement")
+	flag.StringVar(&Config.DBMigrate, "db-migrate", "", "Run database migration (up, down or status) and exit")
+	flag.IntVar(&Config.DBMigrateSteps, "db-migrate-steps", 1, "Number of migrations to roll back by down")
+	flag.StringVar(&Config.DBExport, "db-export", "", "Export cached metadata as NDJSON to file (- for stdout) and exit")
+	flag.StringVar(&Config.DBExportTables, "db-export-tables", "", "Comma-separated tables to export, all by default")
+	flag.StringVar(&Config.DBExportProvider, "db-export-provider", "", "Export metadata of the provider only")
+	flag.StringVar(&Config.DBExportSince, "db-export-since", "", "Export metadata updated since the date or RFC 3339 time")
+	flag.StringVar(&Config.DBImport, "db-import", "", "Import NDJSON metadata from file (- for stdin) and exit")
+	flag.StringVar(&Config.DBImportPolicy, "db-import-policy", "overwrite", "Import conflict policy (overwrite, keep-newer or skip)")
+	flag.StringVar(&Config.ConfigFile, "config", "", "Config file in YAML or TOML format")
+	flag.DurationVar(&Config.ConfigWatchInterval, "config-watch-interval", 10*time.Second, "Config file reload interval, 0 to disable")
 	flag.BoolVar(&Config.VersionFlag, "version", false, "Show version")
+	flag.BoolVar(&Config.DumpConfigFlag, "dump-config", false, "Dump effective config with secrets redacted")
 	ff.Parse(flag, os.Args[1:], ff.WithEnvVars())
+
+	// config file has the lowest precedence.
+	if Config.ConfigFile != "" {
+		if err := loadConfigFile(flag); err != nil {
+			log.Fatal(err)
+		}
+	}
 }
 
-func Router(names ...string) *gin.Engine {
-	db, err := database.Open(&database.Config{
+func openDB() (*gorm.DB, error) {
+	return database.Open(&database.Config{
 		DSN:                  Config.DSN,
 		PreparedStmt:         Config.DBPreparedStmt,
 		MaxIdleConns:         Config.DBMaxIdleConns,
 		MaxOpenConns:         Config.DBMaxOpenConns,
 		DisableAutomaticPing: true,
 	})
+}
+
+func Router(names ...string) *gin.Engine {
+	db, err := openDB()
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -84,16 +132,24 @@ func Router(names ...string) *gin.Engine {
 		opts = append(opts, engine.WithEngineName(name))
 	}
 
-	// // set actor provider configs if any
-	for provider, config := range envconfig.ActorProviderConfigs.Iterator() {
+	// set actor provider configs if any
+	for provider, config := range actorProviderConfigs().Iterator() {
 		opts = append(opts, engine.WithActorProviderConfig(provider, config))
 	}
 
 	// set movie provider configs if any
-	for provider, config := range envconfig.MovieProviderConfigs.Iterator() {
+	for provider, config := range movieProviderConfigs().Iterator() {
 		opts = append(opts, engine.WithMovieProviderConfig(provider, config))
 	}
 
+	// set freshness policies from config file if any
+	opts = append(opts, engine.WithFreshnessPolicies(freshnessPolicies()))
+
+	// set translator from config file if any
+	if translator := configFileTranslator(); translator != nil {
+		opts = append(opts, engine.WithTranslator(translator))
+	}
+
 	app := engine.New(db, opts...)
 
 	// always enable auto migrate for sqlite DB
@@ -104,12 +160,42 @@ func Router(names ...string) *gin.Engine {
 		log.Fatal(err)
 	}
 
-	var token auth.Validator
-	if Config.Token != "" {
-		token = auth.Token(Config.Token)
+	// apply runtime provider settings
+	if err = app.LoadProviderSettings(); err != nil {
+		log.Fatal(err)
+	}
+
+	// always reloadable, so that the token can be enabled later.
+	reloadableToken.Set(Config.Token)
+
+	// hot reload config file if any
+	if Config.ConfigFile != "" && Config.ConfigWatchInterval > 0 {
+		watchConfigFile(app)
+	}
+
+	queue := task.NewQueue(db, Config.JobWorkers)
+	task.RegisterJobs(queue, db, app)
+	if err = scheduleJobs(queue); err != nil {
+		log.Fatal(err)
 	}
+	queue.Start()
 
-	task.StartScheduledTasks(db, app)
+	return route.New(app, queue, reloadableToken)
+}
 
-	return route.New(app, token)
+// scheduleJobs creates or updates the built-in jobs by the config.
+func scheduleJobs(queue *task.Queue) error {
+	var feedSchedule string
+	if Config.FeedInterval > 0 {
+		feedSchedule = "@every " + Config.FeedInterval.String()
+	}
+	for name, spec := range map[string]string{
+		task.NumberStatusJob: Config.NumberStatusSchedule,
+		task.ReleaseFeedJob:  feedSchedule,
+	} {
+		if err := queue.Schedule(name, name, spec, nil); err != nil {
+			return fmt.Errorf("schedule job %s: %w", name, err)
+		}
+	}
+	return nil
 }
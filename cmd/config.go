@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"encoding/json"
+	goflag "flag"
+	"io"
+	"log"
+	"maps"
+	"reflect"
+	"strings"
+	"time"
+
+	cimaps "github.com/metatube-community/metatube-sdk-go/collection/maps"
+	"github.com/metatube-community/metatube-sdk-go/engine"
+	"github.com/metatube-community/metatube-sdk-go/internal/configfile"
+	"github.com/metatube-community/metatube-sdk-go/internal/envconfig"
+	"github.com/metatube-community/metatube-sdk-go/route/auth"
+	"github.com/metatube-community/metatube-sdk-go/translate"
+)
+
+var (
+	// configFile is the last successfully loaded config file.
+	configFile = &configfile.Config{}
+
+	// reloadableToken is the token validator, an empty token disables
+	// the auth, so that it can be enabled by the config file later.
+	reloadableToken = auth.NewAtomicToken("")
+
+	// tokenFlagSet is true if the token is set by either command-line
+	// or environment, which overrides the config file on reloads too.
+	tokenFlagSet bool
+)
+
+// loadConfigFile loads the config file, and sets the flags which
+// are not set explicitly, by either command-line or environment.
+func loadConfigFile(flag *goflag.FlagSet) error {
+	c, err := configfile.Load(Config.ConfigFile)
+	if err != nil {
+		return err
+	}
+	isSet := make(map[string]bool)
+	flag.Visit(func(f *goflag.Flag) { isSet[f.Name] = true })
+	tokenFlagSet = isSet["token"]
+	for name, value := range c.Flags() {
+		if isSet[name] {
+			continue
+		}
+		if err = flag.Set(name, value); err != nil {
+			return err
+		}
+	}
+	configFile = c
+	return nil
+}
+
+// actorProviderConfigs returns the actor provider configs from
+// the config file, overridden by the environment variables.
+func actorProviderConfigs() *cimaps.CaseInsensitiveMap[*envconfig.Config] {
+	return mergeProviderConfigs(envconfig.ActorProviderConfigs, configFile.ActorProviderConfigs())
+}
+
+// movieProviderConfigs returns the movie provider configs from
+// the config file, overridden by the environment variables.
+func movieProviderConfigs() *cimaps.CaseInsensitiveMap[*envconfig.Config] {
+	return mergeProviderConfigs(envconfig.MovieProviderConfigs, configFile.MovieProviderConfigs())
+}
+
+func mergeProviderConfigs(env *cimaps.CaseInsensitiveMap[*envconfig.Config], file map[string]map[string]string) *cimaps.CaseInsensitiveMap[*envconfig.Config] {
+	merged := cimaps.NewCaseInsensitiveMap[*envconfig.Config]()
+	for provider, config := range file {
+		c := envconfig.NewConfig()
+		for k, v := range config {
+			c.Set(k, v)
+		}
+		merged.Set(provider, c)
+	}
+	for provider, config := range env.Iterator() {
+		if !merged.Has(provider) {
+			merged.Set(provider, envconfig.NewConfig())
+		}
+		for k, v := range config.Iterator() {
+			merged.GetOrDefault(provider).Set(k, v)
+		}
+	}
+	return merged
+}
+
+// configFileTranslator returns the translator set in the config file, or nil.
+func configFileTranslator() translate.Translator {
+	tc := configFile.Translation
+	if tc.Engine == "" {
+		return nil
+	}
+	return translate.New(tc.Engine, func(v any) error {
+		data, err := json.Marshal(tc.Options)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(data, v)
+	})
+}
+
+// freshnessPolicies returns the freshness policies from the config file.
+func freshnessPolicies() (engine.FreshnessPolicy, map[string]engine.FreshnessPolicy) {
+	convert := func(p configfile.FreshnessPolicy) engine.FreshnessPolicy {
+		return engine.FreshnessPolicy{
+			MovieUpcoming:     time.Duration(p.MovieUpcoming),
+			MovieRecent:       time.Duration(p.MovieRecent),
+			MovieOld:          time.Duration(p.MovieOld),
+			MovieRecentPeriod: time.Duration(p.MovieRecentPeriod),
+			Actor:             time.Duration(p.Actor),
+		}
+	}
+	fc := configFile.Engine.Freshness
+	providers := make(map[string]engine.FreshnessPolicy, len(fc.Providers))
+	for name, policy := range fc.Providers {
+		providers[name] = convert(policy)
+	}
+	return convert(fc.Default), providers
+}
+
+// watchConfigFile reloads the settings which can be changed
+// safely at runtime, i.e. token, provider configs and freshness policies.
+func watchConfigFile(app *engine.Engine) {
+	configfile.Watch(Config.ConfigFile, Config.ConfigWatchInterval, func(c *configfile.Config, err error) {
+		if err != nil {
+			log.Printf("Reload config file: %v", err)
+			return
+		}
+		prev := configFile
+		configFile = c
+		log.Printf("Reload config file: %s", Config.ConfigFile)
+
+		// Token.
+		if c.Server.Token != prev.Server.Token {
+			if tokenFlagSet {
+				log.Printf("Token is set by flag or environment, config file change is ignored")
+			} else {
+				reloadableToken.Set(c.Server.Token)
+			}
+		}
+
+		// Provider configs.
+		reloadProviderConfigs(app, "actor",
+			mergeProviderConfigs(envconfig.ActorProviderConfigs, prev.ActorProviderConfigs()),
+			actorProviderConfigs())
+		reloadProviderConfigs(app, "movie",
+			mergeProviderConfigs(envconfig.MovieProviderConfigs, prev.MovieProviderConfigs()),
+			movieProviderConfigs())
+
+		// Freshness policies.
+		if !reflect.DeepEqual(c.Engine.Freshness, prev.Engine.Freshness) {
+			app.SetFreshnessPolicies(freshnessPolicies())
+		}
+
+		// Others.
+		prevFlags, flags := prev.Flags(), c.Flags()
+		delete(prevFlags, "token")
+		delete(flags, "token")
+		if !maps.Equal(prevFlags, flags) ||
+			prev.Translation.Engine != c.Translation.Engine ||
+			!maps.Equal(prev.Translation.Options, c.Translation.Options) {
+			log.Printf("Server, database, engine and translation changes require restart")
+		}
+	})
+}
+
+// reloadProviderConfigs reapplies the changed provider configs, keys
+// removed from the config file keep their values until restart.
+func reloadProviderConfigs(app *engine.Engine, providerType string, prev, next *cimaps.CaseInsensitiveMap[*envconfig.Config]) {
+	for provider, config := range next.Iterator() {
+		values := providerConfigValues(config)
+		if old, ok := prev.Get(provider); ok && maps.Equal(providerConfigValues(old), values) {
+			continue
+		}
+		if err := app.ReloadProviderConfig(providerType, provider, values); err != nil {
+			log.Printf("Reload %s provider config for %s: %v", providerType, provider, err)
+		}
+	}
+}
+
+func providerConfigValues(config *envconfig.Config) map[string]string {
+	values := make(map[string]string)
+	for k, v := range config.Iterator() {
+		values[strings.ToLower(k)] = v
+	}
+	return values
+}
+
+// DumpConfig writes the effective config with secrets redacted.
+func DumpConfig(w io.Writer) error {
+	c := &configfile.Config{
+		Server: configfile.ServerConfig{
+			Bind:  Config.Bind,
+			Port:  Config.Port,
+			Token: Config.Token,
+		},
+		Database: configfile.DatabaseConfig{
+			DSN:          Config.DSN,
+			MaxIdleConns: Config.DBMaxIdleConns,
+			MaxOpenConns: Config.DBMaxOpenConns,
+			AutoMigrate:  Config.DBAutoMigrate,
+			PreparedStmt: Config.DBPreparedStmt,
+		},
+		Engine: configfile.EngineConfig{
+			RequestTimeout: configfile.Duration(Config.RequestTimeout),
+			FeedInterval:   configfile.Duration(Config.FeedInterval),
+			Freshness:      configFile.Engine.Freshness,
+			Jobs: configfile.JobsConfig{
+				Workers:              Config.JobWorkers,
+				NumberStatusSchedule: Config.NumberStatusSchedule,
+			},
+		},
+		Translation: configFile.Translation,
+		Providers: configfile.ProvidersConfig{
+			Actor: toProviderConfigs(actorProviderConfigs()),
+			Movie: toProviderConfigs(movieProviderConfigs()),
+		},
+	}
+	format := configfile.FormatYAML
+	if Config.ConfigFile != "" {
+		format, _ = configfile.FormatOf(Config.ConfigFile)
+	}
+	data, err := c.Redacted().Marshal(format)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func toProviderConfigs(configs *cimaps.CaseInsensitiveMap[*envconfig.Config]) map[string]configfile.ProviderConfig {
+	result := make(map[string]configfile.ProviderConfig)
+	for provider, config := range configs.Iterator() {
+		pc := make(configfile.ProviderConfig)
+		for k, v := range providerConfigValues(config) {
+			pc[k] = v
+		}
+		result[provider] = pc
+	}
+	return result
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/metatube-community/metatube-sdk-go/engine/dbengine"
+)
+
+// Migrate runs the database migration action, one of up, down
+// or status, and writes the result to w.
+func Migrate(w io.Writer, action string) error {
+	db, err := openDB()
+	if err != nil {
+		return err
+	}
+	dbe := dbengine.New(db)
+	m := dbe.Migrator()
+
+	switch action {
+	case "up":
+		pending, err := m.Pending()
+		if err != nil {
+			return err
+		}
+		if err = dbe.Migrate(); err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "Applied %d migrations, now at version %d\n", len(pending), m.Latest())
+		return err
+	case "down":
+		n, err := m.Down(Config.DBMigrateSteps)
+		if err != nil {
+			return err
+		}
+		version, err := m.Version()
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "Rolled back %d migrations, now at version %d\n", n, version)
+		return err
+	case "status":
+		statuses, err := m.Status()
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range statuses {
+			appliedAt := "pending"
+			if s.AppliedAt != nil {
+				appliedAt = s.AppliedAt.Local().Format(time.DateTime)
+			}
+			fmt.Fprintf(tw, "%04d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+		}
+		if err = tw.Flush(); err != nil {
+			return err
+		}
+		return m.Check()
+	default:
+		return fmt.Errorf("invalid migration action: %s", action)
+	}
+}
@@ -23,6 +23,34 @@ func main() {
 		showVersionAndExit()
 	}
 
+	if cmd.Config.DumpConfigFlag {
+		if err := cmd.DumpConfig(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		os.Exit(0)
+	}
+
+	if cmd.Config.DBMigrate != "" {
+		if err := cmd.Migrate(os.Stdout, cmd.Config.DBMigrate); err != nil {
+			log.Fatal(err)
+		}
+		os.Exit(0)
+	}
+
+	if cmd.Config.DBExport != "" {
+		if err := cmd.Export(os.Stderr, cmd.Config.DBExport); err != nil {
+			log.Fatal(err)
+		}
+		os.Exit(0)
+	}
+
+	if cmd.Config.DBImport != "" {
+		if err := cmd.Import(os.Stderr, cmd.Config.DBImport); err != nil {
+			log.Fatal(err)
+		}
+		os.Exit(0)
+	}
+
 	var (
 		addr = net.JoinHostPort(
 			cmd.Config.Bind,
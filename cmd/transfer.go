@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/metatube-community/metatube-sdk-go/engine/dbengine"
+)
+
+// Export writes the cached metadata as NDJSON to the file, or to
+// stdout if the path is "-", and reports the result to w.
+func Export(w io.Writer, path string) error {
+	opts := dbengine.ExportOptions{
+		Provider: Config.DBExportProvider,
+	}
+	if Config.DBExportTables != "" {
+		opts.Tables = strings.Split(Config.DBExportTables, ",")
+	}
+	if Config.DBExportSince != "" {
+		since, err := dbengine.ParseSince(Config.DBExportSince)
+		if err != nil {
+			return err
+		}
+		opts.UpdatedSince = since
+	}
+
+	db, err := openDB()
+	if err != nil {
+		return err
+	}
+	dbe := dbengine.New(db)
+
+	out := os.Stdout
+	if path != "-" {
+		if out, err = os.Create(path); err != nil {
+			return err
+		}
+		defer out.Close()
+	}
+	bw := bufio.NewWriter(out)
+	n, err := dbe.Export(bw, opts)
+	if err != nil {
+		return err
+	}
+	if err = bw.Flush(); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "Exported %d records\n", n)
+	return err
+}
+
+// Import reads the NDJSON metadata from the file, or from stdin if
+// the path is "-", and reports the progress to w.
+func Import(w io.Writer, path string) error {
+	db, err := openDB()
+	if err != nil {
+		return err
+	}
+	dbe := dbengine.New(db)
+	if Config.DBAutoMigrate {
+		err = dbe.Migrate()
+	} else {
+		err = dbe.Migrator().Check()
+	}
+	if err != nil {
+		return err
+	}
+
+	in := os.Stdin
+	if path != "-" {
+		if in, err = os.Open(path); err != nil {
+			return err
+		}
+		defer in.Close()
+	}
+	progress, err := dbe.Import(in, dbengine.ImportOptions{
+		Policy: dbengine.ConflictPolicy(Config.DBImportPolicy),
+		Progress: func(p dbengine.ImportProgress) {
+			fmt.Fprintf(w, "Read %d records...\n", p.Read)
+		},
+	})
+	if progress != nil {
+		for _, e := range progress.Errors {
+			fmt.Fprintf(w, "Line %d: %s\n", e.Line, e.Error)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "Read %d records: %d imported, %d skipped, %d invalid\n",
+		progress.Read, progress.Imported, progress.Skipped, progress.Invalid)
+	return err
+}
@@ -0,0 +1,275 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"unicode/utf8"
+)
+
+// Mode is the working mode of a Cassette.
+type Mode uint8
+
+const (
+	// ModeDisabled passes all requests through untouched.
+	ModeDisabled Mode = iota
+	// ModeRecord passes all requests through and records the exchanges.
+	ModeRecord
+	// ModeReplay serves requests from recorded exchanges only.
+	ModeReplay
+)
+
+func (m Mode) String() string {
+	switch m {
+	case ModeDisabled:
+		return "disabled"
+	case ModeRecord:
+		return "record"
+	case ModeReplay:
+		return "replay"
+	default:
+		return fmt.Sprintf("Mode(%d)", m)
+	}
+}
+
+// ParseMode parses mode from string.
+func ParseMode(s string) (Mode, error) {
+	switch s {
+	case "", "disabled", "live":
+		return ModeDisabled, nil
+	case "record":
+		return ModeRecord, nil
+	case "replay":
+		return ModeReplay, nil
+	default:
+		return 0, fmt.Errorf("invalid cassette mode: %s", s)
+	}
+}
+
+const base64Encoding = "base64"
+
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+func (r *Request) key() string {
+	return r.Method + " " + r.URL + "\n" + r.Body
+}
+
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body"`
+	Encoding   string      `json:"encoding,omitempty"`
+}
+
+func (r *Response) setBody(data []byte) {
+	if utf8.Valid(data) {
+		r.Body, r.Encoding = string(data), ""
+		return
+	}
+	r.Body, r.Encoding = base64.StdEncoding.EncodeToString(data), base64Encoding
+}
+
+func (r *Response) body() ([]byte, error) {
+	if r.Encoding == base64Encoding {
+		return base64.StdEncoding.DecodeString(r.Body)
+	}
+	return []byte(r.Body), nil
+}
+
+// Interaction is a single recorded HTTP exchange.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Cassette records HTTP exchanges into a file, and replays
+// them deterministically afterward. It's safe for concurrent use.
+type Cassette struct {
+	mu           sync.Mutex
+	path         string
+	mode         Mode
+	interactions []*Interaction
+	// replay cursors, keyed by request key.
+	cursors map[string]int
+}
+
+// New returns a new *Cassette stored at path. Recorded
+// exchanges are loaded from path in replay mode.
+func New(path string, mode Mode) (*Cassette, error) {
+	c := &Cassette{
+		path:    path,
+		mode:    mode,
+		cursors: make(map[string]int),
+	}
+	if mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(data, &c.interactions); err != nil {
+			return nil, fmt.Errorf("decode cassette %s: %w", path, err)
+		}
+	}
+	return c, nil
+}
+
+// Exists reports whether a cassette file exists at path.
+func Exists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// Mode returns the working mode of the cassette.
+func (c *Cassette) Mode() Mode { return c.mode }
+
+// Wrap returns a http.RoundTripper backed by the cassette, the
+// next transport is used to make real requests when necessary.
+// It satisfies the fetch.TransportWrapper signature.
+func (c *Cassette) Wrap(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &transport{cassette: c, next: next}
+}
+
+// Save writes all recorded exchanges into the cassette file.
+// It's a no-op unless the cassette is in record mode.
+func (c *Cassette) Save() error {
+	if c.mode != ModeRecord {
+		return nil
+	}
+	c.mu.Lock()
+	data, err := json.MarshalIndent(c.interactions, "", "\t")
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, data, 0o644)
+}
+
+func (c *Cassette) record(i *Interaction) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.interactions = append(c.interactions, i)
+}
+
+// find returns the next unused interaction that matches the request.
+// The last matched interaction is reused once all have been consumed,
+// since providers might revisit the same URL.
+func (c *Cassette) find(r *Request) (*Interaction, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := r.key()
+	var matches []*Interaction
+	for _, i := range c.interactions {
+		if i.Request.key() == key {
+			matches = append(matches, i)
+		}
+	}
+	if len(matches) == 0 {
+		return nil, false
+	}
+	n := c.cursors[key]
+	if n >= len(matches) {
+		n = len(matches) - 1
+	}
+	c.cursors[key] = n + 1
+	return matches[n], true
+}
+
+// ErrInteractionNotFound is returned when there is no
+// recorded exchange matches the request in replay mode.
+var ErrInteractionNotFound = errors.New("cassette: interaction not found")
+
+type transport struct {
+	cassette *Cassette
+	next     http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.cassette.mode == ModeDisabled {
+		return t.next.RoundTrip(req)
+	}
+
+	r := &Request{
+		Method: req.Method,
+		URL:    req.URL.String(),
+	}
+	if req.Body != nil && req.Body != http.NoBody {
+		data, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = string(data)
+		// restore request body.
+		req.Body = io.NopCloser(bytes.NewReader(data))
+	}
+
+	if t.cassette.mode == ModeReplay {
+		i, ok := t.cassette.find(r)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s %s", ErrInteractionNotFound, r.Method, r.URL)
+		}
+		return i.Response.toHTTPResponse(req)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	// restore response body.
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+
+	i := &Interaction{
+		Request: *r,
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header.Clone(),
+		},
+	}
+	i.Response.setBody(data)
+	t.cassette.record(i)
+	return resp, nil
+}
+
+func (r *Response) toHTTPResponse(req *http.Request) (*http.Response, error) {
+	data, err := r.body()
+	if err != nil {
+		return nil, err
+	}
+	header := r.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(data)),
+		ContentLength: int64(len(data)),
+		Request:       req,
+	}, nil
+}
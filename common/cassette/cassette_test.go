@@ -0,0 +1,104 @@
+package cassette
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCassette(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := hits.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Hit", strings.Repeat("*", int(n)))
+		switch r.URL.Path {
+		case "/binary":
+			_, _ = w.Write([]byte{0xff, 0xfe, 0x00, 0x01})
+		case "/echo":
+			_, _ = w.Write(body)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	path := filepath.Join(t.TempDir(), "cassettes", "test.json")
+
+	get := func(c *http.Client, method, url, body string) (*http.Response, []byte) {
+		req, err := http.NewRequest(method, url, strings.NewReader(body))
+		require.NoError(t, err)
+		resp, err := c.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, data
+	}
+
+	// Record.
+	rec, err := New(path, ModeRecord)
+	require.NoError(t, err)
+	client := &http.Client{Transport: rec.Wrap(nil)}
+
+	_, data := get(client, http.MethodGet, server.URL+"/binary", "")
+	assert.Equal(t, []byte{0xff, 0xfe, 0x00, 0x01}, data)
+	_, data = get(client, http.MethodPost, server.URL+"/echo", "a")
+	assert.Equal(t, "a", string(data))
+	_, data = get(client, http.MethodPost, server.URL+"/echo", "b")
+	assert.Equal(t, "b", string(data))
+	resp, _ := get(client, http.MethodGet, server.URL+"/missing", "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	require.NoError(t, rec.Save())
+	require.True(t, Exists(path))
+
+	// Replay without the server.
+	server.Close()
+	recorded := hits.Load()
+
+	rep, err := New(path, ModeReplay)
+	require.NoError(t, err)
+	client = &http.Client{Transport: rep.Wrap(nil)}
+
+	resp, data = get(client, http.MethodGet, server.URL+"/binary", "")
+	assert.Equal(t, []byte{0xff, 0xfe, 0x00, 0x01}, data)
+	assert.Equal(t, "*", resp.Header.Get("X-Hit"))
+	_, data = get(client, http.MethodPost, server.URL+"/echo", "b")
+	assert.Equal(t, "b", string(data))
+	_, data = get(client, http.MethodPost, server.URL+"/echo", "a")
+	assert.Equal(t, "a", string(data))
+	resp, _ = get(client, http.MethodGet, server.URL+"/missing", "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// Revisit should reuse the last matched interaction.
+	resp, _ = get(client, http.MethodGet, server.URL+"/binary", "")
+	assert.Equal(t, "*", resp.Header.Get("X-Hit"))
+
+	_, err = client.Get(server.URL + "/unknown")
+	assert.ErrorIs(t, err, ErrInteractionNotFound)
+	assert.Equal(t, recorded, hits.Load())
+}
+
+func TestParseMode(t *testing.T) {
+	for _, unit := range []struct {
+		s    string
+		mode Mode
+	}{
+		{"", ModeDisabled},
+		{"live", ModeDisabled},
+		{"record", ModeRecord},
+		{"replay", ModeReplay},
+	} {
+		mode, err := ParseMode(unit.s)
+		require.NoError(t, err)
+		assert.Equal(t, unit.mode, mode)
+	}
+	_, err := ParseMode("unknown")
+	assert.Error(t, err)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/hashicorp/go-cleanhttp"
 	"github.com/hashicorp/go-retryablehttp"
 
+	"github.com/metatube-community/metatube-sdk-go/common/proxy"
 	"github.com/metatube-community/metatube-sdk-go/common/random"
 	"github.com/metatube-community/metatube-sdk-go/errors"
 )
@@ -38,6 +39,10 @@ type Config struct {
 	// Custom HTTP Transport.
 	Transport http.RoundTripper
 
+	// Dynamic proxy switch, requests are sent
+	// through its current proxy pool if set.
+	Proxy *proxy.Switch
+
 	// Skip TLS verification. Applies only
 	// to *http.Transport based transport.
 	SkipVerify bool
@@ -96,6 +101,8 @@ func Default(cfg *Config) *Fetcher {
 			transport.TLSClientConfig.InsecureSkipVerify = true
 		}
 	}
+	// apply proxy switch and process-wide wrapper, if any.
+	c.HTTPClient.Transport = WrapTransport(cfg.Proxy.Wrap(c.HTTPClient.Transport))
 	return New(c.StandardClient(), cfg)
 }
 
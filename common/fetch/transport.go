@@ -0,0 +1,50 @@
+package fetch
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/metatube-community/metatube-sdk-go/common/ratelimit"
+)
+
+// TransportWrapper wraps a http.RoundTripper with extra behaviours.
+type TransportWrapper func(http.RoundTripper) http.RoundTripper
+
+var (
+	wrapperMu sync.RWMutex
+	wrapper   TransportWrapper
+)
+
+// SetTransportWrapper sets a process-wide wrapper which is applied to the
+// transports of all Fetchers and scrapers, including the package-level
+// ones created before, it's mainly used by tests to record or replay
+// HTTP traffic. Pass nil to unset it. As it's process-wide, the tests
+// setting it must not run in parallel with other tests making requests.
+func SetTransportWrapper(w TransportWrapper) {
+	wrapperMu.Lock()
+	defer wrapperMu.Unlock()
+	wrapper = w
+}
+
+// WrapTransport applies the shared per-host limits and then the
+// process-wide wrapper, if any, to rt. All outgoing HTTP requests
+// should go through it. A nil rt will be treated as http.DefaultTransport.
+func WrapTransport(rt http.RoundTripper) http.RoundTripper {
+	return &wrappedTransport{next: ratelimit.DefaultRegistry.Wrap(rt)}
+}
+
+// wrappedTransport looks up the process-wide wrapper per request, so
+// that the wrapper set afterward still applies.
+type wrappedTransport struct {
+	next http.RoundTripper
+}
+
+func (t *wrappedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	wrapperMu.RLock()
+	w := wrapper
+	wrapperMu.RUnlock()
+	if w != nil {
+		return w(t.next).RoundTrip(req)
+	}
+	return t.next.RoundTrip(req)
+}
@@ -36,3 +36,56 @@ func ParseBestMediaURI(reader io.Reader) (string, m3u8.ListType, error) {
 		return "", 0, errors.New("bad list type")
 	}
 }
+
+// Rewrite decodes the playlist from reader, replaces the URIs of all
+// variants, renditions, segments, keys and maps with fn, and returns
+// the encoded playlist.
+func Rewrite(reader io.Reader, fn func(uri string) string) ([]byte, m3u8.ListType, error) {
+	playList, listType, err := m3u8.DecodeFrom(reader, true)
+	if err != nil {
+		return nil, 0, err
+	}
+	// the same key, map or rendition may be shared
+	// by many items, make sure to rewrite it once.
+	seen := make(map[*string]struct{})
+	rewrite := func(uri *string) {
+		if _, ok := seen[uri]; ok || *uri == "" {
+			return
+		}
+		seen[uri] = struct{}{}
+		*uri = fn(*uri)
+	}
+	switch listType {
+	case m3u8.MEDIA:
+		mediaPL := playList.(*m3u8.MediaPlaylist)
+		if mediaPL.Key != nil {
+			rewrite(&mediaPL.Key.URI)
+		}
+		if mediaPL.Map != nil {
+			rewrite(&mediaPL.Map.URI)
+		}
+		for _, segment := range mediaPL.Segments {
+			if segment == nil {
+				continue
+			}
+			rewrite(&segment.URI)
+			if segment.Key != nil {
+				rewrite(&segment.Key.URI)
+			}
+			if segment.Map != nil {
+				rewrite(&segment.Map.URI)
+			}
+		}
+	case m3u8.MASTER:
+		masterPL := playList.(*m3u8.MasterPlaylist)
+		for _, variant := range masterPL.Variants {
+			rewrite(&variant.URI)
+			for _, alternative := range variant.Alternatives {
+				rewrite(&alternative.URI)
+			}
+		}
+	default:
+		return nil, 0, errors.New("bad list type")
+	}
+	return playList.Encode().Bytes(), listType, nil
+}
@@ -2,6 +2,7 @@ package m3u8
 
 import (
 	"bytes"
+	"strings"
 	"testing"
 	"unsafe"
 
@@ -44,6 +45,19 @@ const m3u8Sample2 = `
 #EXT-X-ENDLIST
 `
 
+const m3u8Sample3 = `
+#EXTM3U
+#EXT-X-VERSION:3
+#EXT-X-TARGETDURATION:10
+#EXT-X-MEDIA-SEQUENCE:0
+#EXT-X-KEY:METHOD=AES-128,URI="key.bin"
+#EXTINF:10,
+seg0.ts
+#EXTINF:10,
+seg1.ts
+#EXT-X-ENDLIST
+`
+
 func TestParseBestMediaURI(t *testing.T) {
 	buf := bytes.NewReader(unsafe.Slice(unsafe.StringData(m3u8Sample1), len(m3u8Sample1)))
 	url, typ, err := ParseBestMediaURI(buf)
@@ -57,3 +71,20 @@ func TestParseBestMediaURI(t *testing.T) {
 	require.Equal(t, "", url)
 	require.Equal(t, m3u8.MEDIA, typ)
 }
+
+func TestRewrite(t *testing.T) {
+	prefix := func(uri string) string { return "/proxy?u=" + uri }
+
+	data, typ, err := Rewrite(strings.NewReader(m3u8Sample1), prefix)
+	require.NoError(t, err)
+	require.Equal(t, m3u8.MASTER, typ)
+	require.Equal(t, 8, strings.Count(string(data), "/proxy?u=http://qthttp.apple.com.edgesuite.net/"))
+
+	data, typ, err = Rewrite(strings.NewReader(m3u8Sample3), prefix)
+	require.NoError(t, err)
+	require.Equal(t, m3u8.MEDIA, typ)
+	require.Contains(t, string(data), `URI="/proxy?u=key.bin"`)
+	require.Contains(t, string(data), "/proxy?u=seg0.ts")
+	require.Contains(t, string(data), "/proxy?u=seg1.ts")
+	require.NotContains(t, string(data), "/proxy?u=/proxy")
+}
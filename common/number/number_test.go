@@ -294,3 +294,37 @@ func TestRequireFaceDetection(t *testing.T) {
 		assert.Equal(t, unit.want, RequiresFaceDetection(unit.orig), unit.orig)
 	}
 }
+
+func TestParse(t *testing.T) {
+	for _, unit := range []struct {
+		orig string
+		want Number
+	}{
+		{"", Number{}},
+		{"ABP-030", Number{Number: "ABP-030", Prefix: "ABP", Digits: "030", Pattern: PatternStandard}},
+		{"ABP-030-C.mp4", Number{Number: "ABP-030", Prefix: "ABP", Digits: "030", Subtitle: true, Pattern: PatternStandard}},
+		{"ABP-030C", Number{Number: "ABP-030", Prefix: "ABP", Digits: "030", Subtitle: true, Pattern: PatternStandard}},
+		{"ABP-030B", Number{Number: "ABP-030", Prefix: "ABP", Digits: "030", Part: 2, Pattern: PatternStandard}},
+		{"ABP-030-UC.mp4", Number{Number: "ABP-030", Prefix: "ABP", Digits: "030", Subtitle: true, Uncensored: true, Pattern: PatternStandard}},
+		{"rctd-461-C-cD4.mp4", Number{Number: "rctd-461", Prefix: "rctd", Digits: "461", Part: 4, Subtitle: true, Pattern: PatternStandard}},
+		{"hnd-993ch字幕", Number{Number: "hnd-993", Prefix: "hnd", Digits: "993", Subtitle: true, Pattern: PatternStandard}},
+		{"[98t.tv]vema-181-4k-C.mp4", Number{Number: "vema-181", Prefix: "vema", Digits: "181", Subtitle: true, Resolution: "4K", Pattern: PatternStandard}},
+		{"SSIS-329-C_1080P30FPS", Number{Number: "SSIS-329", Prefix: "SSIS", Digits: "329", Subtitle: true, Resolution: "1080p", Pattern: PatternStandard}},
+		{"SDDE-625_uncensored_leak_C_cd1.mp4", Number{Number: "SDDE-625", Prefix: "SDDE", Digits: "625", Part: 1, Subtitle: true, Uncensored: true, Pattern: PatternStandard}},
+		{"(無修正-流出) MXGS-247.mp4", Number{Number: "MXGS-247", Prefix: "MXGS", Digits: "247", Uncensored: true, Pattern: PatternStandard}},
+		{"118abp077", Number{Number: "118abp077", Prefix: "118abp", Digits: "077", Pattern: PatternStandard}},
+		{"133ARA-030-C 你好.mp4", Number{Number: "133ARA-030", Prefix: "133ARA", Digits: "030", Subtitle: true, Pattern: PatternShirouto}},
+		{"FC2-PPV-123456-C.mp4", Number{Number: "FC2-123456", Prefix: "FC2", Digits: "123456", Studio: "FC2", Subtitle: true, Pattern: PatternFC2}},
+		{"FC2PPV-123456-1", Number{Number: "FC2-123456", Prefix: "FC2", Digits: "123456", Studio: "FC2", Part: 1, Pattern: PatternFC2}},
+		{"carib-020317_001.mp4", Number{Number: "020317_001", Prefix: "020317", Digits: "001", Studio: "Caribbeancom", Uncensored: true, Pattern: PatternUncensored}},
+		{"020317-001-caribpr-1080p60fps.mp4", Number{Number: "020317-001", Prefix: "020317", Digits: "001", Studio: "CaribbeancomPR", Resolution: "1080p", Uncensored: true, Pattern: PatternUncensored}},
+		{"10musume-020317_01-CD2.iso", Number{Number: "020317_01", Prefix: "020317", Digits: "01", Studio: "10musume", Part: 2, Uncensored: true, Pattern: PatternUncensored}},
+		{"020317_001.mp4", Number{Number: "020317_001", Prefix: "020317", Digits: "001", Studio: "1Pondo", Uncensored: true, Pattern: PatternUncensored}},
+		{"Tokyo Hot n9001 FHD.mp4", Number{Number: "n9001", Prefix: "n", Digits: "9001", Studio: "TOKYO-HOT", Resolution: "1080p", Uncensored: true, Pattern: PatternUncensored}},
+		{"heyzo-1342", Number{Number: "heyzo-1342", Prefix: "heyzo", Digits: "1342", Studio: "HEYZO", Uncensored: true, Pattern: PatternUncensored}},
+		{"gcolle-847256", Number{Number: "gcolle-847256", Prefix: "gcolle", Digits: "847256", Studio: "Gcolle", Pattern: PatternSpecial}},
+		{"MIDV-111-C_X1080X.mp4", Number{Number: "MIDV-111", Prefix: "MIDV", Digits: "111", Subtitle: true, Resolution: "1080p", Pattern: PatternStandard}},
+	} {
+		assert.Equal(t, &unit.want, Parse(unit.orig), unit.orig)
+	}
+}
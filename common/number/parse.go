@@ -0,0 +1,232 @@
+package number
+
+import (
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Patterns of the parsed number.
+const (
+	PatternFC2        = "fc2"
+	PatternUncensored = "uncensored"
+	PatternSpecial    = "special"
+	PatternShirouto   = "shirouto"
+	PatternStandard   = "standard"
+)
+
+// Number is the structured result of parsing a filename.
+type Number struct {
+	// Number is the normalized number, same as Trim.
+	Number string `json:"number"`
+	// Prefix and Digits split the number, e.g.: ABP and 030.
+	Prefix string `json:"prefix,omitempty"`
+	Digits string `json:"digits,omitempty"`
+	// Studio is the provider name hinted by the filename, if any.
+	Studio string `json:"studio,omitempty"`
+	// Part is the part index starts from 1, or 0 if not split.
+	Part int `json:"part,omitempty"`
+	// Resolution is e.g.: 720p, 1080p or 4K.
+	Resolution string `json:"resolution,omitempty"`
+	Subtitle   bool   `json:"subtitle"`
+	Uncensored bool   `json:"uncensored"`
+	// Pattern is the kind of number matched, e.g.: fc2 or standard.
+	Pattern string `json:"pattern,omitempty"`
+}
+
+var (
+	parseDomainRe     = regexp.MustCompile(`(?i)([a-z\d]+\.(?:com|net|top|xyz|tv))(?:[^a-z\d]|$)`)
+	parseSuffixRe     = regexp.MustCompile(`^(?i)[-_.\s]*(uc|ch|cd\d{1,2}|(?:part|pt)\d{1,2}|c|[abd]|[-_]\d)(?:[^a-z\d]|$)`)
+	parseTagRe        = regexp.MustCompile(`^(?i)[-_.\s]*(dvd|iso|mkv|mp4|c?avi|\d*fps|whole|(f|hhb)?hd\d*|sd\d*|(?:360|480|720|1080|2160)[pi]|X1080X|uncensored|leak|[2468]k|[xh]26[45])+`)
+	parseMakerRe      = regexp.MustCompile(`(?i)(?:^|[^a-z\d])(carib(?:b?ean)?(?:com)?(pr)?|1?pond?o?|10mu(?:sume)?|paco(?:paco)?(?:mama)?|mura(?:mura)?|(tokyo[-_\s]?hot))(?:[^a-z]|$)`)
+	parseDigitsRe     = regexp.MustCompile(`^(.+?)[-_]?(\d+)$`)
+	parseResKRe       = regexp.MustCompile(`(?i)(?:^|[^a-z\d])([2468])k(?:[^a-z]|$)`)
+	parseResPRe       = regexp.MustCompile(`(?i)(?:^|\D)(2160|1080|720|480|360)[pix]`)
+	parseResFHDRe     = regexp.MustCompile(`(?i)(?:^|[^a-z])fhd(?:[^a-z]|$)`)
+	parseSubtitleRe   = regexp.MustCompile(`中文字幕|中字|字幕`)
+	parseUncensoredRe = regexp.MustCompile(`(?i)uncensored|leak(?:ed)?|無修正|无修正|無碼|无码|流出|破解`)
+	parseDateRe       = regexp.MustCompile(`^\d{6}([-_])(\d{2,3})$`)
+	parseTokyoHotRe   = regexp.MustCompile(`^(?i)(n|k|kb|cz|gedo|se)\d{2,4}$`)
+	parseStudioRe     = regexp.MustCompile(`^(?i)(heyzo|heydouga|kin8|h0930|c0930|h4610|mywife|gcolle|getchu|pcolle)[-_]`)
+)
+
+var studioPrefixes = map[string]string{
+	"heyzo":    "HEYZO",
+	"heydouga": "HeyDouga",
+	"kin8":     "KIN8",
+	"h0930":    "H0930",
+	"c0930":    "C0930",
+	"h4610":    "H4610",
+	"mywife":   "MYWIFE",
+	"gcolle":   "Gcolle",
+	"getchu":   "Getchu",
+	"pcolle":   "Pcolle",
+}
+
+// Parse parses the filename into a structured number, unlike Trim,
+// it also keeps the part, subtitle, resolution and other signals.
+func Parse(filename string) *Number {
+	n := &Number{Number: Trim(filename)}
+	if n.Number == "" {
+		return n
+	}
+
+	base := filename
+	if ext := path.Ext(base); len(ext) < 7 {
+		base = base[:len(base)-len(ext)] // trim extension
+	}
+	base = parseDomainRe.ReplaceAllString(base, "")
+
+	// parse suffixes right after the number.
+	key := n.Number
+	if IsFC2(n.Number) {
+		key = strings.TrimLeft(n.Number[strings.IndexAny(n.Number, "-_")+1:], "-_")
+	}
+	if i := indexFold(base, key); i >= 0 {
+		n.parseSuffixes(base[i+len(key):])
+	}
+
+	// parse flags from the whole filename.
+	n.Resolution = parseResolution(base)
+	if parseSubtitleRe.MatchString(base) {
+		n.Subtitle = true
+	}
+	if parseUncensoredRe.MatchString(base) || IsUncensored(n.Number) {
+		n.Uncensored = true
+	}
+
+	if ss := parseDigitsRe.FindStringSubmatch(n.Number); len(ss) == 3 && !isDigits(n.Number) {
+		n.Prefix, n.Digits = strings.TrimRight(ss[1], "-_"), ss[2]
+	}
+	n.Studio = parseStudio(base, n.Number)
+
+	switch {
+	case IsFC2(n.Number):
+		n.Pattern = PatternFC2
+	case IsUncensored(n.Number):
+		n.Pattern = PatternUncensored
+	case IsSpecial(n.Number):
+		n.Pattern = PatternSpecial
+	case shiroutoRe.MatchString(n.Number):
+		n.Pattern = PatternShirouto
+	case n.Prefix != "" && n.Digits != "":
+		n.Pattern = PatternStandard
+	}
+	return n
+}
+
+// parseSuffixes consumes part, subtitle and tag suffixes one by one.
+func (n *Number) parseSuffixes(s string) {
+	for s != "" {
+		if loc := parseSuffixRe.FindStringSubmatchIndex(s); loc != nil {
+			token := strings.ToLower(strings.TrimLeft(s[loc[2]:loc[3]], "-_"))
+			switch {
+			case token == "c" || token == "ch":
+				n.Subtitle = true
+			case token == "uc":
+				n.Subtitle, n.Uncensored = true, true
+			case token == "a", token == "b", token == "d":
+				n.Part = int(token[0]-'a') + 1
+			default:
+				if part, err := strconv.Atoi(strings.TrimLeft(token, "cdpartp")); err == nil {
+					n.Part = part
+				}
+			}
+			s = s[loc[3]:]
+			continue
+		}
+		if loc := parseTagRe.FindStringIndex(s); loc != nil && loc[1] > 0 {
+			s = s[loc[1]:]
+			continue
+		}
+		break
+	}
+}
+
+func parseResolution(s string) string {
+	if ss := parseResKRe.FindStringSubmatch(s); len(ss) == 2 {
+		return ss[1] + "K"
+	}
+	if ss := parseResPRe.FindStringSubmatch(s); len(ss) == 2 {
+		if ss[1] == "2160" {
+			return "4K"
+		}
+		return ss[1] + "p"
+	}
+	if parseResFHDRe.MatchString(s) {
+		return "1080p"
+	}
+	return ""
+}
+
+func parseStudio(base, number string) string {
+	if IsFC2(number) {
+		return "FC2"
+	}
+	if ss := parseStudioRe.FindStringSubmatch(number); len(ss) == 2 {
+		return studioPrefixes[strings.ToLower(ss[1])]
+	}
+	if strings.HasPrefix(strings.ToLower(number), "xxx-av") {
+		return "XXX-AV"
+	}
+	// makers are trimmed from date-like or Tokyo-Hot numbers only.
+	if !parseDateRe.MatchString(number) && !parseTokyoHotRe.MatchString(number) {
+		return ""
+	}
+	if ss := parseMakerRe.FindStringSubmatch(base); len(ss) == 4 {
+		maker := strings.ToLower(ss[1])
+		switch {
+		case ss[3] != "":
+			return "TOKYO-HOT"
+		case strings.HasPrefix(maker, "carib") && ss[2] != "":
+			return "CaribbeancomPR"
+		case strings.HasPrefix(maker, "carib"):
+			return "Caribbeancom"
+		case strings.HasPrefix(maker, "10mu"):
+			return "10musume"
+		case strings.HasPrefix(maker, "paco"):
+			return "PACOPACOMAMA"
+		case strings.HasPrefix(maker, "mura"):
+			return "MURAMURA"
+		default:
+			return "1Pondo"
+		}
+	}
+	// otherwise, guess by the date-like numbers.
+	if ss := parseDateRe.FindStringSubmatch(number); len(ss) == 3 {
+		switch {
+		case ss[1] == "-" && len(ss[2]) == 3:
+			return "Caribbeancom"
+		case ss[1] == "_" && len(ss[2]) == 2:
+			return "10musume"
+		case ss[1] == "_":
+			return "1Pondo"
+		}
+	}
+	if parseTokyoHotRe.MatchString(number) {
+		return "TOKYO-HOT"
+	}
+	return ""
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// indexFold returns the index of the first case-insensitive match of
+// the key in s, or -1 if not present. The key is not a fixed pattern,
+// so it's matched without compiling a regexp on every parse.
+func indexFold(s, key string) int {
+	for i := 0; i+len(key) <= len(s); i++ {
+		if strings.EqualFold(s[i:i+len(key)], key) {
+			return i
+		}
+	}
+	return -1
+}
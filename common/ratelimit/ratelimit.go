@@ -0,0 +1,296 @@
+package ratelimit
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// DefaultRegistry is the process-wide registry shared by all
+// Fetchers and scrapers, so that limits are applied per host
+// regardless of which client makes the request.
+var DefaultRegistry = NewRegistry()
+
+// slowWaitThreshold is the queueing delay above which a log is printed.
+const slowWaitThreshold = 3 * time.Second
+
+var logger = log.New(os.Stdout, "[RATELIMIT]\u0020", log.LstdFlags)
+
+type Config struct {
+	// Rate is the number of requests allowed per second,
+	// zero or negative value means unlimited.
+	Rate float64 `json:"rate"`
+
+	// Burst is the max number of requests allowed at once,
+	// it defaults to 1 if Rate is set.
+	Burst int `json:"burst"`
+
+	// Concurrency is the max number of in-flight requests,
+	// zero or negative value means unlimited.
+	Concurrency int `json:"concurrency"`
+}
+
+func (cfg Config) IsZero() bool {
+	return cfg.Rate <= 0 && cfg.Concurrency <= 0
+}
+
+type Stats struct {
+	Requests uint64 `json:"requests"`
+	Delayed  uint64 `json:"delayed"`
+	InFlight int    `json:"in_flight"`
+	// Durations are left to consumers to present in readable
+	// units, as the default JSON encoding is nanoseconds.
+	TotalWait time.Duration `json:"-"`
+	MaxWait   time.Duration `json:"-"`
+}
+
+// Limiter combines a token-bucket rate limiter
+// and a concurrency semaphore for a single host.
+type Limiter struct {
+	config  Config
+	limiter *rate.Limiter
+	sem     chan struct{}
+
+	mu    sync.Mutex
+	stats Stats
+}
+
+func NewLimiter(cfg Config) *Limiter {
+	l := &Limiter{config: cfg}
+	if cfg.Rate > 0 {
+		burst := cfg.Burst
+		if burst <= 0 {
+			burst = 1
+		}
+		l.limiter = rate.NewLimiter(rate.Limit(cfg.Rate), burst)
+	}
+	if cfg.Concurrency > 0 {
+		l.sem = make(chan struct{}, cfg.Concurrency)
+	}
+	return l
+}
+
+func (l *Limiter) Config() Config { return l.config }
+
+func (l *Limiter) Stats() Stats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.stats
+}
+
+// Wait blocks until a request is allowed, it returns the queueing
+// delay and a release func which must be called once the request
+// is done.
+func (l *Limiter) Wait(ctx context.Context) (release func(), wait time.Duration, err error) {
+	start := time.Now()
+	if l.sem != nil {
+		select {
+		case l.sem <- struct{}{}:
+		case <-ctx.Done():
+			return nil, time.Since(start), ctx.Err()
+		}
+	}
+	if l.limiter != nil {
+		if err = l.limiter.Wait(ctx); err != nil {
+			if l.sem != nil {
+				<-l.sem
+			}
+			return nil, time.Since(start), err
+		}
+	}
+	wait = time.Since(start)
+
+	l.mu.Lock()
+	l.stats.Requests++
+	l.stats.InFlight++
+	l.stats.TotalWait += wait
+	if wait > time.Millisecond {
+		l.stats.Delayed++
+	}
+	if wait > l.stats.MaxWait {
+		l.stats.MaxWait = wait
+	}
+	l.mu.Unlock()
+
+	var once sync.Once
+	release = func() {
+		once.Do(func() {
+			l.mu.Lock()
+			l.stats.InFlight--
+			l.mu.Unlock()
+			if l.sem != nil {
+				<-l.sem
+			}
+		})
+	}
+	return release, wait, nil
+}
+
+// Registry holds limiters keyed by hostname, a limiter may be shared
+// by a group of hosts, e.g. the site, mirrors and image hosts of a
+// provider, so that they are limited as a whole.
+type Registry struct {
+	mu       sync.RWMutex
+	limiters map[string]*Limiter
+}
+
+func NewRegistry() *Registry {
+	return &Registry{limiters: make(map[string]*Limiter)}
+}
+
+// Set sets limits for the host, a zero config removes the limits.
+func (r *Registry) Set(host string, cfg Config) {
+	r.SetGroup([]string{host}, cfg)
+}
+
+// SetGroup sets limits shared by the hosts, a zero config removes the limits.
+func (r *Registry) SetGroup(hosts []string, cfg Config) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var l *Limiter
+	if !cfg.IsZero() {
+		l = NewLimiter(cfg)
+	}
+	for _, host := range hosts {
+		host = strings.ToLower(host)
+		if l == nil {
+			delete(r.limiters, host)
+		} else {
+			r.limiters[host] = l
+		}
+	}
+}
+
+// SetDefault sets limits for the host only if it's not set yet.
+func (r *Registry) SetDefault(host string, cfg Config) {
+	r.SetDefaultGroup([]string{host}, cfg)
+}
+
+// SetDefaultGroup sets limits shared by the hosts which are not set yet,
+// they join the limiter of the others if any of the hosts is set.
+func (r *Registry) SetDefaultGroup(hosts []string, cfg Config) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var l *Limiter
+	for _, host := range hosts {
+		if l = r.limiters[strings.ToLower(host)]; l != nil {
+			break
+		}
+	}
+	if l == nil {
+		if cfg.IsZero() {
+			return
+		}
+		l = NewLimiter(cfg)
+	}
+	for _, host := range hosts {
+		host = strings.ToLower(host)
+		if _, ok := r.limiters[host]; !ok {
+			r.limiters[host] = l
+		}
+	}
+}
+
+// Config returns the limits config of the host.
+func (r *Registry) Config(host string) (Config, bool) {
+	if l := r.Limiter(host); l != nil {
+		return l.Config(), true
+	}
+	return Config{}, false
+}
+
+// Limiter returns the limiter of the host, or nil if unlimited.
+func (r *Registry) Limiter(host string) *Limiter {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.limiters[strings.ToLower(host)]
+}
+
+// Hosts returns the sorted hosts sharing the limiter of the host.
+func (r *Registry) Hosts(host string) (hosts []string) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	l := r.limiters[strings.ToLower(host)]
+	if l == nil {
+		return nil
+	}
+	for h, other := range r.limiters {
+		if other == l {
+			hosts = append(hosts, h)
+		}
+	}
+	slices.Sort(hosts)
+	return
+}
+
+// Stats returns stats of all limiters, keyed by
+// the first sorted host of each limiter.
+func (r *Registry) Stats() map[string]Stats {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	keys := make(map[*Limiter]string, len(r.limiters))
+	for host, l := range r.limiters {
+		if key, ok := keys[l]; !ok || host < key {
+			keys[l] = host
+		}
+	}
+	stats := make(map[string]Stats, len(keys))
+	for l, key := range keys {
+		stats[key] = l.Stats()
+	}
+	return stats
+}
+
+// Wrap returns a http.RoundTripper which applies the registry
+// limits to all requests before sending them to next.
+func (r *Registry) Wrap(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &transport{registry: r, next: next}
+}
+
+type transport struct {
+	registry *Registry
+	next     http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	l := t.registry.Limiter(req.URL.Hostname())
+	if l == nil /* unlimited */ {
+		return t.next.RoundTrip(req)
+	}
+	release, wait, err := l.Wait(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	if wait >= slowWaitThreshold {
+		logger.Printf("Request to %s queued for %s", req.URL.Hostname(), wait)
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	// hold the slot until the body is fully consumed.
+	resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+}
+
+func (r *releaseOnClose) Close() error {
+	defer r.release()
+	return r.ReadCloser.Close()
+}
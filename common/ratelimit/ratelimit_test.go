@@ -0,0 +1,141 @@
+package ratelimit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter_Rate(t *testing.T) {
+	l := NewLimiter(Config{Rate: 20, Burst: 1})
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		release, _, err := l.Wait(context.Background())
+		require.NoError(t, err)
+		release()
+	}
+	// first token is free, others take 50ms each.
+	assert.GreaterOrEqual(t, time.Since(start), 180*time.Millisecond)
+
+	stats := l.Stats()
+	assert.EqualValues(t, 5, stats.Requests)
+	assert.EqualValues(t, 4, stats.Delayed)
+	assert.Equal(t, 0, stats.InFlight)
+	assert.Greater(t, stats.MaxWait, time.Duration(0))
+}
+
+func TestLimiter_Concurrency(t *testing.T) {
+	l := NewLimiter(Config{Concurrency: 1})
+
+	release, _, err := l.Wait(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, l.Stats().InFlight)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, _, err = l.Wait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	release()
+	release() // release twice should be safe.
+	release, _, err = l.Wait(context.Background())
+	require.NoError(t, err)
+	release()
+	assert.Equal(t, 0, l.Stats().InFlight)
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	r.SetDefault("Example.com", Config{Concurrency: 2})
+	r.SetDefault("example.com", Config{Concurrency: 5}) // ignored.
+
+	cfg, ok := r.Config("EXAMPLE.COM")
+	require.True(t, ok)
+	assert.Equal(t, 2, cfg.Concurrency)
+
+	r.Set("example.com", Config{Rate: 1})
+	cfg, _ = r.Config("example.com")
+	assert.Equal(t, Config{Rate: 1}, cfg)
+
+	r.Set("example.com", Config{})
+	_, ok = r.Config("example.com")
+	assert.False(t, ok)
+}
+
+func TestRegistry_Group(t *testing.T) {
+	r := NewRegistry()
+	r.SetGroup([]string{"example.com", "IMG.example.com"}, Config{Concurrency: 2})
+	l := r.Limiter("example.com")
+	require.NotNil(t, l)
+	assert.Same(t, l, r.Limiter("img.example.com"))
+	assert.Equal(t, []string{"example.com", "img.example.com"}, r.Hosts("img.example.com"))
+
+	// new hosts join the existing limiter, set ones are kept.
+	r.SetDefaultGroup([]string{"example.com", "mirror.example.com"}, Config{Concurrency: 5})
+	assert.Same(t, l, r.Limiter("mirror.example.com"))
+	assert.Equal(t, 2, l.Config().Concurrency)
+
+	// stats are keyed by the first host of each limiter.
+	stats := r.Stats()
+	assert.Len(t, stats, 1)
+	assert.Contains(t, stats, "example.com")
+
+	r.SetGroup([]string{"example.com", "img.example.com", "mirror.example.com"}, Config{})
+	assert.Empty(t, r.Stats())
+}
+
+func TestRegistry_Wrap(t *testing.T) {
+	var (
+		inFlight    atomic.Int32
+		maxInFlight atomic.Int32
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	r := NewRegistry()
+	r.Set(u.Hostname(), Config{Concurrency: 2})
+	client := &http.Client{Transport: r.Wrap(nil)}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(server.URL)
+			if !assert.NoError(t, err) {
+				return
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(2))
+	stats := r.Stats()[u.Hostname()]
+	assert.EqualValues(t, 8, stats.Requests)
+	assert.Equal(t, 0, stats.InFlight)
+}
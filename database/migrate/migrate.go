@@ -0,0 +1,360 @@
+package migrate
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const TableName = "schema_migrations"
+
+// Annotations to wrap statements containing semicolons, e.g. triggers.
+const (
+	statementBegin = "-- +migrate StatementBegin"
+	statementEnd   = "-- +migrate StatementEnd"
+)
+
+var ErrSchemaTooNew = errors.New("database schema is newer than supported")
+
+//go:embed migrations
+var migrationsFS embed.FS
+
+// migrations are the embedded migrations by driver name.
+var migrations = mustLoad(migrationsFS, "migrations")
+
+var migrationFileRe = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is a versioned schema change with its up and down SQL.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status is the status of a migration, either embedded or applied.
+type Status struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+type schemaMigration struct {
+	Version   int64 `gorm:"primaryKey"`
+	Name      string
+	AppliedAt time.Time
+}
+
+func (*schemaMigration) TableName() string {
+	return TableName
+}
+
+// Migrator applies the embedded migrations of the DB driver, and
+// records the applied versions in the schema_migrations table.
+type Migrator struct {
+	db         *gorm.DB
+	migrations []*Migration
+}
+
+func New(db *gorm.DB) *Migrator {
+	return &Migrator{
+		db:         db,
+		migrations: migrations[db.Config.Dialector.Name()],
+	}
+}
+
+// Latest returns the latest version known by this binary.
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Version returns the current version of the DB schema, 0 if none.
+func (m *Migrator) Version() (int64, error) {
+	applied, err := m.applied()
+	if err != nil || len(applied) == 0 {
+		return 0, err
+	}
+	return applied[len(applied)-1].Version, nil
+}
+
+// Check returns ErrSchemaTooNew if the DB schema is newer than the
+// latest migration, i.e. it was migrated by a newer binary.
+func (m *Migrator) Check() error {
+	if err := m.supported(); err != nil {
+		return err
+	}
+	version, err := m.Version()
+	if err != nil {
+		return err
+	}
+	if version > m.Latest() {
+		return fmt.Errorf("%w: version %d > %d", ErrSchemaTooNew, version, m.Latest())
+	}
+	return nil
+}
+
+// Pending returns the migrations not applied yet in version order.
+func (m *Migrator) Pending() ([]*Migration, error) {
+	applied, err := m.appliedVersions()
+	if err != nil {
+		return nil, err
+	}
+	var pending []*Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+// Up applies all pending migrations, each in its own transaction,
+// and returns the number of migrations applied. Note that MySQL
+// commits DDL statements implicitly, so they can't be rolled back.
+func (m *Migrator) Up() (int, error) {
+	if err := m.Check(); err != nil {
+		return 0, err
+	}
+	if err := m.ensureTable(); err != nil {
+		return 0, err
+	}
+	pending, err := m.Pending()
+	if err != nil {
+		return 0, err
+	}
+	for i, migration := range pending {
+		if err := m.db.Transaction(func(tx *gorm.DB) error {
+			if err := execScript(tx, migration.Up); err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigration{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				AppliedAt: time.Now(),
+			}).Error
+		}); err != nil {
+			return i, fmt.Errorf("migrate up %s: %w", migration, err)
+		}
+	}
+	return len(pending), nil
+}
+
+// Down rolls back the last applied migrations by steps, and returns
+// the number of migrations rolled back.
+func (m *Migrator) Down(steps int) (int, error) {
+	if err := m.supported(); err != nil {
+		return 0, err
+	}
+	applied, err := m.applied()
+	if err != nil {
+		return 0, err
+	}
+	slices.Reverse(applied)
+	for i, record := range applied[:min(steps, len(applied))] {
+		migration := m.lookup(record.Version)
+		if migration == nil {
+			return i, fmt.Errorf("%w: unknown version %d", ErrSchemaTooNew, record.Version)
+		}
+		if err := m.db.Transaction(func(tx *gorm.DB) error {
+			if err := execScript(tx, migration.Down); err != nil {
+				return err
+			}
+			return tx.Delete(&schemaMigration{Version: migration.Version}).Error
+		}); err != nil {
+			return i, fmt.Errorf("migrate down %s: %w", migration, err)
+		}
+	}
+	return min(steps, len(applied)), nil
+}
+
+// Status returns the status of all embedded and applied migrations.
+func (m *Migrator) Status() ([]*Status, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	statuses := make(map[int64]*Status)
+	for _, migration := range m.migrations {
+		statuses[migration.Version] = &Status{
+			Version: migration.Version,
+			Name:    migration.Name,
+		}
+	}
+	for _, record := range applied {
+		s, ok := statuses[record.Version]
+		if !ok /* applied by a newer binary */ {
+			s = &Status{Version: record.Version, Name: record.Name}
+			statuses[record.Version] = s
+		}
+		s.Applied, s.AppliedAt = true, &record.AppliedAt
+	}
+	results := make([]*Status, 0, len(statuses))
+	for _, s := range statuses {
+		results = append(results, s)
+	}
+	slices.SortFunc(results, func(a, b *Status) int {
+		return int(a.Version - b.Version)
+	})
+	return results, nil
+}
+
+func (m *Migrator) supported() error {
+	if len(m.migrations) == 0 {
+		return fmt.Errorf("unsupported DB type: %s", m.db.Config.Dialector.Name())
+	}
+	return nil
+}
+
+func (m *Migrator) ensureTable() error {
+	return m.db.Exec(`CREATE TABLE IF NOT EXISTS ` + TableName + ` (
+	  version BIGINT NOT NULL PRIMARY KEY,
+	  name TEXT NOT NULL,
+	  applied_at TIMESTAMP NOT NULL
+	)`).Error
+}
+
+// applied returns the applied migrations in version order, without
+// creating the table, so that it's safe to call on a read-only DB.
+func (m *Migrator) applied() ([]*schemaMigration, error) {
+	if !m.db.Migrator().HasTable(TableName) {
+		return nil, nil
+	}
+	var records []*schemaMigration
+	if err := m.db.Order("version").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func (m *Migrator) appliedVersions() (map[int64]struct{}, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	versions := make(map[int64]struct{}, len(applied))
+	for _, record := range applied {
+		versions[record.Version] = struct{}{}
+	}
+	return versions, nil
+}
+
+func (m *Migrator) lookup(version int64) *Migration {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration
+		}
+	}
+	return nil
+}
+
+func (m *Migration) String() string {
+	return fmt.Sprintf("%04d_%s", m.Version, m.Name)
+}
+
+func execScript(tx *gorm.DB, script string) error {
+	for _, stmt := range splitStatements(script) {
+		if err := tx.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitStatements splits the script by semicolons at the end of lines,
+// except for the statements wrapped between StatementBegin and End.
+func splitStatements(script string) []string {
+	var (
+		stmts   []string
+		buf     strings.Builder
+		inBlock bool
+	)
+	flush := func() {
+		if stmt := strings.TrimSpace(buf.String()); stmt != "" {
+			stmts = append(stmts, stmt)
+		}
+		buf.Reset()
+	}
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == statementBegin:
+			flush()
+			inBlock = true
+			continue
+		case trimmed == statementEnd:
+			flush()
+			inBlock = false
+			continue
+		case !inBlock && strings.HasPrefix(trimmed, "--"):
+			continue // skip comments.
+		}
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+		if !inBlock && strings.HasSuffix(trimmed, ";") {
+			flush()
+		}
+	}
+	flush()
+	return stmts
+}
+
+// mustLoad loads migrations from dir/<driver>/<version>_<name>.<up|down>.sql,
+// every version must have both up and down files.
+func mustLoad(fsys fs.FS, dir string) map[string][]*Migration {
+	drivers, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+	results := make(map[string][]*Migration)
+	for _, driver := range drivers {
+		entries, err := fs.ReadDir(fsys, path.Join(dir, driver.Name()))
+		if err != nil {
+			panic(err)
+		}
+		versions := make(map[int64]*Migration)
+		for _, entry := range entries {
+			ss := migrationFileRe.FindStringSubmatch(entry.Name())
+			if len(ss) != 4 {
+				panic(fmt.Sprintf("invalid migration file: %s/%s", driver.Name(), entry.Name()))
+			}
+			version, _ := strconv.ParseInt(ss[1], 10, 64)
+			data, err := fs.ReadFile(fsys, path.Join(dir, driver.Name(), entry.Name()))
+			if err != nil {
+				panic(err)
+			}
+			migration, ok := versions[version]
+			if !ok {
+				migration = &Migration{Version: version, Name: ss[2]}
+				versions[version] = migration
+			}
+			if ss[3] == "up" {
+				migration.Up = string(data)
+			} else {
+				migration.Down = string(data)
+			}
+		}
+		for _, migration := range versions {
+			if migration.Up == "" || migration.Down == "" {
+				panic(fmt.Sprintf("incomplete migration: %s/%s", driver.Name(), migration))
+			}
+			results[driver.Name()] = append(results[driver.Name()], migration)
+		}
+		slices.SortFunc(results[driver.Name()], func(a, b *Migration) int {
+			return int(a.Version - b.Version)
+		})
+	}
+	return results
+}
@@ -0,0 +1,157 @@
+package migrate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/metatube-community/metatube-sdk-go/database"
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+func openTestDB(t *testing.T) *gorm.DB {
+	db, err := database.Open(&database.Config{
+		DSN:                  ":memory:",
+		MaxOpenConns:         1, // keep the memory DB.
+		DisableAutomaticPing: true,
+		LogLevel:             logger.Warn,
+	})
+	require.NoError(t, err)
+	return db
+}
+
+func TestSplitStatements(t *testing.T) {
+	for _, unit := range []struct {
+		script string
+		want   []string
+	}{
+		{"", nil},
+		{"-- comment only\n", nil},
+		{"CREATE TABLE a (x int);\nCREATE TABLE b (y int);\n", []string{
+			"CREATE TABLE a (x int);",
+			"CREATE TABLE b (y int);",
+		}},
+		{"-- comment\nCREATE TABLE a (\n  x int\n);\n", []string{
+			"CREATE TABLE a (\n  x int\n);",
+		}},
+		{"DROP TABLE a;\n" + statementBegin + "\nCREATE TRIGGER t BEGIN\n  DELETE FROM a;\nEND;\n" + statementEnd + "\nDROP TABLE b;", []string{
+			"DROP TABLE a;",
+			"CREATE TRIGGER t BEGIN\n  DELETE FROM a;\nEND;",
+			"DROP TABLE b;",
+		}},
+	} {
+		assert.Equal(t, unit.want, splitStatements(unit.script))
+	}
+}
+
+func TestMigrations(t *testing.T) {
+	for _, driver := range []string{database.Sqlite, database.Postgres, database.MySQL} {
+		ms := migrations[driver]
+		require.NotEmpty(t, ms, driver)
+		// both drivers must have the same versions.
+		require.Len(t, ms, len(migrations[database.Sqlite]), driver)
+		for i, m := range ms {
+			assert.Equal(t, migrations[database.Sqlite][i].Version, m.Version)
+			assert.Equal(t, migrations[database.Sqlite][i].Name, m.Name)
+		}
+	}
+}
+
+func TestMigrator_UpDown(t *testing.T) {
+	m := New(openTestDB(t))
+
+	version, err := m.Version()
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), version)
+
+	n, err := m.Up()
+	require.NoError(t, err)
+	assert.Equal(t, len(m.migrations), n)
+
+	version, err = m.Version()
+	require.NoError(t, err)
+	assert.Equal(t, m.Latest(), version)
+	assert.True(t, m.db.Migrator().HasColumn(&model.NumberStatus{}, "gaps"))
+
+	// nothing to apply again.
+	n, err = m.Up()
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	n, err = m.Down(1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.False(t, m.db.Migrator().HasTable(&model.MatchJobItem{}))
+	assert.True(t, m.db.Migrator().HasColumn(&model.MatchJob{}, "items"))
+	assert.True(t, m.db.Migrator().HasColumn(&model.FeedCheckpoint{}, "resume_page"))
+
+	statuses, err := m.Status()
+	require.NoError(t, err)
+	require.Len(t, statuses, len(m.migrations))
+	assert.True(t, statuses[0].Applied)
+	assert.False(t, statuses[len(statuses)-1].Applied)
+
+	n, err = m.Down(100)
+	require.NoError(t, err)
+	assert.Equal(t, len(m.migrations)-1, n)
+	assert.False(t, m.db.Migrator().HasTable(&model.MovieInfo{}))
+
+	n, err = m.Up()
+	require.NoError(t, err)
+	assert.Equal(t, len(m.migrations), n)
+}
+
+func TestMigrator_Adopt(t *testing.T) {
+	db := openTestDB(t)
+	// schema created by gorm auto migration before.
+	require.NoError(t, db.AutoMigrate(&model.MovieInfo{}, &model.Maker{}, &model.Series{}))
+	require.NoError(t, db.Create(&model.MovieInfo{
+		ID:       "abp00001",
+		Provider: "FANZA",
+		Maker:    "プレステージ",
+		Series:   "ABP",
+	}).Error)
+
+	m := New(db)
+	_, err := m.Up()
+	require.NoError(t, err)
+
+	// collections are backfilled.
+	var makers []*model.Maker
+	require.NoError(t, db.Find(&makers).Error)
+	require.Len(t, makers, 1)
+	assert.Equal(t, "プレステージ", makers[0].Name)
+
+	var series []*model.Series
+	require.NoError(t, db.Find(&series).Error)
+	require.Len(t, series, 1)
+	assert.Equal(t, "ABP", series[0].Name)
+}
+
+func TestMigrator_Check(t *testing.T) {
+	m := New(openTestDB(t))
+	_, err := m.Up()
+	require.NoError(t, err)
+	require.NoError(t, m.Check())
+
+	// migrated by a newer binary.
+	require.NoError(t, m.db.Create(&schemaMigration{
+		Version: m.Latest() + 1,
+		Name:    "future",
+	}).Error)
+	assert.ErrorIs(t, m.Check(), ErrSchemaTooNew)
+
+	_, err = m.Up()
+	assert.ErrorIs(t, err, ErrSchemaTooNew)
+
+	_, err = m.Down(1)
+	assert.ErrorIs(t, err, ErrSchemaTooNew)
+
+	statuses, err := m.Status()
+	require.NoError(t, err)
+	assert.Equal(t, "future", statuses[len(statuses)-1].Name)
+	assert.True(t, statuses[len(statuses)-1].Applied)
+}
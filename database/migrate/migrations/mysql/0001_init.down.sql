@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS `feed_checkpoints`;
+DROP TABLE IF EXISTS `series`;
+DROP TABLE IF EXISTS `makers`;
+DROP TABLE IF EXISTS `provider_settings`;
+DROP TABLE IF EXISTS `provider_sessions`;
+DROP TABLE IF EXISTS `movie_reviews`;
+DROP TABLE IF EXISTS `actor_metadata`;
+DROP TABLE IF EXISTS `movie_metadata`;
@@ -0,0 +1,118 @@
+-- Requires MySQL 8.0+, for the collation and the ngram parser.
+--
+-- Tables use utf8mb4_0900_as_ci, which is accent-sensitive and case-
+-- insensitive, the same as the nocase collation in Postgres, so that
+-- plain comparisons are case-insensitive.
+--
+-- Stopwords are disabled for the FULLTEXT indexes created in this
+-- session, otherwise the ngram parser skips any token containing a
+-- stopword, e.g. all tokens containing the letter "a".
+SET SESSION innodb_ft_enable_stopword = OFF;
+
+CREATE TABLE IF NOT EXISTS `movie_metadata` (
+  `id` varchar(255) NOT NULL,
+  `number` varchar(255),
+  `title` text,
+  `summary` mediumtext,
+  `provider` varchar(255) NOT NULL,
+  `homepage` text,
+  `director` text,
+  `actors` json,
+  `thumb_url` text,
+  `big_thumb_url` text,
+  `cover_url` text,
+  `big_cover_url` text,
+  `preview_video_url` text,
+  `preview_video_hls_url` text,
+  `preview_images` json,
+  `maker` varchar(255),
+  `label` varchar(255),
+  `series` varchar(255),
+  `genres` json,
+  `score` double,
+  `runtime` bigint,
+  `release_date` date,
+  `created_at` datetime(3),
+  `updated_at` datetime(3),
+  PRIMARY KEY (`id`, `provider`),
+  INDEX `idx_movie_metadata_number` (`number`),
+  FULLTEXT INDEX `idx_movie_metadata_fulltext` (`title`, `summary`) WITH PARSER ngram
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_as_ci;
+
+CREATE TABLE IF NOT EXISTS `actor_metadata` (
+  `id` varchar(255) NOT NULL,
+  `name` varchar(255),
+  `provider` varchar(255) NOT NULL,
+  `homepage` text,
+  `summary` mediumtext,
+  `hobby` text,
+  `skill` text,
+  `blood_type` varchar(255),
+  `cup_size` varchar(255),
+  `measurements` varchar(255),
+  `nationality` varchar(255),
+  `height` bigint,
+  `aliases` json,
+  `images` json,
+  `birthday` date,
+  `debut_date` date,
+  `created_at` datetime(3),
+  `updated_at` datetime(3),
+  PRIMARY KEY (`id`, `provider`),
+  INDEX `idx_actor_metadata_name` (`name`),
+  FULLTEXT INDEX `idx_actor_metadata_fulltext` (`name`) WITH PARSER ngram
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_as_ci;
+
+CREATE TABLE IF NOT EXISTS `movie_reviews` (
+  `id` varchar(255) NOT NULL,
+  `provider` varchar(255) NOT NULL,
+  `reviews` json,
+  `created_at` datetime(3),
+  `updated_at` datetime(3),
+  PRIMARY KEY (`id`, `provider`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_as_ci;
+
+CREATE TABLE IF NOT EXISTS `provider_sessions` (
+  `provider` varchar(255) NOT NULL,
+  `cookies` json,
+  `created_at` datetime(3),
+  `updated_at` datetime(3),
+  PRIMARY KEY (`provider`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_as_ci;
+
+CREATE TABLE IF NOT EXISTS `provider_settings` (
+  `type` varchar(255) NOT NULL,
+  `name` varchar(255) NOT NULL,
+  `enabled` boolean,
+  `priority` double,
+  `timeout` bigint,
+  `config` json,
+  `created_at` datetime(3),
+  `updated_at` datetime(3),
+  PRIMARY KEY (`type`, `name`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_as_ci;
+
+CREATE TABLE IF NOT EXISTS `makers` (
+  `name` varchar(255) NOT NULL,
+  `created_at` datetime(3),
+  `updated_at` datetime(3),
+  PRIMARY KEY (`name`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_as_ci;
+
+CREATE TABLE IF NOT EXISTS `series` (
+  `name` varchar(255) NOT NULL,
+  `maker` varchar(255) NOT NULL,
+  `created_at` datetime(3),
+  `updated_at` datetime(3),
+  PRIMARY KEY (`name`, `maker`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_as_ci;
+
+CREATE TABLE IF NOT EXISTS `feed_checkpoints` (
+  `provider` varchar(255) NOT NULL,
+  `last_id` varchar(255),
+  `last_release_date` date,
+  `ingested` bigint,
+  `created_at` datetime(3),
+  `updated_at` datetime(3),
+  PRIMARY KEY (`provider`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_as_ci;
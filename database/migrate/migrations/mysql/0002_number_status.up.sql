@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS `number_status` (
+  `number_prefix` varchar(255) NOT NULL,
+  `status` bigint,
+  `created_at` datetime(3),
+  `updated_at` datetime(3),
+  PRIMARY KEY (`number_prefix`),
+  INDEX `idx_number_status_status` (`status`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_as_ci;
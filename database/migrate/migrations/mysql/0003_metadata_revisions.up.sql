@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS `metadata_revisions` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `kind` varchar(16),
+  `provider` varchar(255),
+  `record_id` varchar(255),
+  `source` varchar(32),
+  `changes` json,
+  `data` json,
+  `created_at` datetime(3),
+  PRIMARY KEY (`id`),
+  INDEX `idx_metadata_revisions_record` (`kind`, `provider`, `record_id`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_as_ci;
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS `movie_overrides` (
+  `id` varchar(255) NOT NULL,
+  `provider` varchar(255) NOT NULL,
+  `fields` json,
+  `created_at` datetime(3),
+  `updated_at` datetime(3),
+  PRIMARY KEY (`id`, `provider`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_as_ci;
+
+CREATE TABLE IF NOT EXISTS `actor_overrides` (
+  `id` varchar(255) NOT NULL,
+  `provider` varchar(255) NOT NULL,
+  `fields` json,
+  `created_at` datetime(3),
+  `updated_at` datetime(3),
+  PRIMARY KEY (`id`, `provider`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_as_ci;
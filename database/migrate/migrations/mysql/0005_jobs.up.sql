@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS `jobs` (
+  `name` varchar(255) NOT NULL,
+  `type` varchar(64),
+  `schedule` varchar(255),
+  `payload` json,
+  `max_attempts` bigint,
+  `paused` boolean,
+  `next_run_at` datetime(3),
+  `last_run_at` datetime(3),
+  `version` bigint,
+  `created_at` datetime(3),
+  `updated_at` datetime(3),
+  PRIMARY KEY (`name`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_as_ci;
+
+CREATE TABLE IF NOT EXISTS `job_runs` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `job_name` varchar(255),
+  `type` varchar(64),
+  `payload` json,
+  `status` varchar(16),
+  `attempts` bigint,
+  `max_attempts` bigint,
+  `run_at` datetime(3),
+  `owner` varchar(255),
+  `lease_until` datetime(3),
+  `started_at` datetime(3),
+  `finished_at` datetime(3),
+  `error` text,
+  `created_at` datetime(3),
+  `updated_at` datetime(3),
+  PRIMARY KEY (`id`),
+  INDEX `idx_job_runs_job_name` (`job_name`),
+  INDEX `idx_job_runs_status_run_at` (`status`, `run_at`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_as_ci;
@@ -0,0 +1,10 @@
+ALTER TABLE `number_status`
+  DROP COLUMN `last_error`,
+  DROP COLUMN `last_run_at`,
+  DROP COLUMN `gaps`,
+  DROP COLUMN `found`,
+  DROP COLUMN `last_found`,
+  DROP COLUMN `max_misses`,
+  DROP COLUMN `max_suffix`,
+  DROP COLUMN `min_suffix`,
+  DROP COLUMN `digits`;
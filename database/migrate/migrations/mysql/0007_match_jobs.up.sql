@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS `match_jobs` (
+  `id` varchar(32) NOT NULL,
+  `status` varchar(16),
+  `total` bigint,
+  `unique_count` bigint,
+  `completed` bigint,
+  `matched` bigint,
+  `items` json,
+  `finished_at` datetime(3),
+  `created_at` datetime(3),
+  `updated_at` datetime(3),
+  PRIMARY KEY (`id`),
+  INDEX `idx_match_jobs_updated_at` (`updated_at`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_as_ci;
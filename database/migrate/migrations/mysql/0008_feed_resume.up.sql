@@ -0,0 +1,4 @@
+ALTER TABLE `feed_checkpoints`
+  ADD COLUMN `resume_page` bigint DEFAULT 0,
+  ADD COLUMN `pending_id` varchar(255),
+  ADD COLUMN `pending_release_date` date;
@@ -0,0 +1,3 @@
+ALTER TABLE `match_jobs` ADD COLUMN `items` json;
+
+DROP TABLE IF EXISTS `match_job_items`;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS `match_job_items` (
+  `job_id` varchar(32) NOT NULL,
+  `seq` bigint NOT NULL,
+  `input` text,
+  `result` json,
+  `error` text,
+  PRIMARY KEY (`job_id`, `seq`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_0900_as_ci;
+
+ALTER TABLE `match_jobs` DROP COLUMN `items`;
@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS "feed_checkpoints";
+DROP TABLE IF EXISTS "series";
+DROP TABLE IF EXISTS "makers";
+DROP TABLE IF EXISTS "provider_settings";
+DROP TABLE IF EXISTS "provider_sessions";
+DROP TABLE IF EXISTS "movie_reviews";
+DROP TABLE IF EXISTS "actor_metadata";
+DROP TABLE IF EXISTS "movie_metadata";
+-- The pg_trgm extension is kept, as it may be shared by other schemas.
+DROP COLLATION IF EXISTS nocase;
@@ -0,0 +1,141 @@
+-- Baseline schema, same as the one created by gorm auto migration,
+-- so that existing databases are adopted as is.
+
+-- Case-insensitive collation.
+CREATE COLLATION IF NOT EXISTS nocase (
+  provider = icu,
+  locale = 'und-u-ks-level2',
+  deterministic = FALSE
+);
+
+-- Trigram matching for fuzzy search.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE TABLE IF NOT EXISTS "movie_metadata" (
+  "id" text,
+  "number" text,
+  "title" text,
+  "summary" text,
+  "provider" text,
+  "homepage" text,
+  "director" text,
+  "actors" text[],
+  "thumb_url" text,
+  "big_thumb_url" text,
+  "cover_url" text,
+  "big_cover_url" text,
+  "preview_video_url" text,
+  "preview_video_hls_url" text,
+  "preview_images" text[],
+  "maker" text,
+  "label" text,
+  "series" text,
+  "genres" text[],
+  "score" decimal,
+  "runtime" bigint,
+  "release_date" date,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  PRIMARY KEY ("id", "provider")
+);
+
+CREATE TABLE IF NOT EXISTS "actor_metadata" (
+  "id" text,
+  "name" text,
+  "provider" text,
+  "homepage" text,
+  "summary" text,
+  "hobby" text,
+  "skill" text,
+  "blood_type" text,
+  "cup_size" text,
+  "measurements" text,
+  "nationality" text,
+  "height" bigint,
+  "aliases" text[],
+  "images" text[],
+  "birthday" date,
+  "debut_date" date,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  PRIMARY KEY ("id", "provider")
+);
+
+CREATE TABLE IF NOT EXISTS "movie_reviews" (
+  "id" text,
+  "provider" text,
+  "reviews" JSONB,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  PRIMARY KEY ("id", "provider")
+);
+
+CREATE TABLE IF NOT EXISTS "provider_sessions" (
+  "provider" text,
+  "cookies" JSONB,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  PRIMARY KEY ("provider")
+);
+
+CREATE TABLE IF NOT EXISTS "provider_settings" (
+  "type" text,
+  "name" text,
+  "enabled" boolean,
+  "priority" decimal,
+  "timeout" bigint,
+  "config" JSONB,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  PRIMARY KEY ("type", "name")
+);
+
+CREATE TABLE IF NOT EXISTS "makers" (
+  "name" text,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  PRIMARY KEY ("name")
+);
+
+CREATE TABLE IF NOT EXISTS "series" (
+  "name" text,
+  "maker" text,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  PRIMARY KEY ("name", "maker")
+);
+
+CREATE TABLE IF NOT EXISTS "feed_checkpoints" (
+  "provider" text,
+  "last_id" text,
+  "last_release_date" date,
+  "ingested" bigint,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  PRIMARY KEY ("provider")
+);
+
+-- Indexes for nocase collation.
+CREATE INDEX IF NOT EXISTS idx_actor_metadata_provider_nocase ON actor_metadata (provider COLLATE nocase);
+CREATE INDEX IF NOT EXISTS idx_actor_metadata_id_nocase ON actor_metadata (id COLLATE nocase);
+CREATE INDEX IF NOT EXISTS idx_actor_metadata_name_nocase ON actor_metadata (name COLLATE nocase);
+CREATE INDEX IF NOT EXISTS idx_movie_metadata_provider_nocase ON movie_metadata (provider COLLATE nocase);
+CREATE INDEX IF NOT EXISTS idx_movie_metadata_id_nocase ON movie_metadata (id COLLATE nocase);
+CREATE INDEX IF NOT EXISTS idx_movie_metadata_number_nocase ON movie_metadata (number COLLATE nocase);
+
+-- Indexes for full-text search.
+CREATE INDEX IF NOT EXISTS idx_actor_metadata_name_trgm ON actor_metadata USING gin (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_movie_metadata_number_trgm ON movie_metadata USING gin (number gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_movie_metadata_title_trgm ON movie_metadata USING gin (title gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_movie_metadata_summary_trgm ON movie_metadata USING gin (summary gin_trgm_ops);
+
+-- Collect makers and series from movies saved before these tables existed.
+INSERT INTO "makers" ("name", "created_at", "updated_at")
+SELECT DISTINCT "maker", CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
+FROM "movie_metadata" WHERE "maker" <> ''
+ON CONFLICT DO NOTHING;
+
+INSERT INTO "series" ("name", "maker", "created_at", "updated_at")
+SELECT DISTINCT "series", "maker", CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
+FROM "movie_metadata" WHERE "series" <> ''
+ON CONFLICT DO NOTHING;
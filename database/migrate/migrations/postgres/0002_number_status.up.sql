@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS "number_status" (
+  "number_prefix" text,
+  "status" bigint,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  PRIMARY KEY ("number_prefix")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_number_status_status" ON "number_status" ("status");
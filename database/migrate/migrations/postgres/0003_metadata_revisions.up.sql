@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS "metadata_revisions" (
+  "id" bigserial,
+  "kind" text,
+  "provider" text,
+  "record_id" text,
+  "source" text,
+  "changes" JSONB,
+  "data" JSONB,
+  "created_at" timestamptz,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_metadata_revisions_record" ON "metadata_revisions" ("kind", "provider" COLLATE nocase, "record_id" COLLATE nocase);
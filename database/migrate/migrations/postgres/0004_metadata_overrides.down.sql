@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "actor_overrides";
+DROP TABLE IF EXISTS "movie_overrides";
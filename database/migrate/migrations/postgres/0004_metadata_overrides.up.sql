@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS "movie_overrides" (
+  "id" text,
+  "provider" text,
+  "fields" JSONB,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  PRIMARY KEY ("id", "provider")
+);
+
+CREATE TABLE IF NOT EXISTS "actor_overrides" (
+  "id" text,
+  "provider" text,
+  "fields" JSONB,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  PRIMARY KEY ("id", "provider")
+);
@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS "jobs" (
+  "name" text,
+  "type" text,
+  "schedule" text,
+  "payload" JSONB,
+  "max_attempts" bigint,
+  "paused" boolean,
+  "next_run_at" timestamptz,
+  "last_run_at" timestamptz,
+  "version" bigint,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  PRIMARY KEY ("name")
+);
+
+CREATE TABLE IF NOT EXISTS "job_runs" (
+  "id" bigserial,
+  "job_name" text,
+  "type" text,
+  "payload" JSONB,
+  "status" text,
+  "attempts" bigint,
+  "max_attempts" bigint,
+  "run_at" timestamptz,
+  "owner" text,
+  "lease_until" timestamptz,
+  "started_at" timestamptz,
+  "finished_at" timestamptz,
+  "error" text,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_job_runs_job_name" ON "job_runs" ("job_name");
+CREATE INDEX IF NOT EXISTS "idx_job_runs_status_run_at" ON "job_runs" ("status", "run_at");
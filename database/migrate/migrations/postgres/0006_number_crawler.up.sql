@@ -0,0 +1,11 @@
+-- existing prefixes keep the 3-digit range without a miss limit.
+ALTER TABLE "number_status"
+  ADD COLUMN "digits" bigint DEFAULT 3,
+  ADD COLUMN "min_suffix" bigint DEFAULT 1,
+  ADD COLUMN "max_suffix" bigint DEFAULT 999,
+  ADD COLUMN "max_misses" bigint DEFAULT 0,
+  ADD COLUMN "last_found" bigint DEFAULT 0,
+  ADD COLUMN "found" bigint DEFAULT 0,
+  ADD COLUMN "gaps" JSONB,
+  ADD COLUMN "last_run_at" timestamptz,
+  ADD COLUMN "last_error" text;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS "match_jobs" (
+  "id" text,
+  "status" text,
+  "total" bigint,
+  "unique_count" bigint,
+  "completed" bigint,
+  "matched" bigint,
+  "items" JSONB,
+  "finished_at" timestamptz,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_match_jobs_updated_at" ON "match_jobs" ("updated_at");
@@ -0,0 +1,4 @@
+ALTER TABLE "feed_checkpoints"
+  DROP COLUMN "pending_release_date",
+  DROP COLUMN "pending_id",
+  DROP COLUMN "resume_page";
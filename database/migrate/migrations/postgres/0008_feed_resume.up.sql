@@ -0,0 +1,4 @@
+ALTER TABLE "feed_checkpoints"
+  ADD COLUMN "resume_page" bigint DEFAULT 0,
+  ADD COLUMN "pending_id" text,
+  ADD COLUMN "pending_release_date" date;
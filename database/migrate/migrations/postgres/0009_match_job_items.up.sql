@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS "match_job_items" (
+  "job_id" text,
+  "seq" bigint,
+  "input" text,
+  "result" JSONB,
+  "error" text,
+  PRIMARY KEY ("job_id", "seq")
+);
+
+ALTER TABLE "match_jobs" DROP COLUMN "items";
@@ -0,0 +1,16 @@
+DROP TRIGGER IF EXISTS actor_metadata_fts_au;
+DROP TRIGGER IF EXISTS actor_metadata_fts_ad;
+DROP TRIGGER IF EXISTS actor_metadata_fts_ai;
+DROP TABLE IF EXISTS actor_metadata_fts;
+DROP TRIGGER IF EXISTS movie_metadata_fts_au;
+DROP TRIGGER IF EXISTS movie_metadata_fts_ad;
+DROP TRIGGER IF EXISTS movie_metadata_fts_ai;
+DROP TABLE IF EXISTS movie_metadata_fts;
+DROP TABLE IF EXISTS `feed_checkpoints`;
+DROP TABLE IF EXISTS `series`;
+DROP TABLE IF EXISTS `makers`;
+DROP TABLE IF EXISTS `provider_settings`;
+DROP TABLE IF EXISTS `provider_sessions`;
+DROP TABLE IF EXISTS `movie_reviews`;
+DROP TABLE IF EXISTS `actor_metadata`;
+DROP TABLE IF EXISTS `movie_metadata`;
@@ -0,0 +1,167 @@
+-- Baseline schema, same as the one created by gorm auto migration,
+-- so that existing databases are adopted as is.
+CREATE TABLE IF NOT EXISTS `movie_metadata` (
+  `id` text,
+  `number` text,
+  `title` text,
+  `summary` text,
+  `provider` text,
+  `homepage` text,
+  `director` text,
+  `actors` text[],
+  `thumb_url` text,
+  `big_thumb_url` text,
+  `cover_url` text,
+  `big_cover_url` text,
+  `preview_video_url` text,
+  `preview_video_hls_url` text,
+  `preview_images` text[],
+  `maker` text,
+  `label` text,
+  `series` text,
+  `genres` text[],
+  `score` real,
+  `runtime` integer,
+  `release_date` date,
+  `created_at` datetime,
+  `updated_at` datetime,
+  PRIMARY KEY (`id`, `provider`)
+);
+
+CREATE TABLE IF NOT EXISTS `actor_metadata` (
+  `id` text,
+  `name` text,
+  `provider` text,
+  `homepage` text,
+  `summary` text,
+  `hobby` text,
+  `skill` text,
+  `blood_type` text,
+  `cup_size` text,
+  `measurements` text,
+  `nationality` text,
+  `height` integer,
+  `aliases` text[],
+  `images` text[],
+  `birthday` date,
+  `debut_date` date,
+  `created_at` datetime,
+  `updated_at` datetime,
+  PRIMARY KEY (`id`, `provider`)
+);
+
+CREATE TABLE IF NOT EXISTS `movie_reviews` (
+  `id` text,
+  `provider` text,
+  `reviews` JSON,
+  `created_at` datetime,
+  `updated_at` datetime,
+  PRIMARY KEY (`id`, `provider`)
+);
+
+CREATE TABLE IF NOT EXISTS `provider_sessions` (
+  `provider` text,
+  `cookies` JSON,
+  `created_at` datetime,
+  `updated_at` datetime,
+  PRIMARY KEY (`provider`)
+);
+
+CREATE TABLE IF NOT EXISTS `provider_settings` (
+  `type` text,
+  `name` text,
+  `enabled` numeric,
+  `priority` real,
+  `timeout` integer,
+  `config` JSON,
+  `created_at` datetime,
+  `updated_at` datetime,
+  PRIMARY KEY (`type`, `name`)
+);
+
+CREATE TABLE IF NOT EXISTS `makers` (
+  `name` text,
+  `created_at` datetime,
+  `updated_at` datetime,
+  PRIMARY KEY (`name`)
+);
+
+CREATE TABLE IF NOT EXISTS `series` (
+  `name` text,
+  `maker` text,
+  `created_at` datetime,
+  `updated_at` datetime,
+  PRIMARY KEY (`name`, `maker`)
+);
+
+CREATE TABLE IF NOT EXISTS `feed_checkpoints` (
+  `provider` text,
+  `last_id` text,
+  `last_release_date` date,
+  `ingested` integer,
+  `created_at` datetime,
+  `updated_at` datetime,
+  PRIMARY KEY (`provider`)
+);
+
+-- Full-text search tables, kept in sync by triggers and joined by rowid.
+CREATE VIRTUAL TABLE IF NOT EXISTS movie_metadata_fts USING fts5(
+  provider UNINDEXED, id, number, title, summary, tokenize = 'trigram'
+);
+
+-- +migrate StatementBegin
+CREATE TRIGGER IF NOT EXISTS movie_metadata_fts_ai AFTER INSERT ON movie_metadata BEGIN
+  INSERT INTO movie_metadata_fts(rowid, provider, id, number, title, summary)
+  VALUES (new.rowid, new.provider, new.id, new.number, new.title, new.summary);
+END;
+-- +migrate StatementEnd
+
+-- +migrate StatementBegin
+CREATE TRIGGER IF NOT EXISTS movie_metadata_fts_ad AFTER DELETE ON movie_metadata BEGIN
+  DELETE FROM movie_metadata_fts WHERE rowid = old.rowid;
+END;
+-- +migrate StatementEnd
+
+-- +migrate StatementBegin
+CREATE TRIGGER IF NOT EXISTS movie_metadata_fts_au AFTER UPDATE ON movie_metadata BEGIN
+  DELETE FROM movie_metadata_fts WHERE rowid = old.rowid;
+  INSERT INTO movie_metadata_fts(rowid, provider, id, number, title, summary)
+  VALUES (new.rowid, new.provider, new.id, new.number, new.title, new.summary);
+END;
+-- +migrate StatementEnd
+
+CREATE VIRTUAL TABLE IF NOT EXISTS actor_metadata_fts USING fts5(
+  provider UNINDEXED, id UNINDEXED, name, aliases, tokenize = 'trigram'
+);
+
+-- +migrate StatementBegin
+CREATE TRIGGER IF NOT EXISTS actor_metadata_fts_ai AFTER INSERT ON actor_metadata BEGIN
+  INSERT INTO actor_metadata_fts(rowid, provider, id, name, aliases)
+  VALUES (new.rowid, new.provider, new.id, new.name, new.aliases);
+END;
+-- +migrate StatementEnd
+
+-- +migrate StatementBegin
+CREATE TRIGGER IF NOT EXISTS actor_metadata_fts_ad AFTER DELETE ON actor_metadata BEGIN
+  DELETE FROM actor_metadata_fts WHERE rowid = old.rowid;
+END;
+-- +migrate StatementEnd
+
+-- +migrate StatementBegin
+CREATE TRIGGER IF NOT EXISTS actor_metadata_fts_au AFTER UPDATE ON actor_metadata BEGIN
+  DELETE FROM actor_metadata_fts WHERE rowid = old.rowid;
+  INSERT INTO actor_metadata_fts(rowid, provider, id, name, aliases)
+  VALUES (new.rowid, new.provider, new.id, new.name, new.aliases);
+END;
+-- +migrate StatementEnd
+
+-- Collect makers and series from movies saved before these tables existed.
+INSERT INTO `makers` (`name`, `created_at`, `updated_at`)
+SELECT DISTINCT `maker`, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
+FROM `movie_metadata` WHERE `maker` <> ''
+ON CONFLICT DO NOTHING;
+
+INSERT INTO `series` (`name`, `maker`, `created_at`, `updated_at`)
+SELECT DISTINCT `series`, `maker`, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
+FROM `movie_metadata` WHERE `series` <> ''
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS `metadata_revisions` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `kind` text,
+  `provider` text,
+  `record_id` text,
+  `source` text,
+  `changes` JSON,
+  `data` JSON,
+  `created_at` datetime
+);
+
+CREATE INDEX IF NOT EXISTS `idx_metadata_revisions_record` ON `metadata_revisions` (`kind`, `provider` COLLATE NOCASE, `record_id` COLLATE NOCASE);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS `movie_overrides` (
+  `id` text,
+  `provider` text,
+  `fields` JSON,
+  `created_at` datetime,
+  `updated_at` datetime,
+  PRIMARY KEY (`id`, `provider`)
+);
+
+CREATE TABLE IF NOT EXISTS `actor_overrides` (
+  `id` text,
+  `provider` text,
+  `fields` JSON,
+  `created_at` datetime,
+  `updated_at` datetime,
+  PRIMARY KEY (`id`, `provider`)
+);
@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS `jobs` (
+  `name` text,
+  `type` text,
+  `schedule` text,
+  `payload` JSON,
+  `max_attempts` integer,
+  `paused` numeric,
+  `next_run_at` datetime,
+  `last_run_at` datetime,
+  `version` integer,
+  `created_at` datetime,
+  `updated_at` datetime,
+  PRIMARY KEY (`name`)
+);
+
+CREATE TABLE IF NOT EXISTS `job_runs` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `job_name` text,
+  `type` text,
+  `payload` JSON,
+  `status` text,
+  `attempts` integer,
+  `max_attempts` integer,
+  `run_at` datetime,
+  `owner` text,
+  `lease_until` datetime,
+  `started_at` datetime,
+  `finished_at` datetime,
+  `error` text,
+  `created_at` datetime,
+  `updated_at` datetime
+);
+
+CREATE INDEX IF NOT EXISTS `idx_job_runs_job_name` ON `job_runs` (`job_name`);
+CREATE INDEX IF NOT EXISTS `idx_job_runs_status_run_at` ON `job_runs` (`status`, `run_at`);
@@ -0,0 +1,9 @@
+ALTER TABLE `number_status` DROP COLUMN `last_error`;
+ALTER TABLE `number_status` DROP COLUMN `last_run_at`;
+ALTER TABLE `number_status` DROP COLUMN `gaps`;
+ALTER TABLE `number_status` DROP COLUMN `found`;
+ALTER TABLE `number_status` DROP COLUMN `last_found`;
+ALTER TABLE `number_status` DROP COLUMN `max_misses`;
+ALTER TABLE `number_status` DROP COLUMN `max_suffix`;
+ALTER TABLE `number_status` DROP COLUMN `min_suffix`;
+ALTER TABLE `number_status` DROP COLUMN `digits`;
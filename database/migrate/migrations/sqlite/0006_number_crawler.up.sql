@@ -0,0 +1,10 @@
+-- existing prefixes keep the 3-digit range without a miss limit.
+ALTER TABLE `number_status` ADD COLUMN `digits` integer DEFAULT 3;
+ALTER TABLE `number_status` ADD COLUMN `min_suffix` integer DEFAULT 1;
+ALTER TABLE `number_status` ADD COLUMN `max_suffix` integer DEFAULT 999;
+ALTER TABLE `number_status` ADD COLUMN `max_misses` integer DEFAULT 0;
+ALTER TABLE `number_status` ADD COLUMN `last_found` integer DEFAULT 0;
+ALTER TABLE `number_status` ADD COLUMN `found` integer DEFAULT 0;
+ALTER TABLE `number_status` ADD COLUMN `gaps` JSON;
+ALTER TABLE `number_status` ADD COLUMN `last_run_at` datetime;
+ALTER TABLE `number_status` ADD COLUMN `last_error` text;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS `match_jobs` (
+  `id` text,
+  `status` text,
+  `total` integer,
+  `unique_count` integer,
+  `completed` integer,
+  `matched` integer,
+  `items` JSON,
+  `finished_at` datetime,
+  `created_at` datetime,
+  `updated_at` datetime,
+  PRIMARY KEY (`id`)
+);
+
+CREATE INDEX IF NOT EXISTS `idx_match_jobs_updated_at` ON `match_jobs` (`updated_at`);
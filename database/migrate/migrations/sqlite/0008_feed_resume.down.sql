@@ -0,0 +1,3 @@
+ALTER TABLE `feed_checkpoints` DROP COLUMN `pending_release_date`;
+ALTER TABLE `feed_checkpoints` DROP COLUMN `pending_id`;
+ALTER TABLE `feed_checkpoints` DROP COLUMN `resume_page`;
@@ -0,0 +1,3 @@
+ALTER TABLE `feed_checkpoints` ADD COLUMN `resume_page` integer DEFAULT 0;
+ALTER TABLE `feed_checkpoints` ADD COLUMN `pending_id` text;
+ALTER TABLE `feed_checkpoints` ADD COLUMN `pending_release_date` date;
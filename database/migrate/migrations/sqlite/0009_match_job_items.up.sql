@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS `match_job_items` (
+  `job_id` text,
+  `seq` integer,
+  `input` text,
+  `result` JSON,
+  `error` text,
+  PRIMARY KEY (`job_id`, `seq`)
+);
+
+ALTER TABLE `match_jobs` DROP COLUMN `items`;
@@ -4,10 +4,10 @@ import (
 	goerr "errors"
 	"fmt"
 	"sort"
+	"strings"
 	"sync"
 
 	"golang.org/x/text/language"
-	"gorm.io/gorm/clause"
 
 	"github.com/metatube-community/metatube-sdk-go/collection/sets"
 	"github.com/metatube-community/metatube-sdk-go/collection/slices"
@@ -19,20 +19,8 @@ import (
 	"github.com/metatube-community/metatube-sdk-go/provider/gfriends"
 )
 
-func (e *Engine) searchActorFromDB(keyword string, provider mt.Provider) (results []*model.ActorSearchResult, err error) {
-	var infos []*model.ActorInfo
-	if err = e.db.
-		Where("provider = ? AND name = ? COLLATE NOCASE",
-			provider.Name(), keyword).
-		Find(&infos).Error; err == nil {
-		for _, info := range infos {
-			if !info.IsValid() {
-				continue
-			}
-			results = append(results, info.ToSearchResult())
-		}
-	}
-	return
+func (e *Engine) searchActorFromDB(keyword string, provider mt.Provider) ([]*model.ActorSearchResult, error) {
+	return e.dbe.FindActors(keyword, provider.Name())
 }
 
 func (e *Engine) searchActor(keyword string, provider mt.Provider, fallback bool) ([]*model.ActorSearchResult, error) {
@@ -101,6 +89,10 @@ func (e *Engine) searchActor(keyword string, provider mt.Provider, fallback bool
 		}
 		return nil, mt.ErrInfoNotFound
 	}
+	// rewrite homepages to the working mirror.
+	for _, result := range results {
+		result.Homepage = preferredURL(provider, result.Homepage)
+	}
 	return results, nil
 }
 
@@ -117,7 +109,8 @@ func (e *Engine) SearchActorAll(keyword string, fallback bool) (results []*model
 		mu sync.Mutex
 		wg sync.WaitGroup
 	)
-	for _, provider := range e.actorProviders.Iterator() {
+	providers := e.GetActorProviders()
+	for _, provider := range providers {
 		wg.Add(1)
 		go func(provider mt.ActorProvider) {
 			defer wg.Done()
@@ -135,19 +128,25 @@ func (e *Engine) SearchActorAll(keyword string, fallback bool) (results []*model
 	wg.Wait()
 
 	sort.SliceStable(results, func(i, j int) bool {
-		return e.MustGetActorProviderByName(results[i].Provider).Priority() >
-			e.MustGetActorProviderByName(results[j].Provider).Priority()
+		return actorProviderPriority(providers, results[i].Provider) >
+			actorProviderPriority(providers, results[j].Provider)
 	})
 	return
 }
 
+// actorProviderPriority returns the priority of the named provider
+// in providers, or 0 if it's not found, e.g. disabled meanwhile.
+func actorProviderPriority(providers map[string]mt.ActorProvider, name string) float64 {
+	for key, provider := range providers {
+		if strings.EqualFold(key, name) {
+			return provider.Priority()
+		}
+	}
+	return 0
+}
+
 func (e *Engine) getActorInfoFromDB(provider mt.ActorProvider, id string) (*model.ActorInfo, error) {
-	info := &model.ActorInfo{}
-	err := e.db. // Exact match here.
-			Where("provider = ?", provider.Name()).
-			Where("id = ? COLLATE NOCASE", id).
-			First(info).Error
-	return info, err
+	return e.dbe.GetActorInfo(providerid.ProviderID{Provider: provider.Name(), ID: id})
 }
 
 func (e *Engine) getActorInfoWithCallback(provider mt.ActorProvider, id string, lazy bool, callback func() (*model.ActorInfo, error)) (info *model.ActorInfo, err error) {
@@ -157,13 +156,31 @@ func (e *Engine) getActorInfoWithCallback(provider mt.ActorProvider, id string,
 			err = mt.ErrIncompleteMetadata
 		}
 	}()
+	defer func() {
+		// rewrite homepage to the working mirror, this happens
+		// after auto-save, so the original homepage is stored.
+		if err == nil && info != nil {
+			info.Homepage = preferredURL(provider, info.Homepage)
+		}
+	}()
 	if provider.Name() == gfriends.Name {
 		return provider.GetActorInfoByID(id)
 	}
+	defer func() {
+		// apply manual overrides after auto-save and image injection,
+		// so that they are never stored in, nor replaced by the metadata.
+		if err == nil && info != nil {
+			e.applyActorOverride(info)
+		}
+	}()
 	defer func() {
 		// gfriends actor image injection for JAV actor providers.
 		if err == nil && info != nil && provider.Language() == language.Japanese {
-			if gInfo, gErr := e.MustGetActorProviderByName(gfriends.Name).GetActorInfoByID(info.Name); gErr == nil && len(gInfo.Images) > 0 {
+			gProvider, gErr := e.GetActorProviderByName(gfriends.Name)
+			if gErr != nil /* disabled */ {
+				return
+			}
+			if gInfo, gErr := gProvider.GetActorInfoByID(info.Name); gErr == nil && len(gInfo.Images) > 0 {
 				info.Images = append(gInfo.Images, info.Images...)
 			}
 		}
@@ -171,6 +188,8 @@ func (e *Engine) getActorInfoWithCallback(provider mt.ActorProvider, id string,
 	// Query DB first (by id).
 	if lazy {
 		if info, err = e.getActorInfoFromDB(provider, id); err == nil && info.IsValid() {
+			// serve the stale info while it's being refreshed.
+			e.refreshActorInfoIfStale(provider, info)
 			return
 		}
 	}
@@ -178,9 +197,7 @@ func (e *Engine) getActorInfoWithCallback(provider mt.ActorProvider, id string,
 	defer func() {
 		if err == nil && info.IsValid() {
 			// Make sure we save the original info here.
-			e.db.Clauses(clause.OnConflict{
-				UpdateAll: true,
-			}).Create(info) // ignore error
+			e.dbe.SaveActorInfo(info) // ignore error
 		}
 	}()
 	return callback()
@@ -0,0 +1,161 @@
+package engine
+
+import (
+	"iter"
+	"slices"
+	"strings"
+
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+const (
+	actorProviderType = "actor"
+	movieProviderType = "movie"
+)
+
+// Provider features derived from the implemented interfaces.
+const (
+	FeatureActorInfo    = "actor_info"
+	FeatureActorSearch  = "actor_search"
+	FeatureMovieInfo    = "movie_info"
+	FeatureMovieSearch  = "movie_search"
+	FeatureMovieReviews = "movie_reviews"
+	FeatureFilmography  = "filmography"
+	FeatureBrowse       = "browse"
+	FeatureReleaseFeed  = "release_feed"
+	FeatureFetch        = "fetch"
+	FeatureConfig       = "config"
+	FeatureTimeout      = "timeout"
+	FeatureProxy        = "proxy"
+	FeatureSession      = "session"
+	FeatureMirrors      = "mirrors"
+)
+
+// ProviderCapability describes what a provider supports and how it's configured.
+type ProviderCapability struct {
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`
+	URL      string   `json:"url"`
+	Language string   `json:"language"`
+	Priority float64  `json:"priority"`
+	Enabled  bool     `json:"enabled"`
+	Timeout  string   `json:"timeout,omitempty"`
+	Features []string `json:"features"`
+	// ConfigKeys lists the configured keys, values are never exposed.
+	ConfigKeys []string `json:"config_keys,omitempty"`
+}
+
+// GetProviderCapabilities returns the capabilities of all actor and movie
+// providers, including the disabled ones, ordered by type, priority and name.
+func (e *Engine) GetProviderCapabilities() []*ProviderCapability {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	var capabilities []*ProviderCapability
+	for provider := range e.actorProviders.Values() {
+		capabilities = append(capabilities, e.newProviderCapability(actorProviderType, provider, true))
+	}
+	for provider := range e.disabledActorProviders.Values() {
+		capabilities = append(capabilities, e.newProviderCapability(actorProviderType, provider, false))
+	}
+	for provider := range e.movieProviders.Values() {
+		capabilities = append(capabilities, e.newProviderCapability(movieProviderType, provider, true))
+	}
+	for provider := range e.disabledMovieProviders.Values() {
+		capabilities = append(capabilities, e.newProviderCapability(movieProviderType, provider, false))
+	}
+	slices.SortFunc(capabilities, func(a, b *ProviderCapability) int {
+		if c := strings.Compare(a.Type, b.Type); c != 0 {
+			return c
+		}
+		if a.Priority != b.Priority {
+			if a.Priority > b.Priority {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return capabilities
+}
+
+func (e *Engine) newProviderCapability(providerType string, provider mt.Provider, enabled bool) *ProviderCapability {
+	capability := &ProviderCapability{
+		Name:     provider.Name(),
+		Type:     providerType,
+		URL:      provider.URL().String(),
+		Language: provider.Language().String(),
+		Priority: provider.Priority(),
+		Enabled:  enabled,
+		Features: providerFeatures(provider),
+	}
+
+	// Effective request timeout.
+	if g, ok := provider.(mt.RequestTimeoutGetter); ok {
+		if v := g.RequestTimeout(); v > 0 {
+			capability.Timeout = v.String()
+		}
+	} else if _, ok = provider.(mt.RequestTimeoutSetter); ok {
+		capability.Timeout = e.timeout.String()
+	}
+
+	// Configured keys, if any.
+	configs := e.actorProviderConfigs
+	if providerType == movieProviderType {
+		configs = e.movieProviderConfigs
+	}
+	if config, ok := configs.Get(provider.Name()); ok {
+		if k, ok := config.(interface{ Keys() iter.Seq[string] }); ok {
+			for key := range k.Keys() {
+				capability.ConfigKeys = append(capability.ConfigKeys, strings.ToLower(key))
+			}
+			slices.Sort(capability.ConfigKeys)
+		}
+	}
+	return capability
+}
+
+func providerFeatures(provider mt.Provider) (features []string) {
+	if _, ok := provider.(mt.ActorProvider); ok {
+		features = append(features, FeatureActorInfo)
+	}
+	if _, ok := provider.(mt.ActorSearcher); ok {
+		features = append(features, FeatureActorSearch)
+	}
+	if _, ok := provider.(mt.MovieProvider); ok {
+		features = append(features, FeatureMovieInfo)
+	}
+	if _, ok := provider.(mt.MovieSearcher); ok {
+		features = append(features, FeatureMovieSearch)
+	}
+	if _, ok := provider.(mt.MovieReviewer); ok {
+		features = append(features, FeatureMovieReviews)
+	}
+	if _, ok := provider.(mt.ActorFilmographer); ok {
+		features = append(features, FeatureFilmography)
+	}
+	if _, ok := provider.(mt.MovieBrowser); ok {
+		features = append(features, FeatureBrowse)
+	}
+	if _, ok := provider.(mt.ReleaseFeeder); ok {
+		features = append(features, FeatureReleaseFeed)
+	}
+	if _, ok := provider.(mt.Fetcher); ok {
+		features = append(features, FeatureFetch)
+	}
+	if _, ok := provider.(mt.ConfigSetter); ok {
+		features = append(features, FeatureConfig)
+	}
+	if _, ok := provider.(mt.RequestTimeoutSetter); ok {
+		features = append(features, FeatureTimeout)
+	}
+	if _, ok := provider.(mt.ProxySetter); ok {
+		features = append(features, FeatureProxy)
+	}
+	if s, ok := provider.(mt.SessionSetter); ok && s.SessionEnabled() {
+		features = append(features, FeatureSession)
+	}
+	if _, ok := provider.(mt.MirrorSetter); ok {
+		features = append(features, FeatureMirrors)
+	}
+	return
+}
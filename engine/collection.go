@@ -0,0 +1,114 @@
+package engine
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/metatube-community/metatube-sdk-go/engine/providerid"
+	"github.com/metatube-community/metatube-sdk-go/errors"
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+// collectionColumns maps collection types to movie metadata columns.
+var collectionColumns = map[string]string{
+	mt.MakerCollection:  "maker",
+	mt.LabelCollection:  "label",
+	mt.SeriesCollection: "series",
+}
+
+// saveMovieCollections saves the maker and series of the movie, if any.
+func (e *Engine) saveMovieCollections(info *model.MovieInfo) {
+	if info.Maker != "" {
+		_ = e.dbe.AddMaker(&model.Maker{Name: info.Maker}) // ignore error
+	}
+	if info.Series != "" {
+		_ = e.dbe.AddSeries(&model.Series{Name: info.Series, Maker: info.Maker}) // ignore error
+	}
+}
+
+// ListMakers lists the saved makers whose names contain the keyword,
+// page starts from 1.
+func (e *Engine) ListMakers(keyword string, page, size int) ([]*model.Maker, error) {
+	return e.dbe.ListMakers(keyword, page, size)
+}
+
+// ListSeries lists the saved series whose names contain the keyword,
+// optionally of the given maker, page starts from 1.
+func (e *Engine) ListSeries(keyword, maker string, page, size int) ([]*model.Series, error) {
+	return e.dbe.ListSeries(keyword, maker, page, size)
+}
+
+// ListCollectionMovies lists the saved movies of the maker, label or
+// series by name, newest first. The maker is optional, and is used to
+// tell apart series of the same name.
+func (e *Engine) ListCollectionMovies(collection, name, maker string, page, size int) ([]*model.MovieSearchResult, error) {
+	column, ok := collectionColumns[collection]
+	if !ok {
+		return nil, mt.ErrInvalidCollection
+	}
+	infos, err := e.dbe.ListCollectionMovies(column, name, maker, page, size)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*model.MovieSearchResult, 0, len(infos))
+	for _, info := range infos {
+		if !info.IsValid() {
+			continue
+		}
+		result := info.ToSearchResult()
+		if provider, err := e.GetMovieProviderByName(result.Provider); err == nil {
+			result.Homepage = preferredURL(provider, result.Homepage)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// BrowseMovies lists the movies of the maker, label or series from
+// the provider by id, page starts from 1. The pages of size movies are
+// mapped onto the pages of the provider, which are assumed to be of the
+// same size as the first one, except the last.
+func (e *Engine) BrowseMovies(collection string, pid providerid.ProviderID, page, size int) ([]*model.MovieSearchResult, error) {
+	if _, ok := collectionColumns[collection]; !ok {
+		return nil, mt.ErrInvalidCollection
+	}
+	provider, err := e.GetMovieProviderByName(pid.Provider)
+	if err != nil {
+		return nil, err
+	}
+	browser, ok := provider.(mt.MovieBrowser)
+	if !ok {
+		return nil, errors.New(http.StatusBadRequest,
+			fmt.Sprintf("browsing not supported by %s", provider.Name()))
+	}
+	first, err := browser.BrowseMovies(collection, pid.ID, 1)
+	if err != nil || len(first) == 0 {
+		return nil, err
+	}
+	perPage := len(first)
+	if size <= 0 {
+		size = perPage
+	}
+	offset := (max(page, 1) - 1) * size
+	providerPage, skip := offset/perPage+1, offset%perPage
+
+	var results []*model.MovieSearchResult
+	for len(results) < size {
+		items := first
+		if providerPage > 1 {
+			if items, err = browser.BrowseMovies(collection, pid.ID, providerPage); err != nil {
+				return nil, err
+			}
+		}
+		if skip >= len(items) {
+			break // no more pages.
+		}
+		results = append(results, items[skip:]...)
+		if len(items) < perPage {
+			break // the last page.
+		}
+		providerPage, skip = providerPage+1, 0
+	}
+	return validMovieResults(provider, results[:min(len(results), size)]), nil
+}
@@ -0,0 +1,63 @@
+package engine
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metatube-community/metatube-sdk-go/engine/providerid"
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+// fakeBrowser lists total movies of a collection by pages of perPage.
+type fakeBrowser struct {
+	mt.MovieProvider
+	total, perPage int
+	visited        []int
+}
+
+func (*fakeBrowser) Name() string { return "BROWSER" }
+
+func (b *fakeBrowser) BrowseMovies(_, _ string, page int) ([]*model.MovieSearchResult, error) {
+	b.visited = append(b.visited, page)
+	var results []*model.MovieSearchResult
+	for i := (page - 1) * b.perPage; i < page*b.perPage && i < b.total; i++ {
+		id := fmt.Sprintf("ID-%03d", i+1)
+		results = append(results, &model.MovieSearchResult{
+			ID: id, Number: id, Title: id, Provider: b.Name(), Homepage: "https://browser/" + id,
+		})
+	}
+	return results, nil
+}
+
+func TestEngine_BrowseMovies(t *testing.T) {
+	e := New(openTestDB(t))
+	browser := &fakeBrowser{total: 25, perPage: 10}
+	e.movieProviders.Set(browser.Name(), browser)
+	pid := providerid.ProviderID{Provider: browser.Name(), ID: "1"}
+
+	for _, unit := range []struct {
+		page, size int
+		first      string
+		count      int
+		visited    []int
+	}{
+		{1, 10, "ID-001", 10, []int{1}},
+		{1, 5, "ID-001", 5, []int{1}},
+		{2, 15, "ID-016", 10, []int{1, 2, 3}},
+		{3, 4, "ID-009", 4, []int{1, 2}},
+		{4, 10, "", 0, []int{1, 4}},
+	} {
+		browser.visited = nil
+		results, err := e.BrowseMovies(mt.MakerCollection, pid, unit.page, unit.size)
+		require.NoError(t, err)
+		require.Len(t, results, unit.count)
+		if unit.count > 0 {
+			assert.Equal(t, unit.first, results[0].ID)
+		}
+		assert.Equal(t, unit.visited, browser.visited)
+	}
+}
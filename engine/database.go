@@ -1,42 +1,91 @@
 package engine
 
 import (
-	"fmt"
+	"io"
 
-	"github.com/metatube-community/metatube-sdk-go/database"
+	"github.com/metatube-community/metatube-sdk-go/engine/dbengine"
 	"github.com/metatube-community/metatube-sdk-go/model"
 )
 
+// dbSearchLimit limits the results of searches from DB.
+const dbSearchLimit = 20
+
+// DBAutoMigrate applies the pending migrations if v is true, it always
+// fails if the DB schema was migrated by a newer version.
 func (e *Engine) DBAutoMigrate(v bool) error {
+	m := e.dbe.Migrator()
+	if err := m.Check(); err != nil {
+		return err
+	}
 	if !v {
+		if pending, err := m.Pending(); err == nil && len(pending) > 0 {
+			e.logger.Printf("Database has %d pending migrations", len(pending))
+		}
 		return nil
 	}
-	// Create Case-Insensitive Collation for Postgres.
-	if e.DBDriver() == database.Postgres {
-		e.db.Exec(`CREATE COLLATION IF NOT EXISTS NOCASE (
-		provider = icu,
-		locale = 'und-u-ks-level2',
-		deterministic = FALSE)`)
-	}
-	return e.db.AutoMigrate(
-		&model.MovieInfo{},
-		&model.ActorInfo{},
-		&model.MovieReviewInfo{},
-	)
+	return e.dbe.Migrate()
 }
 
 func (e *Engine) DBDriver() string {
-	return e.db.Config.Dialector.Name()
+	return e.dbe.Driver()
+}
+
+func (e *Engine) DBVersion() (string, error) {
+	return e.dbe.Version()
+}
+
+// ExportDB writes the cached metadata as NDJSON records, and returns
+// the number of records written.
+func (e *Engine) ExportDB(w io.Writer, opts dbengine.ExportOptions) (int, error) {
+	return e.dbe.Export(w, opts)
 }
 
-func (e *Engine) DBVersion() (version string, err error) {
-	switch dbType := e.DBDriver(); dbType {
-	case database.Postgres:
-		err = e.db.Raw("SELECT version();").Scan(&version).Error
-	case database.Sqlite:
-		err = e.db.Raw("SELECT sqlite_version();").Scan(&version).Error
-	default:
-		err = fmt.Errorf("unsupported DB type: %s", dbType)
+// ImportDB imports the NDJSON records written by ExportDB, existing
+// rows are resolved by the conflict policy of the options.
+func (e *Engine) ImportDB(r io.Reader, opts dbengine.ImportOptions) (*dbengine.ImportProgress, error) {
+	progress, err := e.dbe.Import(r, opts)
+	if err != nil {
+		return progress, err
+	}
+	e.logger.Printf("Database import done: %d read, %d imported, %d skipped, %d invalid",
+		progress.Read, progress.Imported, progress.Skipped, progress.Invalid)
+	return progress, nil
+}
+
+// SearchMovieFromDB searches the locally cached movies by number, id
+// or title fuzzily, optionally filtered by the provider name.
+func (e *Engine) SearchMovieFromDB(keyword, provider string, limit, offset int) ([]*model.MovieSearchResult, error) {
+	results, err := e.dbe.SearchMovie(keyword, dbengine.MovieSearchOptions{
+		Provider: provider,
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		if p, err := e.GetMovieProviderByName(result.Provider); err == nil {
+			result.Homepage = preferredURL(p, result.Homepage)
+		}
+	}
+	return results, nil
+}
+
+// SearchActorFromDB searches the locally cached actors by name
+// fuzzily, optionally filtered by the provider name.
+func (e *Engine) SearchActorFromDB(keyword, provider string, limit, offset int) ([]*model.ActorSearchResult, error) {
+	results, err := e.dbe.SearchActor(keyword, dbengine.ActorSearchOptions{
+		Provider: provider,
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		if p, err := e.GetActorProviderByName(result.Provider); err == nil {
+			result.Homepage = preferredURL(p, result.Homepage)
+		}
 	}
-	return
+	return results, nil
 }
@@ -5,7 +5,6 @@ import (
 
 	"gorm.io/gorm/clause"
 
-	"github.com/metatube-community/metatube-sdk-go/database"
 	"github.com/metatube-community/metatube-sdk-go/engine/providerid"
 	"github.com/metatube-community/metatube-sdk-go/model"
 )
@@ -14,6 +13,7 @@ type actorEngine interface {
 	GetActorInfo(providerid.ProviderID) (*model.ActorInfo, error)
 	SaveActorInfo(*model.ActorInfo) error
 	SearchActor(string, ActorSearchOptions) ([]*model.ActorSearchResult, error)
+	FindActors(name, provider string) ([]*model.ActorSearchResult, error)
 }
 
 var _ actorEngine = (*engine)(nil)
@@ -22,19 +22,22 @@ func (e *engine) GetActorInfo(pid providerid.ProviderID) (*model.ActorInfo, erro
 	info := &model.ActorInfo{}
 	err := e.DB().
 		Where( // Exact match here.
-			`provider COLLATE NOCASE = ? AND id COLLATE NOCASE = ?`,
+			e.dialect.equalFold("provider")+" AND "+e.dialect.equalFold("id"),
 			pid.Provider, pid.ID,
 		).First(info).Error
 	return info, err
 }
 
 func (e *engine) SaveActorInfo(info *model.ActorInfo) error {
+	return e.saveActorInfo(info, model.RevisionSourceScrape)
+}
+
+func (e *engine) saveActorInfo(info *model.ActorInfo, source model.RevisionSource) error {
 	if !info.IsValid() {
 		return fmt.Errorf("invalid %T", info)
 	}
-	return e.DB().Clauses(clause.OnConflict{
-		UpdateAll: true,
-	}).Create(info).Error
+	return saveWithRevision(e.DB(), model.ActorRevision,
+		providerid.ProviderID{Provider: info.Provider, ID: info.ID}, source, info)
 }
 
 func (e *engine) SearchActor(keyword string, opts ActorSearchOptions) ([]*model.ActorSearchResult, error) {
@@ -45,22 +48,15 @@ func (e *engine) SearchActor(keyword string, opts ActorSearchOptions) ([]*model.
 
 	// provider filter.
 	if opts.Provider != "" {
-		tx = tx.Where(`provider COLLATE NOCASE = ?`, opts.Provider)
+		tx = tx.Where(e.dialect.equalFold(actorColumn("provider")), opts.Provider)
 	}
 
-	// keyword filter.
-	if e.Driver() == database.Postgres {
-		tx = tx.Where(
-			`(name COLLATE NOCASE = ? OR similarity(name, ?) > ?)`,
-			keyword, keyword, opts.Threshold,
-		)
-	} else { // Sqlite
-		pattern := "%" + keyword + "%"
-		tx = tx.Where(
-			`(name COLLATE NOCASE = ? OR name LIKE ? COLLATE NOCASE)`,
-			keyword, pattern,
-		)
-	}
+	// keyword filter and relevance order.
+	tx = e.dialect.searchActor(tx, keyword, &opts)
+
+	// stable order for pagination.
+	tx = tx.Order(clause.OrderByColumn{Column: clause.Column{Table: clause.CurrentTable, Name: "provider"}}).
+		Order(clause.OrderByColumn{Column: clause.Column{Table: clause.CurrentTable, Name: "id"}})
 
 	// pagination.
 	if opts.Limit > 0 {
@@ -84,3 +80,25 @@ func (e *engine) SearchActor(keyword string, opts ActorSearchOptions) ([]*model.
 	}
 	return results, nil
 }
+
+// FindActors finds actors of which the name equals the given one
+// case-insensitively, for internal lookups that must not return near
+// misses, an empty provider means all providers.
+func (e *engine) FindActors(name, provider string) ([]*model.ActorSearchResult, error) {
+	tx := e.DB().Where(e.dialect.equalFold("name"), name)
+	if provider != "" {
+		tx = tx.Where(e.dialect.equalFold("provider"), provider)
+	}
+	var infos []*model.ActorInfo
+	if err := tx.Order("provider").Order("id").Find(&infos).Error; err != nil {
+		return nil, err
+	}
+	results := make([]*model.ActorSearchResult, 0, len(infos))
+	for _, info := range infos {
+		if !info.IsValid() {
+			continue // ignore invalid info.
+		}
+		results = append(results, info.ToSearchResult())
+	}
+	return results, nil
+}
@@ -0,0 +1,75 @@
+package dbengine
+
+import (
+	"fmt"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+type collectionEngine interface {
+	AddMaker(*model.Maker) error
+	AddSeries(*model.Series) error
+	ListMakers(keyword string, page, size int) ([]*model.Maker, error)
+	ListSeries(keyword, maker string, page, size int) ([]*model.Series, error)
+	ListCollectionMovies(column, name, maker string, page, size int) ([]*model.MovieInfo, error)
+}
+
+var _ collectionEngine = (*engine)(nil)
+
+// AddMaker saves the maker, it's a no-op if exists.
+func (e *engine) AddMaker(maker *model.Maker) error {
+	return e.DB().Clauses(clause.OnConflict{
+		DoNothing: true,
+	}).Create(maker).Error
+}
+
+// AddSeries saves the series, it's a no-op if exists.
+func (e *engine) AddSeries(series *model.Series) error {
+	return e.DB().Clauses(clause.OnConflict{
+		DoNothing: true,
+	}).Create(series).Error
+}
+
+// ListMakers lists the makers whose names contain the keyword,
+// page starts from 1.
+func (e *engine) ListMakers(keyword string, page, size int) (makers []*model.Maker, err error) {
+	tx := e.DB().Order("name")
+	if keyword != "" {
+		tx = tx.Where("name LIKE ?", "%"+keyword+"%")
+	}
+	err = tx.Offset((page - 1) * size).Limit(size).Find(&makers).Error
+	return
+}
+
+// ListSeries lists the series whose names contain the keyword,
+// optionally of the given maker, page starts from 1.
+func (e *engine) ListSeries(keyword, maker string, page, size int) (series []*model.Series, err error) {
+	tx := e.DB().Order("name").Order("maker")
+	if keyword != "" {
+		tx = tx.Where("name LIKE ?", "%"+keyword+"%")
+	}
+	if maker != "" {
+		tx = tx.Where("maker = ?", maker)
+	}
+	err = tx.Offset((page - 1) * size).Limit(size).Find(&series).Error
+	return
+}
+
+// ListCollectionMovies lists the movies whose column, i.e. maker, label
+// or series, equals the name, newest first. The maker is optional.
+func (e *engine) ListCollectionMovies(column, name, maker string, page, size int) (infos []*model.MovieInfo, err error) {
+	tx := e.DB().Where(fmt.Sprintf("%s = ?", column), name)
+	if maker != "" && column != "maker" {
+		tx = tx.Where("maker = ?", maker)
+	}
+	err = tx.
+		Order("release_date DESC").
+		Order("provider").
+		Order("id").
+		Offset((page - 1) * size).
+		Limit(size).
+		Find(&infos).Error
+	return
+}
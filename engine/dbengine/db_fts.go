@@ -0,0 +1,107 @@
+package dbengine
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+const (
+	// ftsMinKeywordLength is the min keyword length the trigram
+	// tokenizer can match, shorter keywords fall back to LIKE.
+	ftsMinKeywordLength = 3
+	// ftsMigrationVersion is the version of the migration which
+	// creates the FTS tables and their triggers.
+	ftsMigrationVersion = 1
+)
+
+// ftsTable is an SQLite FTS5 table mirroring the text columns of
+// a metadata table, kept in sync by triggers and joined by rowid.
+type ftsTable struct {
+	table     string
+	unindexed []string
+	indexed   []string
+	// weights of the indexed columns for BM25 ranking.
+	weights []float64
+}
+
+var (
+	movieFTS = &ftsTable{
+		table:     model.MovieMetadataTableName,
+		unindexed: []string{"provider"},
+		indexed:   []string{"id", "number", "title", "summary"},
+		weights:   []float64{10, 10, 5, 1},
+	}
+	actorFTS = &ftsTable{
+		table:     model.ActorMetadataTableName,
+		unindexed: []string{"provider", "id"},
+		indexed:   []string{"name", "aliases"},
+		weights:   []float64{10, 5},
+	}
+)
+
+func (t *ftsTable) name() string {
+	return t.table + "_fts"
+}
+
+func (t *ftsTable) columns() []string {
+	return append(append([]string{}, t.unindexed...), t.indexed...)
+}
+
+// rank returns the BM25 rank expression, lower is better.
+func (t *ftsTable) rank() string {
+	weights := make([]string, 0, len(t.unindexed)+len(t.indexed))
+	for range t.unindexed {
+		weights = append(weights, "0")
+	}
+	for _, w := range t.weights {
+		weights = append(weights, fmt.Sprint(w))
+	}
+	return fmt.Sprintf("bm25(%s, %s)", t.name(), strings.Join(weights, ", "))
+}
+
+// join joins the FTS table and filters by the keyword.
+func (t *ftsTable) join(tx *gorm.DB, keyword string) *gorm.DB {
+	return tx.
+		Select(t.table+".*").
+		Joins(fmt.Sprintf("JOIN %s ON %s.rowid = %s.rowid", t.name(), t.name(), t.table)).
+		Where(fmt.Sprintf("%s MATCH ?", t.name()), ftsPhrase(keyword))
+}
+
+// empty reports whether the FTS index is empty while the table is
+// not, e.g. the index is cleared, which costs no table scan.
+func (t *ftsTable) empty(db *gorm.DB) (empty bool, err error) {
+	err = db.Raw(fmt.Sprintf(
+		`SELECT NOT EXISTS (SELECT 1 FROM %s) AND EXISTS (SELECT 1 FROM %s)`,
+		t.name(), t.table)).Scan(&empty).Error
+	return
+}
+
+// rebuild populates the FTS index from the table. Tables and triggers
+// are created by the migrations, which must be kept in line with the
+// columns, and migrations rebuilding the table must rebuild the index.
+func (t *ftsTable) rebuild(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(fmt.Sprintf(`DELETE FROM %s`, t.name())).Error; err != nil {
+			return err
+		}
+		cols := strings.Join(t.columns(), ", ")
+		return tx.Exec(fmt.Sprintf(`INSERT INTO %s(rowid, %s) SELECT rowid, %s FROM %s`,
+			t.name(), cols, cols, t.table)).Error
+	})
+}
+
+// useFTS reports whether the keyword is long enough for FTS.
+func useFTS(keyword string) bool {
+	return utf8.RuneCountInString(keyword) >= ftsMinKeywordLength
+}
+
+// ftsPhrase quotes the keyword as an FTS5 phrase, so that it is
+// matched as a substring with the trigram tokenizer.
+func ftsPhrase(keyword string) string {
+	return `"` + strings.ReplaceAll(keyword, `"`, `""`) + `"`
+}
@@ -0,0 +1,93 @@
+package dbengine
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+// matchJobItemBatchSize is the number of items inserted at a time.
+const matchJobItemBatchSize = 500
+
+type matchJobEngine interface {
+	GetMatchJob(id string) (*model.MatchJob, error)
+	GetMatchJobItems(id string, limit, offset int) ([]*model.MatchJobItem, error)
+	SaveMatchJob(*model.MatchJob, []*model.MatchJobItem) error
+	SaveMatchJobProgress(*model.MatchJob) error
+	DeleteMatchJobs(updatedBefore time.Time) (int64, error)
+}
+
+var _ matchJobEngine = (*engine)(nil)
+
+func (e *engine) GetMatchJob(id string) (*model.MatchJob, error) {
+	job := &model.MatchJob{}
+	err := e.DB().
+		Where("id = ?", id).
+		First(job).Error
+	return job, err
+}
+
+// GetMatchJobItems returns the items of the job in input order,
+// limit <= 0 means no limit.
+func (e *engine) GetMatchJobItems(id string, limit, offset int) ([]*model.MatchJobItem, error) {
+	tx := e.DB().Where("job_id = ?", id).Order("seq")
+	if limit > 0 {
+		tx = tx.Limit(limit)
+	}
+	if offset > 0 {
+		tx = tx.Offset(offset)
+	}
+	var items []*model.MatchJobItem
+	err := tx.Find(&items).Error
+	return items, err
+}
+
+// SaveMatchJob upserts the job, and replaces its items if any.
+func (e *engine) SaveMatchJob(job *model.MatchJob, items []*model.MatchJobItem) error {
+	return e.DB().Transaction(func(tx *gorm.DB) error {
+		if len(items) > 0 {
+			if err := tx.
+				Where("job_id = ?", job.ID).
+				Delete(&model.MatchJobItem{}).Error; err != nil {
+				return err
+			}
+			if err := tx.CreateInBatches(items, matchJobItemBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Clauses(clause.OnConflict{
+			UpdateAll: true,
+		}).Create(job).Error
+	})
+}
+
+// SaveMatchJobProgress updates the counters of the job only, which
+// also refreshes its update time as a heartbeat.
+func (e *engine) SaveMatchJobProgress(job *model.MatchJob) error {
+	return e.DB().Model(job).
+		Select("status", "completed", "matched", "updated_at").
+		Updates(job).Error
+}
+
+// DeleteMatchJobs deletes the jobs not updated since the time with
+// their items, and returns the number of deleted jobs.
+func (e *engine) DeleteMatchJobs(updatedBefore time.Time) (n int64, err error) {
+	err = e.DB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Where("job_id IN (?)", tx.Model(&model.MatchJob{}).
+				Select("id").
+				Where("updated_at < ?", updatedBefore)).
+			Delete(&model.MatchJobItem{}).Error; err != nil {
+			return err
+		}
+		result := tx.
+			Where("updated_at < ?", updatedBefore).
+			Delete(&model.MatchJob{})
+		n = result.RowsAffected
+		return result.Error
+	})
+	return
+}
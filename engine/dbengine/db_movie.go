@@ -6,7 +6,6 @@ import (
 
 	"gorm.io/gorm/clause"
 
-	"github.com/metatube-community/metatube-sdk-go/database"
 	"github.com/metatube-community/metatube-sdk-go/engine/providerid"
 	"github.com/metatube-community/metatube-sdk-go/model"
 )
@@ -15,6 +14,7 @@ type movieEngine interface {
 	GetMovieInfo(providerid.ProviderID) (*model.MovieInfo, error)
 	SaveMovieInfo(*model.MovieInfo) error
 	SearchMovie(string, MovieSearchOptions) ([]*model.MovieSearchResult, error)
+	FindMovies(keyword, provider string) ([]*model.MovieSearchResult, error)
 	GetMovieReviewInfo(providerid.ProviderID) (*model.MovieReviewInfo, error)
 	SaveMovieReviewInfo(*model.MovieReviewInfo) error
 }
@@ -25,19 +25,22 @@ func (e *engine) GetMovieInfo(pid providerid.ProviderID) (*model.MovieInfo, erro
 	info := &model.MovieInfo{}
 	err := e.DB().
 		Where( // Exact match here.
-			`provider COLLATE NOCASE = ? AND id COLLATE NOCASE = ?`,
+			e.dialect.equalFold("provider")+" AND "+e.dialect.equalFold("id"),
 			pid.Provider, pid.ID,
 		).First(info).Error
 	return info, err
 }
 
 func (e *engine) SaveMovieInfo(info *model.MovieInfo) error {
+	return e.saveMovieInfo(info, model.RevisionSourceScrape)
+}
+
+func (e *engine) saveMovieInfo(info *model.MovieInfo, source model.RevisionSource) error {
 	if !info.IsValid() {
 		return fmt.Errorf("invalid %T", info)
 	}
-	return e.DB().Clauses(clause.OnConflict{
-		UpdateAll: true,
-	}).Create(info).Error
+	return saveWithRevision(e.DB(), model.MovieRevision,
+		providerid.ProviderID{Provider: info.Provider, ID: info.ID}, source, info)
 }
 
 func (e *engine) SearchMovie(keyword string, opts MovieSearchOptions) ([]*model.MovieSearchResult, error) {
@@ -48,40 +51,15 @@ func (e *engine) SearchMovie(keyword string, opts MovieSearchOptions) ([]*model.
 
 	// provider filter.
 	if opts.Provider != "" {
-		tx = tx.Where(`provider COLLATE NOCASE = ?`, opts.Provider)
+		tx = tx.Where(e.dialect.equalFold(movieColumn("provider")), opts.Provider)
 	}
 
-	// Note: keyword can be an ID, a number, or a title, so we should
-	// query all of them for a better match. Also, it's case-insensitive.
-	pattern := "%" + keyword + "%"
-	if e.Driver() == database.Postgres {
-		tx = tx.Where(
-			`(
-			  number COLLATE NOCASE = ?
-			  OR id COLLATE NOCASE = ?
-			  OR number ILIKE ?
-			  OR title ILIKE ?
-			  OR similarity(number, ?) > ?
-			  OR similarity(title, ?) > ?
-			)`,
-			keyword, keyword,
-			pattern, pattern,
-			keyword, opts.Thresholds.Number,
-			keyword, opts.Thresholds.Title,
-		)
-	} else { // sqlite
-		tx = tx.Where(
-			`(
-			  number COLLATE NOCASE = ?
-			  OR id COLLATE NOCASE = ?
-			  OR number LIKE ? COLLATE NOCASE
-			  OR id LIKE ? COLLATE NOCASE
-			  OR title LIKE ? COLLATE NOCASE
-			)`,
-			keyword, keyword,
-			pattern, pattern, pattern,
-		)
-	}
+	// keyword filter and relevance order.
+	tx = e.dialect.searchMovie(tx, keyword, &opts)
+
+	// stable order for pagination.
+	tx = tx.Order(clause.OrderByColumn{Column: clause.Column{Table: clause.CurrentTable, Name: "provider"}}).
+		Order(clause.OrderByColumn{Column: clause.Column{Table: clause.CurrentTable, Name: "id"}})
 
 	// pagination.
 	if opts.Limit > 0 {
@@ -106,11 +84,35 @@ func (e *engine) SearchMovie(keyword string, opts MovieSearchOptions) ([]*model.
 	return results, nil
 }
 
+// FindMovies finds movies of which the number or id equals the keyword
+// case-insensitively, for internal lookups that must not return near
+// misses, an empty provider means all providers.
+func (e *engine) FindMovies(keyword, provider string) ([]*model.MovieSearchResult, error) {
+	tx := e.DB().Where(
+		e.dialect.equalFold("number")+" OR "+e.dialect.equalFold("id"),
+		keyword, keyword)
+	if provider != "" {
+		tx = tx.Where(e.dialect.equalFold("provider"), provider)
+	}
+	var infos []*model.MovieInfo
+	if err := tx.Order("provider").Order("id").Find(&infos).Error; err != nil {
+		return nil, err
+	}
+	results := make([]*model.MovieSearchResult, 0, len(infos))
+	for _, info := range infos {
+		if !info.IsValid() {
+			continue // normally it is valid, but just in case.
+		}
+		results = append(results, info.ToSearchResult())
+	}
+	return results, nil
+}
+
 func (e *engine) GetMovieReviewInfo(pid providerid.ProviderID) (*model.MovieReviewInfo, error) {
 	info := &model.MovieReviewInfo{}
 	err := e.DB().
 		Where( // Exact match here.
-			`provider COLLATE NOCASE = ? AND id COLLATE NOCASE = ?`,
+			e.dialect.equalFold("provider")+" AND "+e.dialect.equalFold("id"),
 			pid.Provider, pid.ID,
 		).First(info).Error
 	return info, err
@@ -0,0 +1,72 @@
+package dbengine
+
+import (
+	"fmt"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+type numberEngine interface {
+	GetNumberStatuses() ([]*model.NumberStatus, error)
+	GetNumberStatus(string) (*model.NumberStatus, error)
+	CreateNumberStatus(*model.NumberStatus) error
+	SaveNumberStatus(*model.NumberStatus) error
+	DeleteNumberStatus(string) error
+	GetMovieNumbers(string) ([]*model.MovieSearchResult, error)
+}
+
+var _ numberEngine = (*engine)(nil)
+
+// GetNumberStatuses returns all tracked number prefixes.
+func (e *engine) GetNumberStatuses() ([]*model.NumberStatus, error) {
+	var statuses []*model.NumberStatus
+	if err := e.DB().Order("number_prefix").Find(&statuses).Error; err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
+
+func (e *engine) GetNumberStatus(prefix string) (*model.NumberStatus, error) {
+	status := &model.NumberStatus{}
+	err := e.DB().
+		Where(e.dialect.equalFold("number_prefix"), prefix).
+		First(status).Error
+	return status, err
+}
+
+// CreateNumberStatus creates the number prefix, it fails if exists.
+func (e *engine) CreateNumberStatus(status *model.NumberStatus) error {
+	if !status.IsValid() {
+		return fmt.Errorf("invalid %T", status)
+	}
+	return e.DB().Create(status).Error
+}
+
+func (e *engine) SaveNumberStatus(status *model.NumberStatus) error {
+	if !status.IsValid() {
+		return fmt.Errorf("invalid %T", status)
+	}
+	return e.DB().Save(status).Error
+}
+
+func (e *engine) DeleteNumberStatus(prefix string) error {
+	return e.DB().
+		Where(e.dialect.equalFold("number_prefix"), prefix).
+		Delete(&model.NumberStatus{}).Error
+}
+
+// GetMovieNumbers returns the provider, id and number of the movies
+// whose numbers start with the prefix and a hyphen, case-insensitively.
+// It's a range of the number, '.' being the next character of '-', so
+// that the number index is used, and the prefix is matched literally.
+func (e *engine) GetMovieNumbers(prefix string) ([]*model.MovieSearchResult, error) {
+	var results []*model.MovieSearchResult
+	if err := e.DB().Model(&model.MovieInfo{}).
+		Select("provider", "id", "number").
+		Where(e.dialect.rangeFold("number"), prefix+"-", prefix+".").
+		Order("provider").
+		Find(&results).Error; err != nil {
+		return nil, err
+	}
+	return results, nil
+}
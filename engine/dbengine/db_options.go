@@ -9,7 +9,7 @@ type ActorSearchOptions struct {
 
 func (opts *ActorSearchOptions) applyDefaults() {
 	const (
-		maxLimit  = 20
+		maxLimit  = 100
 		threshold = 0.2 // be more tolerated for name search.
 	)
 	if opts.Threshold == 0 {
@@ -34,7 +34,7 @@ type MovieThresholds struct {
 
 func (opts *MovieSearchOptions) applyDefaults() {
 	const (
-		maxLimit        = 20
+		maxLimit        = 100
 		numberThreshold = 0.4
 		titleThreshold  = 0.2
 	)
@@ -0,0 +1,67 @@
+package dbengine
+
+import (
+	"fmt"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/metatube-community/metatube-sdk-go/engine/providerid"
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+type overrideEngine interface {
+	GetMovieOverride(providerid.ProviderID) (*model.MovieOverride, error)
+	SaveMovieOverride(*model.MovieOverride) error
+	DeleteMovieOverride(providerid.ProviderID) error
+	GetActorOverride(providerid.ProviderID) (*model.ActorOverride, error)
+	SaveActorOverride(*model.ActorOverride) error
+	DeleteActorOverride(providerid.ProviderID) error
+}
+
+var _ overrideEngine = (*engine)(nil)
+
+func (e *engine) GetMovieOverride(pid providerid.ProviderID) (*model.MovieOverride, error) {
+	override := &model.MovieOverride{}
+	err := e.DB().
+		Where(e.dialect.equalFold("provider")+" AND "+e.dialect.equalFold("id"), pid.Provider, pid.ID).
+		First(override).Error
+	return override, err
+}
+
+func (e *engine) SaveMovieOverride(override *model.MovieOverride) error {
+	if !override.IsValid() {
+		return fmt.Errorf("invalid %T", override)
+	}
+	return e.DB().Clauses(clause.OnConflict{
+		UpdateAll: true,
+	}).Create(override).Error
+}
+
+func (e *engine) DeleteMovieOverride(pid providerid.ProviderID) error {
+	return e.DB().
+		Where(e.dialect.equalFold("provider")+" AND "+e.dialect.equalFold("id"), pid.Provider, pid.ID).
+		Delete(&model.MovieOverride{}).Error
+}
+
+func (e *engine) GetActorOverride(pid providerid.ProviderID) (*model.ActorOverride, error) {
+	override := &model.ActorOverride{}
+	err := e.DB().
+		Where(e.dialect.equalFold("provider")+" AND "+e.dialect.equalFold("id"), pid.Provider, pid.ID).
+		First(override).Error
+	return override, err
+}
+
+func (e *engine) SaveActorOverride(override *model.ActorOverride) error {
+	if !override.IsValid() {
+		return fmt.Errorf("invalid %T", override)
+	}
+	return e.DB().Clauses(clause.OnConflict{
+		UpdateAll: true,
+	}).Create(override).Error
+}
+
+func (e *engine) DeleteActorOverride(pid providerid.ProviderID) error {
+	return e.DB().
+		Where(e.dialect.equalFold("provider")+" AND "+e.dialect.equalFold("id"), pid.Provider, pid.ID).
+		Delete(&model.ActorOverride{}).Error
+}
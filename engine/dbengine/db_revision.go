@@ -0,0 +1,239 @@
+package dbengine
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/metatube-community/metatube-sdk-go/engine/providerid"
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+// maxRevisions is the max number of revisions kept per record,
+// the oldest ones are pruned when exceeded.
+const maxRevisions = 50
+
+// volatileFields are the JSON fields that change on most scrapes without
+// meaningful edits, e.g. scores and signed preview URLs. Changes of them
+// are still saved, but don't make a revision on their own.
+var volatileFields = map[model.RevisionKind][]string{
+	model.MovieRevision: {"score", "preview_video_url", "preview_video_hls_url"},
+}
+
+type revisionEngine interface {
+	GetRevisions(model.RevisionKind, providerid.ProviderID, int, int) ([]*model.MetadataRevision, error)
+	RollbackMovieInfo(providerid.ProviderID, uint64) (*model.MovieInfo, error)
+	RollbackActorInfo(providerid.ProviderID, uint64) (*model.ActorInfo, error)
+}
+
+var _ revisionEngine = (*engine)(nil)
+
+// GetRevisions returns the revisions of a record, newest first.
+func (e *engine) GetRevisions(kind model.RevisionKind, pid providerid.ProviderID, limit, offset int) ([]*model.MetadataRevision, error) {
+	tx := e.DB().
+		Where("kind = ? AND "+e.dialect.equalFold("provider")+" AND "+e.dialect.equalFold("record_id"),
+			kind, pid.Provider, pid.ID).
+		Order("id DESC")
+	if limit > 0 {
+		tx = tx.Limit(limit)
+	}
+	if offset > 0 {
+		tx = tx.Offset(offset)
+	}
+	var revisions []*model.MetadataRevision
+	if err := tx.Find(&revisions).Error; err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// RollbackMovieInfo restores the movie info to the data of the revision,
+// which is recorded as a new revision, so it can be rolled back as well.
+func (e *engine) RollbackMovieInfo(pid providerid.ProviderID, revision uint64) (*model.MovieInfo, error) {
+	info := &model.MovieInfo{}
+	if err := e.rollback(model.MovieRevision, pid, revision, info); err != nil {
+		return nil, err
+	}
+	if err := e.saveMovieInfo(info, model.RevisionSourceRollback); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// RollbackActorInfo restores the actor info to the data of the revision.
+func (e *engine) RollbackActorInfo(pid providerid.ProviderID, revision uint64) (*model.ActorInfo, error) {
+	info := &model.ActorInfo{}
+	if err := e.rollback(model.ActorRevision, pid, revision, info); err != nil {
+		return nil, err
+	}
+	if err := e.saveActorInfo(info, model.RevisionSourceRollback); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// rollback decodes the data of the revision of the record into v.
+func (e *engine) rollback(kind model.RevisionKind, pid providerid.ProviderID, revision uint64, v any) error {
+	rev := &model.MetadataRevision{}
+	if err := e.DB().
+		Where("id = ? AND kind = ? AND "+e.dialect.equalFold("provider")+" AND "+e.dialect.equalFold("record_id"),
+			revision, kind, pid.Provider, pid.ID).
+		First(rev).Error; err != nil {
+		return err
+	}
+	return json.Unmarshal(rev.Data, v)
+}
+
+// saveWithRevision upserts the info, and records a revision in the same
+// transaction if any field has changed. Unchanged infos are still saved
+// to refresh their update time.
+func saveWithRevision[T any](db *gorm.DB, kind model.RevisionKind, pid providerid.ProviderID, source model.RevisionSource, info *T) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		return upsertWithRevision(tx, kind, pid, source, info, clause.OnConflict{UpdateAll: true})
+	})
+}
+
+// upsertWithRevision is saveWithRevision with the given conflict clause,
+// it must be called in a transaction.
+func upsertWithRevision[T any](tx *gorm.DB, kind model.RevisionKind, pid providerid.ProviderID, source model.RevisionSource, info *T, onConflict clause.OnConflict) error {
+	// both are read back from DB, so that they are compared
+	// in the same representation, e.g. dates and empty arrays.
+	prev, err := findExact[T](tx, pid)
+	if err != nil {
+		return err
+	}
+	if err = tx.Clauses(onConflict).Create(info).Error; err != nil {
+		return err
+	}
+	next, err := findExact[T](tx, pid)
+	if err != nil {
+		return err
+	}
+
+	changes, data, err := diffFields(prev, next)
+	if err != nil || len(changes) == 0 {
+		return err
+	}
+	if prev != nil && onlyVolatile(kind, changes) {
+		return nil
+	}
+	if prev != nil {
+		// keep the data saved before revisions were recorded.
+		if err = recordBaseline(tx, kind, pid, prev); err != nil {
+			return err
+		}
+	}
+	if err = tx.Create(&model.MetadataRevision{
+		Kind:     kind,
+		Provider: pid.Provider,
+		RecordID: pid.ID,
+		Source:   source,
+		Changes:  datatypes.NewJSONType(changes),
+		Data:     data,
+	}).Error; err != nil {
+		return err
+	}
+	return pruneRevisions(tx, kind, pid)
+}
+
+// onlyVolatile reports whether all the changed fields are volatile.
+func onlyVolatile(kind model.RevisionKind, changes map[string]*model.FieldChange) bool {
+	for key := range changes {
+		if !slices.Contains(volatileFields[kind], key) {
+			return false
+		}
+	}
+	return true
+}
+
+// pruneRevisions deletes the oldest revisions of the record beyond
+// maxRevisions. The boundary is looked up first, as MySQL can't
+// select from the table being deleted in a subquery.
+func pruneRevisions(tx *gorm.DB, kind model.RevisionKind, pid providerid.ProviderID) error {
+	var ids []uint64
+	if err := tx.Model(&model.MetadataRevision{}).
+		Where("kind = ? AND provider = ? AND record_id = ?", kind, pid.Provider, pid.ID).
+		Order("id DESC").
+		Offset(maxRevisions).
+		Limit(1).
+		Pluck("id", &ids).Error; err != nil || len(ids) == 0 {
+		return err
+	}
+	return tx.Where("kind = ? AND provider = ? AND record_id = ? AND id <= ?", kind, pid.Provider, pid.ID, ids[0]).
+		Delete(&model.MetadataRevision{}).Error
+}
+
+// findExact finds the record by the exact key, the same as the
+// conflict target of upserts, or returns nil if not found.
+func findExact[T any](tx *gorm.DB, pid providerid.ProviderID) (*T, error) {
+	var found []*T
+	if err := tx.Where("provider = ? AND id = ?", pid.Provider, pid.ID).Limit(1).Find(&found).Error; err != nil {
+		return nil, err
+	}
+	if len(found) == 0 {
+		return nil, nil
+	}
+	return found[0], nil
+}
+
+// recordBaseline records the current data of the record as a baseline
+// revision if it has no revisions yet.
+func recordBaseline[T any](tx *gorm.DB, kind model.RevisionKind, pid providerid.ProviderID, current *T) error {
+	var count int64
+	if err := tx.Model(&model.MetadataRevision{}).
+		Where("kind = ? AND provider = ? AND record_id = ?", kind, pid.Provider, pid.ID).
+		Count(&count).Error; err != nil || count > 0 {
+		return err
+	}
+	changes, data, err := diffFields(nil, current)
+	if err != nil {
+		return err
+	}
+	return tx.Create(&model.MetadataRevision{
+		Kind:     kind,
+		Provider: pid.Provider,
+		RecordID: pid.ID,
+		Source:   model.RevisionSourceBaseline,
+		Changes:  datatypes.NewJSONType(changes),
+		Data:     data,
+	}).Error
+}
+
+// diffFields compares the JSON fields of the previous and next values,
+// and returns the changed fields and the JSON of the next value. All
+// fields are changed if prev is nil.
+func diffFields[T any](prev, next *T) (map[string]*model.FieldChange, []byte, error) {
+	nextData, nextFields, err := jsonFields(next)
+	if err != nil {
+		return nil, nil, err
+	}
+	prevFields := make(map[string]json.RawMessage)
+	if prev != nil {
+		if _, prevFields, err = jsonFields(prev); err != nil {
+			return nil, nil, err
+		}
+	}
+	changes := make(map[string]*model.FieldChange)
+	for key, value := range nextFields {
+		if prevValue, ok := prevFields[key]; !ok || !bytes.Equal(prevValue, value) {
+			changes[key] = &model.FieldChange{Old: prevFields[key], New: value}
+		}
+	}
+	return changes, nextData, nil
+}
+
+func jsonFields(v any) ([]byte, map[string]json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, nil, err
+	}
+	fields := make(map[string]json.RawMessage)
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return nil, nil, err
+	}
+	return data, fields, nil
+}
@@ -0,0 +1,65 @@
+package dbengine
+
+import (
+	"gorm.io/gorm/clause"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+type settingEngine interface {
+	GetProviderSettings() ([]*model.ProviderSetting, error)
+	GetProviderSetting(typ, name string) (*model.ProviderSetting, error)
+	SaveProviderSetting(*model.ProviderSetting) error
+	DeleteProviderSetting(typ, name string) error
+	GetProviderSession(name string) (*model.ProviderSession, error)
+	SaveProviderSession(*model.ProviderSession) error
+}
+
+var _ settingEngine = (*engine)(nil)
+
+// GetProviderSettings returns all persisted provider settings, or
+// none if the table is not migrated yet.
+func (e *engine) GetProviderSettings() ([]*model.ProviderSetting, error) {
+	if !e.db.Migrator().HasTable(&model.ProviderSetting{}) {
+		return nil, nil
+	}
+	var settings []*model.ProviderSetting
+	if err := e.DB().Find(&settings).Error; err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (e *engine) GetProviderSetting(typ, name string) (*model.ProviderSetting, error) {
+	setting := &model.ProviderSetting{}
+	err := e.DB().
+		Where("type = ? AND name = ?", typ, name).
+		First(setting).Error
+	return setting, err
+}
+
+func (e *engine) SaveProviderSetting(setting *model.ProviderSetting) error {
+	return e.DB().Clauses(clause.OnConflict{
+		UpdateAll: true,
+	}).Create(setting).Error
+}
+
+func (e *engine) DeleteProviderSetting(typ, name string) error {
+	return e.DB().
+		Where("type = ? AND name = ?", typ, name).
+		Delete(&model.ProviderSetting{}).Error
+}
+
+func (e *engine) GetProviderSession(name string) (*model.ProviderSession, error) {
+	session := &model.ProviderSession{}
+	err := e.DB().
+		Where("provider = ?", name).
+		First(session).Error
+	return session, err
+}
+
+func (e *engine) SaveProviderSession(session *model.ProviderSession) error {
+	return e.DB().Clauses(clause.OnConflict{
+		UpdateAll: true,
+	}).Create(session).Error
+}
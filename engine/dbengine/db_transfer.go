@@ -0,0 +1,560 @@
+package dbengine
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/metatube-community/metatube-sdk-go/engine/providerid"
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+const (
+	// transferBatchSize is the number of rows read or written at a time.
+	transferBatchSize = 500
+	// maxRecordSize is the max size of a single NDJSON line.
+	maxRecordSize = 16 << 20
+	// maxImportErrors limits the errors of lines reported by an
+	// import, the others are counted only.
+	maxImportErrors = 100
+)
+
+var (
+	// ErrTruncatedInput is returned if the last line of the import is
+	// incomplete or the trailer is missing, e.g. the upload or the
+	// export is interrupted.
+	ErrTruncatedInput = errors.New("truncated input")
+	// ErrTrailerMismatch is returned if the records of the import don't
+	// match the trailer, e.g. the file is edited or corrupted.
+	ErrTrailerMismatch = errors.New("trailer mismatch")
+)
+
+// ConflictPolicy decides what to do when an imported row exists.
+type ConflictPolicy string
+
+const (
+	// ConflictOverwrite replaces the existing rows.
+	ConflictOverwrite ConflictPolicy = "overwrite"
+	// ConflictKeepNewer replaces the existing rows only if the
+	// imported ones were updated later.
+	ConflictKeepNewer ConflictPolicy = "keep-newer"
+	// ConflictSkip keeps the existing rows.
+	ConflictSkip ConflictPolicy = "skip"
+)
+
+// Record is a line of the NDJSON export. Timestamps are kept next to
+// the data, as they are not part of the JSON of the models.
+type Record struct {
+	Table     string          `json:"table"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+	Data      json.RawMessage `json:"data"`
+	// Trailer is set only by the last line of the export.
+	Trailer *Trailer `json:"trailer,omitempty"`
+	// line is the line number of the record in the import.
+	line int
+}
+
+// Trailer is the summary written after all records of the export, an
+// import is rejected without a matching one, so that an export which
+// failed halfway can't be taken as complete.
+type Trailer struct {
+	// Records is the number of records.
+	Records int `json:"records"`
+	// SHA256 is the hex checksum of all the record lines.
+	SHA256 string `json:"sha256"`
+}
+
+type ExportOptions struct {
+	// Tables to export, all by default.
+	Tables       []string
+	Provider     string
+	UpdatedSince time.Time
+}
+
+// ParseSince parses the time of ExportOptions.UpdatedSince, either a
+// date in local time, e.g. 2024-01-02, or an RFC 3339 time.
+func ParseSince(s string) (time.Time, error) {
+	if t, err := time.ParseInLocation(time.DateOnly, s, time.Local); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time, expect date or RFC 3339: %s", s)
+	}
+	return t, nil
+}
+
+type ImportOptions struct {
+	Policy ConflictPolicy
+	// Progress is called after every batch if not nil.
+	Progress func(ImportProgress)
+}
+
+// ImportProgress counts the records processed by an import, with the
+// errors of the invalid lines.
+type ImportProgress struct {
+	// Total is the number of records in the trailer.
+	Total    int            `json:"total"`
+	Read     int            `json:"read"`
+	Imported int            `json:"imported"`
+	Skipped  int            `json:"skipped"`
+	Invalid  int            `json:"invalid"`
+	Errors   []*ImportError `json:"errors,omitempty"`
+}
+
+// ImportError is the error of an invalid line.
+type ImportError struct {
+	Line  int    `json:"line"`
+	Table string `json:"table,omitempty"`
+	Error string `json:"error"`
+}
+
+func (p *ImportProgress) invalid(line int, table string, err error) {
+	p.Invalid++
+	if len(p.Errors) < maxImportErrors {
+		p.Errors = append(p.Errors, &ImportError{Line: line, Table: table, Error: err.Error()})
+	}
+}
+
+type transferEngine interface {
+	Export(io.Writer, ExportOptions) (int, error)
+	Import(io.Reader, ImportOptions) (*ImportProgress, error)
+}
+
+var _ transferEngine = (*engine)(nil)
+
+// TransferTables returns the names of the tables that can be
+// exported and imported.
+func TransferTables() []string {
+	names := make([]string, 0, len(transferTables))
+	for _, t := range transferTables {
+		names = append(names, t.name())
+	}
+	return names
+}
+
+// Export writes the rows of the tables as NDJSON records, in the order
+// of provider and id, followed by the trailer, and returns the number
+// of records. The trailer is not written if the export fails.
+func (e *engine) Export(w io.Writer, opts ExportOptions) (int, error) {
+	tables := transferTables
+	if len(opts.Tables) > 0 {
+		tables = nil
+		for _, name := range opts.Tables {
+			t := lookupTransferTable(name)
+			if t == nil {
+				return 0, fmt.Errorf("invalid table: %s", name)
+			}
+			tables = append(tables, t)
+		}
+	}
+	var (
+		total   int
+		hash    = sha256.New()
+		encoder = json.NewEncoder(io.MultiWriter(w, hash))
+	)
+	for _, t := range tables {
+		n, err := t.export(e.DB(), e.dialect, &opts, encoder)
+		total += n
+		if err != nil {
+			return total, fmt.Errorf("export %s: %w", t.name(), err)
+		}
+	}
+	if err := json.NewEncoder(w).Encode(&struct {
+		Trailer *Trailer `json:"trailer"`
+	}{&Trailer{
+		Records: total,
+		SHA256:  hex.EncodeToString(hash.Sum(nil)),
+	}}); err != nil {
+		return total, fmt.Errorf("export trailer: %w", err)
+	}
+	return total, nil
+}
+
+// Import reads NDJSON records and upserts them by the conflict policy.
+// The input is spooled and checked against the trailer first, so that
+// nothing is imported from an incomplete or mismatched export. Then
+// malformed, unknown or invalid records are counted and skipped, while
+// DB errors abort the import with the progress so far, of which the
+// batches flushed already are kept.
+func (e *engine) Import(r io.Reader, opts ImportOptions) (*ImportProgress, error) {
+	if opts.Policy == "" {
+		opts.Policy = ConflictOverwrite
+	}
+	if !slices.Contains([]ConflictPolicy{ConflictOverwrite, ConflictKeepNewer, ConflictSkip}, opts.Policy) {
+		return nil, fmt.Errorf("invalid conflict policy: %s", opts.Policy)
+	}
+
+	spool, err := os.CreateTemp("", "metatube-import-*.ndjson")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = spool.Close()
+		_ = os.Remove(spool.Name())
+	}()
+	trailer, err := verifyTrailer(r, spool)
+	if err != nil {
+		return &ImportProgress{}, err
+	}
+	if _, err = spool.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	var (
+		progress = &ImportProgress{Total: trailer.Records}
+		pending  = make(map[transferer][]*Record)
+	)
+	report := func() {
+		if opts.Progress != nil {
+			opts.Progress(*progress)
+		}
+	}
+	flush := func(t transferer) error {
+		if len(pending[t]) == 0 {
+			return nil
+		}
+		if err := e.DB().Transaction(func(tx *gorm.DB) error {
+			return t.importBatch(tx, pending[t], opts.Policy, progress)
+		}); err != nil {
+			return fmt.Errorf("import %s: %w", t.name(), err)
+		}
+		pending[t] = pending[t][:0]
+		report()
+		return nil
+	}
+	report() // verified.
+
+	var (
+		line    int
+		scanner = bufio.NewScanner(spool)
+	)
+	scanner.Buffer(nil, maxRecordSize)
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue // skip empty lines.
+		}
+		record := &Record{line: line}
+		if err := json.Unmarshal(scanner.Bytes(), record); err != nil {
+			progress.Read++
+			progress.invalid(line, "", err)
+			continue
+		}
+		if record.Trailer != nil {
+			break // verified already.
+		}
+		progress.Read++
+		t := lookupTransferTable(record.Table)
+		if t == nil {
+			progress.invalid(line, record.Table, fmt.Errorf("unknown table: %s", record.Table))
+			continue
+		}
+		if pending[t] = append(pending[t], record); len(pending[t]) >= transferBatchSize {
+			if err := flush(t); err != nil {
+				return progress, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return progress, err
+	}
+	for _, t := range transferTables {
+		if err := flush(t); err != nil {
+			return progress, err
+		}
+	}
+	return progress, nil
+}
+
+// verifyTrailer copies the input to w, and checks the record lines
+// against the trailer, which must be the last non-empty line.
+func verifyTrailer(r io.Reader, w io.Writer) (*Trailer, error) {
+	var (
+		records int
+		last    []byte
+		hash    = sha256.New()
+		scanner = bufio.NewScanner(io.TeeReader(r, w))
+	)
+	scanner.Buffer(nil, maxRecordSize)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue // skip empty lines.
+		}
+		if last != nil {
+			hash.Write(last)
+			hash.Write([]byte{'\n'})
+			records++
+		}
+		last = append(last[:0], scanner.Bytes()...)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	record := &Record{}
+	if last == nil || json.Unmarshal(last, record) != nil || record.Trailer == nil {
+		return nil, fmt.Errorf("missing trailer: %w", ErrTruncatedInput)
+	}
+	if record.Trailer.Records != records ||
+		record.Trailer.SHA256 != hex.EncodeToString(hash.Sum(nil)) {
+		return nil, fmt.Errorf("%d records read, %d expected: %w",
+			records, record.Trailer.Records, ErrTrailerMismatch)
+	}
+	return record.Trailer, nil
+}
+
+// transferer exports and imports the rows of a table.
+type transferer interface {
+	name() string
+	export(tx *gorm.DB, d dialect, opts *ExportOptions, encoder *json.Encoder) (int, error)
+	importBatch(tx *gorm.DB, records []*Record, policy ConflictPolicy, progress *ImportProgress) error
+}
+
+var transferTables = []transferer{
+	&transferTable[model.MovieInfo]{
+		table:    model.MovieMetadataTableName,
+		key:      func(m *model.MovieInfo) (string, string) { return m.Provider, m.ID },
+		times:    func(m *model.MovieInfo) *model.TimeTracker { return &m.TimeTracker },
+		valid:    (*model.MovieInfo).IsValid,
+		revision: model.MovieRevision,
+	},
+	&transferTable[model.ActorInfo]{
+		table:    model.ActorMetadataTableName,
+		key:      func(a *model.ActorInfo) (string, string) { return a.Provider, a.ID },
+		times:    func(a *model.ActorInfo) *model.TimeTracker { return &a.TimeTracker },
+		valid:    (*model.ActorInfo).IsValid,
+		revision: model.ActorRevision,
+	},
+	&transferTable[model.MovieReviewInfo]{
+		table: model.MovieReviewsTableName,
+		key:   func(m *model.MovieReviewInfo) (string, string) { return m.Provider, m.ID },
+		times: func(m *model.MovieReviewInfo) *model.TimeTracker { return &m.TimeTracker },
+		valid: (*model.MovieReviewInfo).IsValid,
+	},
+	&transferTable[model.MovieOverride]{
+		table: model.MovieOverridesTableName,
+		key:   func(o *model.MovieOverride) (string, string) { return o.Provider, o.ID },
+		times: func(o *model.MovieOverride) *model.TimeTracker { return &o.TimeTracker },
+		valid: (*model.MovieOverride).IsValid,
+	},
+	&transferTable[model.ActorOverride]{
+		table: model.ActorOverridesTableName,
+		key:   func(o *model.ActorOverride) (string, string) { return o.Provider, o.ID },
+		times: func(o *model.ActorOverride) *model.TimeTracker { return &o.TimeTracker },
+		valid: (*model.ActorOverride).IsValid,
+	},
+}
+
+func lookupTransferTable(name string) transferer {
+	for _, t := range transferTables {
+		if t.name() == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// transferTable is a metadata table keyed by provider and id.
+type transferTable[T any] struct {
+	table string
+	key   func(*T) (provider, id string)
+	times func(*T) *model.TimeTracker
+	valid func(*T) bool
+	// revision is the kind of the revisions recorded on changes,
+	// empty if the table has no revisions.
+	revision model.RevisionKind
+}
+
+func (t *transferTable[T]) name() string {
+	return t.table
+}
+
+// export pages through the rows by provider and id, so that no
+// connection is held while the records are being written.
+func (t *transferTable[T]) export(tx *gorm.DB, d dialect, opts *ExportOptions, encoder *json.Encoder) (int, error) {
+	tx = tx.Model(new(T))
+	if opts.Provider != "" {
+		tx = tx.Where(d.equalFold("provider"), opts.Provider)
+	}
+	if !opts.UpdatedSince.IsZero() {
+		tx = tx.Where("updated_at >= ?", opts.UpdatedSince)
+	}
+
+	var (
+		n            int
+		provider, id string
+	)
+	for {
+		q := tx.Session(&gorm.Session{})
+		if n > 0 {
+			q = q.Where("(provider > ? OR (provider = ? AND id > ?))", provider, provider, id)
+		}
+		var rows []*T
+		if err := q.Order("provider").Order("id").Limit(transferBatchSize).Find(&rows).Error; err != nil {
+			return n, err
+		}
+		for _, row := range rows {
+			data, err := json.Marshal(row)
+			if err != nil {
+				return n, err
+			}
+			times := t.times(row)
+			if err = encoder.Encode(&Record{
+				Table:     t.table,
+				CreatedAt: times.CreatedAt,
+				UpdatedAt: times.UpdatedAt,
+				Data:      data,
+			}); err != nil {
+				return n, err
+			}
+			n++
+		}
+		if len(rows) < transferBatchSize {
+			return n, nil
+		}
+		provider, id = t.key(rows[len(rows)-1])
+	}
+}
+
+func (t *transferTable[T]) importBatch(tx *gorm.DB, records []*Record, policy ConflictPolicy, progress *ImportProgress) error {
+	var (
+		rows  = make([]*T, 0, len(records))
+		index = make(map[string]int, len(records))
+	)
+	for _, record := range records {
+		row := new(T)
+		if err := json.Unmarshal(record.Data, row); err != nil {
+			progress.invalid(record.line, t.table, err)
+			continue
+		}
+		if !t.valid(row) {
+			progress.invalid(record.line, t.table, errors.New("invalid record"))
+			continue
+		}
+		// zero timestamps are set to now on create.
+		times := t.times(row)
+		times.CreatedAt, times.UpdatedAt = record.CreatedAt, record.UpdatedAt
+		// the last one of the same key wins, as a row can't
+		// be affected twice by an upsert statement.
+		key := t.keyOf(row)
+		if i, ok := index[key]; ok {
+			rows[i] = row
+			progress.Skipped++
+			continue
+		}
+		index[key] = len(rows)
+		rows = append(rows, row)
+	}
+
+	if (policy == ConflictKeepNewer || policy == ConflictSkip && t.revision != "") && len(rows) > 0 {
+		existing, err := t.updatedAt(tx, rows)
+		if err != nil {
+			return err
+		}
+		kept := rows[:0]
+		for _, row := range rows {
+			if updatedAt, ok := existing[t.keyOf(row)]; ok &&
+				(policy == ConflictSkip || !t.times(row).UpdatedAt.After(updatedAt)) {
+				progress.Skipped++
+				continue
+			}
+			kept = append(kept, row)
+		}
+		rows = kept
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	onConflict := clause.OnConflict{DoNothing: true}
+	if policy != ConflictSkip || t.revision != "" {
+		columns, err := t.updateColumns(tx)
+		if err != nil {
+			return err
+		}
+		onConflict = clause.OnConflict{DoUpdates: clause.AssignmentColumns(columns)}
+	}
+	if t.revision != "" {
+		// saved one by one, so that the changes are recorded
+		// the same as scraped ones.
+		for _, row := range rows {
+			provider, id := t.key(row)
+			pid := providerid.ProviderID{Provider: provider, ID: id}
+			if err := upsertWithRevision(tx, t.revision, pid, model.RevisionSourceImport, row, onConflict); err != nil {
+				return err
+			}
+		}
+		progress.Imported += len(rows)
+		return nil
+	}
+	result := tx.Clauses(onConflict).Create(rows)
+	if result.Error != nil {
+		return result.Error
+	}
+	if policy == ConflictSkip {
+		// only the inserted rows are affected.
+		progress.Imported += int(result.RowsAffected)
+		progress.Skipped += len(rows) - int(result.RowsAffected)
+	} else {
+		progress.Imported += len(rows)
+	}
+	return nil
+}
+
+func (t *transferTable[T]) keyOf(row *T) string {
+	provider, id := t.key(row)
+	return provider + "\x00" + id
+}
+
+// updateColumns returns the columns to update on conflict. Unlike
+// UpdateAll, updated_at is kept as imported instead of set to now.
+func (t *transferTable[T]) updateColumns(tx *gorm.DB) ([]string, error) {
+	stmt := &gorm.Statement{DB: tx}
+	if err := stmt.Parse(new(T)); err != nil {
+		return nil, err
+	}
+	var columns []string
+	for _, field := range stmt.Schema.Fields {
+		if field.DBName != "" && !field.PrimaryKey && field.AutoCreateTime == 0 {
+			columns = append(columns, field.DBName)
+		}
+	}
+	return columns, nil
+}
+
+// updatedAt returns the update times of the existing rows by their keys.
+func (t *transferTable[T]) updatedAt(tx *gorm.DB, rows []*T) (map[string]time.Time, error) {
+	var providers, ids []string
+	for _, row := range rows {
+		provider, id := t.key(row)
+		providers, ids = append(providers, provider), append(ids, id)
+	}
+	var results []struct {
+		Provider  string
+		ID        string
+		UpdatedAt time.Time
+	}
+	// a superset of the keys, which is filtered by the map lookups.
+	if err := tx.Model(new(T)).
+		Select("provider", "id", "updated_at").
+		Where("provider IN ? AND id IN ?", slices.Compact(slices.Sorted(slices.Values(providers))), ids).
+		Scan(&results).Error; err != nil {
+		return nil, err
+	}
+	existing := make(map[string]time.Time, len(results))
+	for _, result := range results {
+		existing[result.Provider+"\x00"+result.ID] = result.UpdatedAt
+	}
+	return existing, nil
+}
@@ -1,24 +1,32 @@
 package dbengine
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"database/sql"
 	_ "embed"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"io"
 	"log"
 	"net"
+	"net/http"
 	"net/url"
 	"slices"
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"github.com/ory/dockertest"
 	"github.com/ory/dockertest/docker"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"gorm.io/datatypes"
+	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 
 	"github.com/metatube-community/metatube-sdk-go/common/parser"
@@ -61,6 +69,11 @@ func TestDBEngineTestSuite(t *testing.T) {
 			typ: database.Postgres,
 			dsn: postgresDSN,
 		},
+		{
+			// MySQL
+			typ: database.MySQL,
+			dsn: mysqlDSN,
+		},
 	}
 
 	for _, s := range suites {
@@ -79,7 +92,7 @@ func (s *DBEngineTestSuite) SetupSuite() {
 	s.Require().NoError(err)
 
 	s.eng = New(db)
-	err = s.eng.AutoMigrate()
+	err = s.eng.Migrate()
 	s.Require().NoError(err)
 }
 
@@ -148,6 +161,18 @@ func (s *DBEngineTestSuite) TestActor() {
 		assert.Equal(t, "25352", actors[1].ID)
 	})
 
+	s.T().Run("find actor by name (exact)", func(t *testing.T) {
+		actors, err := s.eng.FindActors("加藤あやの", "av-league")
+		require.NoError(t, err)
+		require.Len(t, actors, 1)
+		assert.Equal(t, "2477", actors[0].ID)
+
+		// no near misses.
+		actors, err = s.eng.FindActors("加藤", "")
+		require.NoError(t, err)
+		assert.Empty(t, actors)
+	})
+
 	s.T().Run("search actor by name (fuzzer)", func(t *testing.T) {
 		actors, err := s.eng.SearchActor("三", ActorSearchOptions{Threshold: 0.1})
 		require.NoError(t, err)
@@ -160,6 +185,21 @@ func (s *DBEngineTestSuite) TestActor() {
 		require.Len(t, actors, 2)
 	})
 
+	s.T().Run("search actor by alias", func(t *testing.T) {
+		err := s.eng.SaveActorInfo(&model.ActorInfo{
+			ID:       "999999",
+			Name:     "別名テスト",
+			Provider: "AV-LEAGUE",
+			Homepage: "https://example.com/999999",
+			Aliases:  []string{"和久井かな", "西島沙織"},
+		})
+		require.NoError(t, err)
+		actors, err := s.eng.SearchActor("和久井かな", ActorSearchOptions{})
+		require.NoError(t, err)
+		require.Len(t, actors, 1)
+		assert.Equal(t, "999999", actors[0].ID)
+	})
+
 	s.T().Run("search actor by name (not found)", func(t *testing.T) {
 		actors, err := s.eng.SearchActor("无名氏", ActorSearchOptions{})
 		require.NoError(t, err)
@@ -239,6 +279,27 @@ func (s *DBEngineTestSuite) TestMovie() {
 		t.Log(jsonify(movies))
 	})
 
+	s.T().Run("find movie by number or id (exact)", func(t *testing.T) {
+		movies, err := s.eng.FindMovies("sdmf-033", "")
+		require.NoError(t, err)
+		require.Len(t, movies, 1)
+		assert.Equal(t, "1sdmf00033", movies[0].ID)
+
+		movies, err = s.eng.FindMovies("HEYZO-0825", "heyzo")
+		require.NoError(t, err)
+		require.Len(t, movies, 1)
+		assert.Equal(t, "0825", movies[0].ID)
+
+		movies, err = s.eng.FindMovies("HEYZO-0825", "FANZA")
+		require.NoError(t, err)
+		assert.Empty(t, movies)
+
+		// no near misses.
+		movies, err = s.eng.FindMovies("HEYZO-0", "")
+		require.NoError(t, err)
+		assert.Empty(t, movies)
+	})
+
 	s.T().Run("search movie by title (fuzz)", func(t *testing.T) {
 		movies, err := s.eng.SearchMovie("密着誘惑してくるささやき淫語お姉", MovieSearchOptions{})
 		require.NoError(t, err)
@@ -254,6 +315,13 @@ func (s *DBEngineTestSuite) TestMovie() {
 		t.Log(jsonify(movies))
 	})
 
+	s.T().Run("search movie by summary", func(t *testing.T) {
+		movies, err := s.eng.SearchMovie("両親が不在中", MovieSearchOptions{})
+		require.NoError(t, err)
+		require.Len(t, movies, 1)
+		assert.Equal(t, "h_127ysn552", movies[0].ID)
+	})
+
 	s.T().Run("search movie by title (fuzzer)", func(t *testing.T) {
 		if s.typ != database.Postgres {
 			t.SkipNow()
@@ -327,11 +395,578 @@ func (s *DBEngineTestSuite) TestMovie_Reviews() {
 	})
 }
 
+func (s *DBEngineTestSuite) TestTransfer() {
+	const provider = "TRANSFER"
+	movies := []*model.MovieInfo{
+		{
+			ID:       "tr001",
+			Number:   "TR-001",
+			Title:    "Transfer One",
+			Provider: provider,
+			Homepage: "https://example.com/tr001",
+			CoverURL: "https://example.com/tr001.jpg",
+			Actors:   pq.StringArray{"A", "B"},
+		},
+		{
+			ID:       "tr002",
+			Number:   "TR-002",
+			Title:    "Transfer Two",
+			Provider: provider,
+			Homepage: "https://example.com/tr002",
+			CoverURL: "https://example.com/tr002.jpg",
+		},
+	}
+	for _, movie := range movies {
+		s.Require().NoError(s.eng.SaveMovieInfo(movie))
+	}
+	s.Require().NoError(s.eng.SaveActorInfo(&model.ActorInfo{
+		ID:       "tr001",
+		Name:     "Transfer Actor",
+		Provider: provider,
+		Homepage: "https://example.com/actor/tr001",
+		Aliases:  pq.StringArray{"Alias"},
+	}))
+	s.Require().NoError(s.eng.SaveMovieReviewInfo(&model.MovieReviewInfo{
+		ID:       "tr001",
+		Provider: provider,
+		Reviews: datatypes.NewJSONType([]*model.MovieReviewDetail{
+			{Author: "Reviewer", Comment: "Good"},
+		}),
+	}))
+
+	var exported bytes.Buffer
+	s.T().Run("export by provider", func(t *testing.T) {
+		n, err := s.eng.Export(&exported, ExportOptions{Provider: strings.ToLower(provider)})
+		require.NoError(t, err)
+		assert.Equal(t, 4, n)
+		lines := strings.Split(strings.TrimSpace(exported.String()), "\n")
+		require.Len(t, lines, 5)
+		record := &Record{}
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), record))
+		assert.Equal(t, model.MovieMetadataTableName, record.Table)
+		assert.False(t, record.UpdatedAt.IsZero())
+		assert.Contains(t, string(record.Data), `"tr001"`)
+		assert.Nil(t, record.Trailer)
+		// the trailer is the last line.
+		assert.Equal(t, withTrailer(lines[:4]...), exported.String())
+	})
+
+	s.T().Run("export by table and time", func(t *testing.T) {
+		n, err := s.eng.Export(io.Discard, ExportOptions{
+			Tables:   []string{model.MovieReviewsTableName},
+			Provider: provider,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+
+		n, err = s.eng.Export(io.Discard, ExportOptions{
+			Provider:     provider,
+			UpdatedSince: time.Now().Add(time.Hour),
+		})
+		require.NoError(t, err)
+		assert.Zero(t, n)
+
+		_, err = s.eng.Export(io.Discard, ExportOptions{Tables: []string{"unknown"}})
+		assert.Error(t, err)
+	})
+
+	// changed after the export.
+	changed := *movies[0]
+	changed.Title = "Transfer Changed"
+	s.Require().NoError(s.eng.SaveMovieInfo(&changed))
+
+	s.T().Run("import keep newer", func(t *testing.T) {
+		progress, err := s.eng.Import(bytes.NewReader(exported.Bytes()), ImportOptions{Policy: ConflictKeepNewer})
+		require.NoError(t, err)
+		assert.Equal(t, &ImportProgress{Total: 4, Read: 4, Skipped: 4}, progress)
+		got, err := s.eng.GetMovieInfo(providerid.ProviderID{Provider: provider, ID: "tr001"})
+		require.NoError(t, err)
+		assert.Equal(t, "Transfer Changed", got.Title)
+	})
+
+	s.T().Run("import skip", func(t *testing.T) {
+		progress, err := s.eng.Import(bytes.NewReader(exported.Bytes()), ImportOptions{Policy: ConflictSkip})
+		require.NoError(t, err)
+		assert.Equal(t, &ImportProgress{Total: 4, Read: 4, Skipped: 4}, progress)
+	})
+
+	s.T().Run("import overwrite", func(t *testing.T) {
+		before, err := s.eng.GetMovieInfo(providerid.ProviderID{Provider: provider, ID: "tr001"})
+		require.NoError(t, err)
+
+		var batches int
+		progress, err := s.eng.Import(bytes.NewReader(exported.Bytes()), ImportOptions{
+			Progress: func(ImportProgress) { batches++ },
+		})
+		require.NoError(t, err)
+		assert.Equal(t, &ImportProgress{Total: 4, Read: 4, Imported: 4}, progress)
+		assert.Equal(t, 4, batches) // once verified, and one batch per table.
+		got, err := s.eng.GetMovieInfo(providerid.ProviderID{Provider: provider, ID: "tr001"})
+		require.NoError(t, err)
+		assert.Equal(t, "Transfer One", got.Title)
+		assert.Equal(t, pq.StringArray{"A", "B"}, got.Actors)
+		assert.True(t, got.UpdatedAt.Before(before.UpdatedAt), "updated_at is kept as exported")
+
+		revisions, err := s.eng.GetRevisions(model.MovieRevision, providerid.ProviderID{Provider: provider, ID: "tr001"}, 1, 0)
+		require.NoError(t, err)
+		require.Len(t, revisions, 1)
+		assert.Equal(t, model.RevisionSourceImport, revisions[0].Source)
+		assert.Contains(t, revisions[0].Changes.Data(), "title")
+	})
+
+	s.T().Run("import duplicates", func(t *testing.T) {
+		lines := strings.Split(strings.TrimSpace(exported.String()), "\n")
+		record := &Record{}
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), record))
+		record.Data = bytes.Replace(record.Data, []byte("Transfer One"), []byte("Transfer Last"), 1)
+		last, err := json.Marshal(record)
+		require.NoError(t, err)
+
+		input := withTrailer(lines[0], lines[0], string(last))
+		progress, err := s.eng.Import(strings.NewReader(input), ImportOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, &ImportProgress{Total: 3, Read: 3, Imported: 1, Skipped: 2}, progress)
+		got, err := s.eng.GetMovieInfo(providerid.ProviderID{Provider: provider, ID: "tr001"})
+		require.NoError(t, err)
+		assert.Equal(t, "Transfer Last", got.Title)
+	})
+
+	s.T().Run("import invalid", func(t *testing.T) {
+		input := withTrailer(
+			`garbage`,
+			`{"table":"unknown","data":{}}`,
+			`{"table":"movie_metadata","data":{"id":"tr003","provider":"TRANSFER"}}`,
+		)
+		progress, err := s.eng.Import(strings.NewReader(input), ImportOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 3, progress.Read)
+		assert.Equal(t, 3, progress.Invalid)
+		require.Len(t, progress.Errors, 3)
+		for i, e := range progress.Errors {
+			assert.Equal(t, i+1, e.Line)
+			assert.NotEmpty(t, e.Error)
+		}
+		assert.Equal(t, "unknown", progress.Errors[1].Table)
+		assert.Equal(t, &ImportError{Line: 3, Table: "movie_metadata", Error: "invalid record"}, progress.Errors[2])
+
+		_, err = s.eng.Import(strings.NewReader(input), ImportOptions{Policy: "unknown"})
+		assert.Error(t, err)
+	})
+
+	s.T().Run("import truncated", func(t *testing.T) {
+		lines := strings.Split(strings.TrimSpace(exported.String()), "\n")
+		// the trailer without a newline is fine.
+		progress, err := s.eng.Import(strings.NewReader(strings.TrimSpace(exported.String())), ImportOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 4, progress.Imported)
+
+		// nothing is imported without the trailer.
+		for _, input := range []string{
+			strings.Join(lines[:4], "\n") + "\n",
+			lines[0] + "\n" + lines[1][:len(lines[1])/2],
+			"",
+		} {
+			progress, err = s.eng.Import(strings.NewReader(input), ImportOptions{})
+			assert.ErrorIs(t, err, ErrTruncatedInput)
+			assert.Zero(t, progress.Read)
+		}
+	})
+
+	s.T().Run("import mismatched", func(t *testing.T) {
+		lines := strings.Split(strings.TrimSpace(exported.String()), "\n")
+		for _, input := range []string{
+			// a record is lost.
+			strings.Join(append(lines[1:4:4], lines[4]), "\n"),
+			// a record is edited.
+			strings.Replace(exported.String(), "Transfer One", "Transfer Edited", 1),
+		} {
+			progress, err := s.eng.Import(strings.NewReader(input), ImportOptions{})
+			assert.ErrorIs(t, err, ErrTrailerMismatch)
+			assert.Zero(t, progress.Read)
+		}
+	})
+}
+
+func (s *DBEngineTestSuite) TestRevision() {
+	const provider = "REVISION"
+	pid := providerid.ProviderID{Provider: provider, ID: "rv001"}
+	jst := time.FixedZone("JST", 9*60*60)
+	info := &model.MovieInfo{
+		ID:          pid.ID,
+		Number:      "RV-001",
+		Title:       "Original",
+		Provider:    provider,
+		Homepage:    "https://example.com/rv001",
+		CoverURL:    "https://example.com/rv001.jpg",
+		ReleaseDate: datatypes.Date(time.Date(2020, 1, 2, 0, 0, 0, 0, jst)),
+	}
+	s.Require().NoError(s.eng.SaveMovieInfo(info))
+
+	s.T().Run("unchanged", func(t *testing.T) {
+		same := *info
+		require.NoError(t, s.eng.SaveMovieInfo(&same))
+		revisions, err := s.eng.GetRevisions(model.MovieRevision, pid, 0, 0)
+		require.NoError(t, err)
+		require.Len(t, revisions, 1)
+		assert.Equal(t, model.RevisionSourceScrape, revisions[0].Source)
+		assert.Contains(t, revisions[0].Changes.Data(), "title")
+		assert.Nil(t, revisions[0].Changes.Data()["title"].Old)
+	})
+
+	s.T().Run("changed", func(t *testing.T) {
+		changed := *info
+		changed.Title = "Garbage"
+		require.NoError(t, s.eng.SaveMovieInfo(&changed))
+		revisions, err := s.eng.GetRevisions(model.MovieRevision,
+			providerid.ProviderID{Provider: "revision", ID: "RV001"}, 0, 0)
+		require.NoError(t, err)
+		require.Len(t, revisions, 2)
+		changes := revisions[0].Changes.Data()
+		require.Len(t, changes, 1)
+		assert.JSONEq(t, `"Original"`, string(changes["title"].Old))
+		assert.JSONEq(t, `"Garbage"`, string(changes["title"].New))
+	})
+
+	s.T().Run("rollback", func(t *testing.T) {
+		revisions, err := s.eng.GetRevisions(model.MovieRevision, pid, 0, 0)
+		require.NoError(t, err)
+		got, err := s.eng.RollbackMovieInfo(pid, revisions[len(revisions)-1].ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Original", got.Title)
+
+		got, err = s.eng.GetMovieInfo(pid)
+		require.NoError(t, err)
+		assert.Equal(t, "Original", got.Title)
+
+		revisions, err = s.eng.GetRevisions(model.MovieRevision, pid, 1, 0)
+		require.NoError(t, err)
+		require.Len(t, revisions, 1)
+		assert.Equal(t, model.RevisionSourceRollback, revisions[0].Source)
+
+		_, err = s.eng.RollbackMovieInfo(pid, 1<<40)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		_, err = s.eng.RollbackActorInfo(pid, revisions[0].ID)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	s.T().Run("baseline", func(t *testing.T) {
+		actor := &model.ActorInfo{
+			ID:       "rv001",
+			Name:     "Untracked",
+			Provider: provider,
+			Homepage: "https://example.com/actor/rv001",
+		}
+		// saved before revisions were recorded.
+		require.NoError(t, s.eng.(*engine).DB().Create(actor).Error)
+
+		changed := *actor
+		changed.Name = "Tracked"
+		require.NoError(t, s.eng.SaveActorInfo(&changed))
+		revisions, err := s.eng.GetRevisions(model.ActorRevision, pid, 0, 0)
+		require.NoError(t, err)
+		require.Len(t, revisions, 2)
+		assert.Equal(t, model.RevisionSourceScrape, revisions[0].Source)
+		assert.Equal(t, model.RevisionSourceBaseline, revisions[1].Source)
+
+		got, err := s.eng.RollbackActorInfo(pid, revisions[1].ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Untracked", got.Name)
+	})
+
+	s.T().Run("volatile", func(t *testing.T) {
+		before, err := s.eng.GetRevisions(model.MovieRevision, pid, 0, 0)
+		require.NoError(t, err)
+		changed := *info
+		changed.Score = 4.5
+		changed.PreviewVideoURL = "https://example.com/rv001.mp4?token=1"
+		require.NoError(t, s.eng.SaveMovieInfo(&changed))
+		revisions, err := s.eng.GetRevisions(model.MovieRevision, pid, 0, 0)
+		require.NoError(t, err)
+		assert.Len(t, revisions, len(before))
+		got, err := s.eng.GetMovieInfo(pid)
+		require.NoError(t, err)
+		assert.Equal(t, 4.5, got.Score)
+	})
+
+	s.T().Run("pruned", func(t *testing.T) {
+		changed := *info
+		for i := range maxRevisions + 5 {
+			changed.Title = fmt.Sprintf("Title %d", i)
+			require.NoError(t, s.eng.SaveMovieInfo(&changed))
+		}
+		revisions, err := s.eng.GetRevisions(model.MovieRevision, pid, 0, 0)
+		require.NoError(t, err)
+		require.Len(t, revisions, maxRevisions)
+		assert.JSONEq(t, fmt.Sprintf(`"Title %d"`, maxRevisions+4), string(revisions[0].Changes.Data()["title"].New))
+	})
+}
+
+func (s *DBEngineTestSuite) TestOverride() {
+	pid := providerid.ProviderID{Provider: "OVERRIDE", ID: "ov001"}
+	s.Require().NoError(s.eng.SaveMovieOverride(&model.MovieOverride{
+		ID:       pid.ID,
+		Provider: pid.Provider,
+		Fields: datatypes.NewJSONType(model.OverrideFields{
+			"title": json.RawMessage(`"Fixed"`),
+		}),
+	}))
+
+	s.T().Run("get override (case-insensitive)", func(t *testing.T) {
+		got, err := s.eng.GetMovieOverride(providerid.ProviderID{Provider: "override", ID: "OV001"})
+		require.NoError(t, err)
+		assert.JSONEq(t, `"Fixed"`, string(got.Fields.Data()["title"]))
+
+		_, err = s.eng.GetActorOverride(pid)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	s.T().Run("export override", func(t *testing.T) {
+		n, err := s.eng.Export(io.Discard, ExportOptions{Provider: pid.Provider})
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+	})
+
+	s.T().Run("save invalid override", func(t *testing.T) {
+		err := s.eng.SaveMovieOverride(&model.MovieOverride{ID: pid.ID, Provider: pid.Provider})
+		assert.Error(t, err)
+	})
+
+	s.T().Run("delete override", func(t *testing.T) {
+		require.NoError(t, s.eng.DeleteMovieOverride(providerid.ProviderID{Provider: "override", ID: "ov001"}))
+		_, err := s.eng.GetMovieOverride(pid)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
+
+func (s *DBEngineTestSuite) TestNumberStatus() {
+	s.Require().NoError(s.eng.CreateNumberStatus(&model.NumberStatus{
+		NumberPrefix: "NUM",
+		Digits:       4,
+		MinSuffix:    1,
+		MaxSuffix:    9999,
+	}))
+	s.Assert().Error(s.eng.CreateNumberStatus(&model.NumberStatus{
+		NumberPrefix: "NUM",
+		Digits:       3,
+		MinSuffix:    1,
+		MaxSuffix:    999,
+	}), "duplicate prefix")
+
+	s.T().Run("get number status (case-insensitive)", func(t *testing.T) {
+		got, err := s.eng.GetNumberStatus("num")
+		require.NoError(t, err)
+		assert.Equal(t, 4, got.Digits)
+		assert.Equal(t, "NUM-0012", got.Number(12))
+
+		_, err = s.eng.GetNumberStatus("NONE")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	s.T().Run("save number status", func(t *testing.T) {
+		got, err := s.eng.GetNumberStatus("NUM")
+		require.NoError(t, err)
+		got.LastFound, got.Status = 20, model.NumberStatusPaused
+		got.Gaps = datatypes.NewJSONType([]int{3, 7})
+		require.NoError(t, s.eng.SaveNumberStatus(got))
+
+		statuses, err := s.eng.GetNumberStatuses()
+		require.NoError(t, err)
+		require.Len(t, statuses, 1)
+		assert.Equal(t, 20, statuses[0].LastFound)
+		assert.Equal(t, model.NumberStatusPaused, statuses[0].Status)
+		assert.Equal(t, []int{3, 7}, statuses[0].Gaps.Data())
+	})
+
+	s.T().Run("delete number status", func(t *testing.T) {
+		require.NoError(t, s.eng.DeleteNumberStatus("num"))
+		_, err := s.eng.GetNumberStatus("NUM")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	s.T().Run("get movie numbers", func(t *testing.T) {
+		for _, info := range []*model.MovieInfo{
+			{ID: "num001", Number: "NUM-001", Title: "a", Provider: "NUMBER", Homepage: "https://number/num001", CoverURL: "https://number/num001.jpg"},
+			{ID: "num002", Number: "num-002", Title: "b", Provider: "NUMBER", Homepage: "https://number/num002", CoverURL: "https://number/num002.jpg"},
+			{ID: "numx003", Number: "NUMX-003", Title: "c", Provider: "NUMBER", Homepage: "https://number/numx003", CoverURL: "https://number/numx003.jpg"},
+			{ID: "n_m004", Number: "N_M-004", Title: "d", Provider: "NUMBER", Homepage: "https://number/n_m004", CoverURL: "https://number/n_m004.jpg"},
+		} {
+			require.NoError(t, s.eng.SaveMovieInfo(info))
+		}
+		var numbers []string
+		results, err := s.eng.GetMovieNumbers("NUM")
+		require.NoError(t, err)
+		for _, result := range results {
+			numbers = append(numbers, result.Number)
+		}
+		assert.ElementsMatch(t, []string{"NUM-001", "num-002"}, numbers)
+
+		// wildcards are matched literally.
+		results, err = s.eng.GetMovieNumbers("N_M")
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, "n_m004", results[0].ID)
+		results, err = s.eng.GetMovieNumbers("N%")
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+}
+
+func (s *DBEngineTestSuite) TestCollection() {
+	for _, info := range []*model.MovieInfo{
+		{ID: "col001", Number: "COL-001", Title: "a", Provider: "COLLECTION", Homepage: "https://collection/col001", CoverURL: "https://collection/col001.jpg", Maker: "Maker A", Series: "Series A", ReleaseDate: datatypes.Date(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))},
+		{ID: "col002", Number: "COL-002", Title: "b", Provider: "COLLECTION", Homepage: "https://collection/col002", CoverURL: "https://collection/col002.jpg", Maker: "Maker A", Series: "Series A", ReleaseDate: datatypes.Date(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))},
+		{ID: "col003", Number: "COL-003", Title: "c", Provider: "COLLECTION", Homepage: "https://collection/col003", CoverURL: "https://collection/col003.jpg", Maker: "Maker B", Series: "Series A"},
+	} {
+		s.Require().NoError(s.eng.SaveMovieInfo(info))
+		s.Require().NoError(s.eng.AddMaker(&model.Maker{Name: info.Maker}))
+		s.Require().NoError(s.eng.AddSeries(&model.Series{Name: info.Series, Maker: info.Maker}))
+	}
+
+	s.T().Run("list makers", func(t *testing.T) {
+		makers, err := s.eng.ListMakers("Maker", 1, 10)
+		require.NoError(t, err)
+		require.Len(t, makers, 2)
+		assert.Equal(t, "Maker A", makers[0].Name)
+
+		makers, err = s.eng.ListMakers("", 2, 1)
+		require.NoError(t, err)
+		require.Len(t, makers, 1)
+		assert.Equal(t, "Maker B", makers[0].Name)
+	})
+
+	s.T().Run("list series", func(t *testing.T) {
+		series, err := s.eng.ListSeries("Series", "", 1, 10)
+		require.NoError(t, err)
+		assert.Len(t, series, 2)
+
+		series, err = s.eng.ListSeries("", "Maker B", 1, 10)
+		require.NoError(t, err)
+		require.Len(t, series, 1)
+		assert.Equal(t, "Maker B", series[0].Maker)
+	})
+
+	s.T().Run("list collection movies", func(t *testing.T) {
+		infos, err := s.eng.ListCollectionMovies("series", "Series A", "Maker A", 1, 10)
+		require.NoError(t, err)
+		require.Len(t, infos, 2)
+		assert.Equal(t, "col002", infos[0].ID) // newest first.
+
+		infos, err = s.eng.ListCollectionMovies("maker", "Maker B", "", 1, 10)
+		require.NoError(t, err)
+		require.Len(t, infos, 1)
+	})
+}
+
+func (s *DBEngineTestSuite) TestProviderSetting() {
+	priority := 10.0
+	s.Require().NoError(s.eng.SaveProviderSetting(&model.ProviderSetting{
+		Type:     "movie",
+		Name:     "SETTING",
+		Priority: &priority,
+		Config:   datatypes.NewJSONType(map[string]string{"mirrors": "https://mirror"}),
+	}))
+
+	s.T().Run("get provider setting", func(t *testing.T) {
+		setting, err := s.eng.GetProviderSetting("movie", "SETTING")
+		require.NoError(t, err)
+		assert.Equal(t, priority, *setting.Priority)
+		assert.Equal(t, "https://mirror", setting.Config.Data()["mirrors"])
+
+		_, err = s.eng.GetProviderSetting("actor", "SETTING")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		settings, err := s.eng.GetProviderSettings()
+		require.NoError(t, err)
+		assert.Len(t, settings, 1)
+	})
+
+	s.T().Run("delete provider setting", func(t *testing.T) {
+		require.NoError(t, s.eng.DeleteProviderSetting("movie", "SETTING"))
+		_, err := s.eng.GetProviderSetting("movie", "SETTING")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	s.T().Run("save provider session", func(t *testing.T) {
+		require.NoError(t, s.eng.SaveProviderSession(&model.ProviderSession{
+			Provider: "SETTING",
+			Cookies:  datatypes.NewJSONType(model.NewSessionCookies([]*http.Cookie{{Name: "sid", Value: "1"}})),
+		}))
+		session, err := s.eng.GetProviderSession("SETTING")
+		require.NoError(t, err)
+		require.Len(t, session.HTTPCookies(), 1)
+		assert.Equal(t, "1", session.HTTPCookies()[0].Value)
+	})
+}
+
 func jsonify(v interface{}) string {
 	data, _ := json.MarshalIndent(v, "", "\t")
 	return string(data)
 }
 
+// withTrailer joins the record lines with the trailer, as exported.
+func withTrailer(lines ...string) string {
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(line + "\n")
+	}
+	sum := sha256.Sum256([]byte(b.String()))
+	fmt.Fprintf(&b, "{\"trailer\":{\"records\":%d,\"sha256\":\"%s\"}}\n", len(lines), hex.EncodeToString(sum[:]))
+	return b.String()
+}
+
+func TestSQLiteFTSRebuild(t *testing.T) {
+	db, err := database.Open(&database.Config{
+		DSN:                  ":memory:",
+		MaxOpenConns:         1, // keep the memory DB.
+		DisableAutomaticPing: true,
+		LogLevel:             logger.Warn,
+	})
+	require.NoError(t, err)
+	// schema created by gorm auto migration before.
+	require.NoError(t, db.AutoMigrate(&model.MovieInfo{}))
+	for _, id := range []string{"fts001", "fts002"} {
+		require.NoError(t, db.Create(&model.MovieInfo{ID: id, Provider: "FTS", Title: "Title " + id}).Error)
+	}
+	indexed := func() (n int64) {
+		require.NoError(t, db.Table(movieFTS.name()).Count(&n).Error)
+		return
+	}
+
+	// populated when the FTS tables are created.
+	eng := New(db)
+	require.NoError(t, eng.Migrate())
+	assert.EqualValues(t, 2, indexed())
+
+	// kept by the triggers since, without comparing on every start.
+	require.NoError(t, db.Exec(`DELETE FROM `+movieFTS.name()+` WHERE id = ?`, "fts001").Error)
+	require.NoError(t, eng.Migrate())
+	assert.EqualValues(t, 1, indexed())
+
+	// rebuilt if found empty.
+	require.NoError(t, db.Exec(`DELETE FROM `+movieFTS.name()).Error)
+	require.NoError(t, eng.Migrate())
+	assert.EqualValues(t, 2, indexed())
+}
+
+func TestParseSince(t *testing.T) {
+	for _, unit := range []struct {
+		s    string
+		want time.Time
+	}{
+		{"2024-01-02", time.Date(2024, 1, 2, 0, 0, 0, 0, time.Local)},
+		{"2024-01-02T03:04:05Z", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{"2024-01-02T03:04:05+09:00", time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("", 9*60*60))},
+	} {
+		got, err := ParseSince(unit.s)
+		require.NoError(t, err)
+		assert.True(t, unit.want.Equal(got), unit.s)
+	}
+	for _, s := range []string{"", "2024/01/02", "yesterday"} {
+		_, err := ParseSince(s)
+		assert.Error(t, err, s)
+	}
+}
+
 func TestMain(m *testing.M) {
 	pool, err := dockertest.NewPool("")
 	if err != nil {
@@ -385,10 +1020,62 @@ func TestMain(m *testing.M) {
 		}
 	}()
 
+	mysqlResource, err := pool.RunWithOptions(
+		&dockertest.RunOptions{
+			Repository: "mysql",
+			Tag:        "8.4",
+			Env: []string{
+				"MYSQL_DATABASE=" + mysqlDB,
+				"MYSQL_ROOT_PASSWORD=" + mysqlPass,
+			},
+		},
+		func(config *docker.HostConfig) {
+			config.AutoRemove = true
+			config.RestartPolicy = docker.RestartPolicy{Name: "no"}
+		},
+	)
+	if err != nil {
+		log.Fatalf("Could not start resource: %s", err)
+	}
+
+	mysqlDSN = (&url.URL{
+		Scheme: mysqlDriver,
+		User:   url.UserPassword("root", mysqlPass),
+		Host:   net.JoinHostPort("localhost", mysqlResource.GetPort("3306/tcp")),
+		Path:   mysqlDB,
+	}).String()
+
+	if err := pool.Retry(func() error {
+		db, err := database.Open(&database.Config{
+			DSN:      mysqlDSN,
+			LogLevel: logger.Silent,
+		})
+		if err != nil {
+			return err
+		}
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		defer sqlDB.Close()
+		return sqlDB.Ping()
+	}); err != nil {
+		log.Fatalf("Could not connect to database: %s", err)
+	}
+
+	defer func() {
+		if err := pool.Purge(mysqlResource); err != nil {
+			log.Fatalf("Could not purge resource: %s", err)
+		}
+	}()
+
 	m.Run()
 }
 
-var postgresDSN string
+var (
+	postgresDSN string
+	mysqlDSN    string
+)
 
 const (
 	postgresDriver = "postgres"
@@ -396,3 +1083,9 @@ const (
 	postgresUser
 	postgresPass
 )
+
+const (
+	mysqlDriver = "mysql"
+	mysqlDB
+	mysqlPass
+)
@@ -0,0 +1,68 @@
+package dbengine
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/metatube-community/metatube-sdk-go/database"
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+// dialect builds the driver specific parts of queries, so that the
+// engine behaves the same on all supported databases.
+type dialect interface {
+	// equalFold returns the case-insensitive equality condition
+	// of the column, with a placeholder for the value.
+	equalFold(column string) string
+	// rangeFold returns the case-insensitive condition that the column
+	// is in the half-open range of two placeholders, which is used for
+	// prefix matches, as LIKE can't use the indexes of the column.
+	rangeFold(column string) string
+	// searchMovie filters movies by the keyword, and orders them by
+	// relevance, exact matches of number or id always come first.
+	searchMovie(tx *gorm.DB, keyword string, opts *MovieSearchOptions) *gorm.DB
+	// searchActor filters actors by the keyword, and orders them by
+	// relevance, exact matches of name always come first.
+	searchActor(tx *gorm.DB, keyword string, opts *ActorSearchOptions) *gorm.DB
+	// versionSQL returns the query of the database version.
+	versionSQL() string
+	// afterMigrate runs after the schema migrations, if needed,
+	// from is the schema version before the migrations.
+	afterMigrate(db *gorm.DB, from int64) error
+}
+
+func newDialect(driver string) dialect {
+	switch driver {
+	case database.Postgres:
+		return postgresDialect{}
+	case database.MySQL:
+		return mysqlDialect{}
+	default: // same fallback as database.Open.
+		return sqliteDialect{}
+	}
+}
+
+// movieColumn qualifies the column, as joined tables may have the same.
+func movieColumn(name string) string {
+	return model.MovieMetadataTableName + "." + name
+}
+
+func actorColumn(name string) string {
+	return model.ActorMetadataTableName + "." + name
+}
+
+func exactMovieOrder(d dialect, keyword string) clause.Expr {
+	return clause.Expr{
+		SQL:  fmt.Sprintf(`(%s OR %s) DESC`, d.equalFold(movieColumn("number")), d.equalFold(movieColumn("id"))),
+		Vars: []any{keyword, keyword},
+	}
+}
+
+func exactActorOrder(d dialect, keyword string) clause.Expr {
+	return clause.Expr{
+		SQL:  fmt.Sprintf(`(%s) DESC`, d.equalFold(actorColumn("name"))),
+		Vars: []any{keyword},
+	}
+}
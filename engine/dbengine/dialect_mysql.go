@@ -0,0 +1,100 @@
+package dbengine
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// mysqlMinKeywordLength is the default ngram_token_size, shorter
+// keywords can't be matched by FULLTEXT indexes and use LIKE.
+const mysqlMinKeywordLength = 2
+
+type mysqlDialect struct{}
+
+// equalFold is a plain comparison, as text columns are created with
+// a case-insensitive collation by the migrations.
+func (mysqlDialect) equalFold(column string) string {
+	return column + ` = ?`
+}
+
+func (mysqlDialect) rangeFold(column string) string {
+	return column + ` >= ? AND ` + column + ` < ?`
+}
+
+func (d mysqlDialect) searchMovie(tx *gorm.DB, keyword string, _ *MovieSearchOptions) *gorm.DB {
+	pattern := "%" + keyword + "%"
+	if utf8.RuneCountInString(keyword) < mysqlMinKeywordLength {
+		return tx.Where(
+			`(
+			  number = ?
+			  OR id = ?
+			  OR number LIKE ?
+			  OR id LIKE ?
+			  OR title LIKE ?
+			  OR summary LIKE ?
+			)`,
+			keyword, keyword,
+			pattern, pattern, pattern, pattern,
+		).Order(exactMovieOrder(d, keyword))
+	}
+	// number and id are short, so LIKE is cheap enough for them.
+	phrase := mysqlPhrase(keyword)
+	return tx.Where(
+		`(
+		  number = ?
+		  OR id = ?
+		  OR number LIKE ?
+		  OR id LIKE ?
+		  OR MATCH (title, summary) AGAINST (? IN BOOLEAN MODE)
+		)`,
+		keyword, keyword,
+		pattern, pattern,
+		phrase,
+	).Order(exactMovieOrder(d, keyword)).Order(clause.Expr{
+		SQL:  `MATCH (title, summary) AGAINST (? IN BOOLEAN MODE) DESC`,
+		Vars: []any{phrase},
+	})
+}
+
+func (d mysqlDialect) searchActor(tx *gorm.DB, keyword string, _ *ActorSearchOptions) *gorm.DB {
+	pattern := "%" + keyword + "%"
+	if utf8.RuneCountInString(keyword) < mysqlMinKeywordLength {
+		return tx.Where(
+			`(
+			  name = ?
+			  OR name LIKE ?
+			  OR JSON_SEARCH(aliases, 'one', ?) IS NOT NULL
+			)`,
+			keyword, pattern, pattern,
+		).Order(exactActorOrder(d, keyword))
+	}
+	phrase := mysqlPhrase(keyword)
+	return tx.Where(
+		`(
+		  name = ?
+		  OR MATCH (name) AGAINST (? IN BOOLEAN MODE)
+		  OR JSON_SEARCH(aliases, 'one', ?) IS NOT NULL
+		)`,
+		keyword, phrase, pattern,
+	).Order(exactActorOrder(d, keyword)).Order(clause.Expr{
+		SQL:  `MATCH (name) AGAINST (? IN BOOLEAN MODE) DESC`,
+		Vars: []any{phrase},
+	})
+}
+
+func (mysqlDialect) versionSQL() string {
+	return `SELECT VERSION();`
+}
+
+func (mysqlDialect) afterMigrate(*gorm.DB, int64) error {
+	return nil
+}
+
+// mysqlPhrase quotes the keyword as a boolean mode phrase, so that it
+// is matched as a substring with the ngram parser.
+func mysqlPhrase(keyword string) string {
+	return `"` + strings.ReplaceAll(keyword, `"`, " ") + `"`
+}
@@ -0,0 +1,61 @@
+package dbengine
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type postgresDialect struct{}
+
+func (postgresDialect) equalFold(column string) string {
+	return column + ` COLLATE NOCASE = ?`
+}
+
+func (postgresDialect) rangeFold(column string) string {
+	return column + ` COLLATE NOCASE >= ? AND ` + column + ` COLLATE NOCASE < ?`
+}
+
+func (d postgresDialect) searchMovie(tx *gorm.DB, keyword string, opts *MovieSearchOptions) *gorm.DB {
+	pattern := "%" + keyword + "%"
+	return tx.Where(
+		`(
+		  number COLLATE NOCASE = ?
+		  OR id COLLATE NOCASE = ?
+		  OR number ILIKE ?
+		  OR title ILIKE ?
+		  OR summary ILIKE ?
+		  OR similarity(number, ?) > ?
+		  OR similarity(title, ?) > ?
+		)`,
+		keyword, keyword,
+		pattern, pattern, pattern,
+		keyword, opts.Thresholds.Number,
+		keyword, opts.Thresholds.Title,
+	).Order(exactMovieOrder(d, keyword)).Order(clause.Expr{
+		SQL:  `GREATEST(similarity(number, ?), similarity(title, ?)) DESC`,
+		Vars: []any{keyword, keyword},
+	})
+}
+
+func (d postgresDialect) searchActor(tx *gorm.DB, keyword string, opts *ActorSearchOptions) *gorm.DB {
+	pattern := "%" + keyword + "%"
+	return tx.Where(
+		`(
+		  name COLLATE NOCASE = ?
+		  OR similarity(name, ?) > ?
+		  OR array_to_string(aliases, ' ') ILIKE ?
+		)`,
+		keyword, keyword, opts.Threshold, pattern,
+	).Order(exactActorOrder(d, keyword)).Order(clause.Expr{
+		SQL:  `similarity(name, ?) DESC`,
+		Vars: []any{keyword},
+	})
+}
+
+func (postgresDialect) versionSQL() string {
+	return `SELECT version();`
+}
+
+func (postgresDialect) afterMigrate(*gorm.DB, int64) error {
+	return nil
+}
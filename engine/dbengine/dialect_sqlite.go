@@ -0,0 +1,78 @@
+package dbengine
+
+import (
+	"gorm.io/gorm"
+)
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) equalFold(column string) string {
+	return column + ` COLLATE NOCASE = ?`
+}
+
+func (sqliteDialect) rangeFold(column string) string {
+	return column + ` COLLATE NOCASE >= ? AND ` + column + ` COLLATE NOCASE < ?`
+}
+
+func (d sqliteDialect) searchMovie(tx *gorm.DB, keyword string, _ *MovieSearchOptions) *gorm.DB {
+	if useFTS(keyword) {
+		return movieFTS.join(tx, keyword).
+			Order(exactMovieOrder(d, keyword)).
+			Order(movieFTS.rank())
+	}
+	pattern := "%" + keyword + "%"
+	return tx.Where(
+		`(
+		  number COLLATE NOCASE = ?
+		  OR id COLLATE NOCASE = ?
+		  OR number LIKE ? COLLATE NOCASE
+		  OR id LIKE ? COLLATE NOCASE
+		  OR title LIKE ? COLLATE NOCASE
+		  OR summary LIKE ? COLLATE NOCASE
+		)`,
+		keyword, keyword,
+		pattern, pattern, pattern, pattern,
+	).Order(exactMovieOrder(d, keyword))
+}
+
+func (d sqliteDialect) searchActor(tx *gorm.DB, keyword string, _ *ActorSearchOptions) *gorm.DB {
+	if useFTS(keyword) {
+		return actorFTS.join(tx, keyword).
+			Order(exactActorOrder(d, keyword)).
+			Order(actorFTS.rank())
+	}
+	pattern := "%" + keyword + "%"
+	return tx.Where(
+		`(
+		  name COLLATE NOCASE = ?
+		  OR name LIKE ? COLLATE NOCASE
+		  OR aliases LIKE ? COLLATE NOCASE
+		)`,
+		keyword, pattern, pattern,
+	).Order(exactActorOrder(d, keyword))
+}
+
+func (sqliteDialect) versionSQL() string {
+	return `SELECT sqlite_version();`
+}
+
+func (sqliteDialect) afterMigrate(db *gorm.DB, from int64) error {
+	// Populate FTS indexes when they are just created, e.g. over the
+	// adopted tables, or found empty, they are kept in sync by the
+	// triggers otherwise.
+	for _, t := range []*ftsTable{movieFTS, actorFTS} {
+		if from >= ftsMigrationVersion {
+			empty, err := t.empty(db)
+			if err != nil {
+				return err
+			}
+			if !empty {
+				continue
+			}
+		}
+		if err := t.rebuild(db); err != nil {
+			return err
+		}
+	}
+	return nil
+}
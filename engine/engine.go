@@ -9,7 +9,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
-	"strings"
+	"sync"
 	"time"
 
 	"gorm.io/gorm"
@@ -17,6 +17,7 @@ import (
 	"github.com/metatube-community/metatube-sdk-go/collection/maps"
 	"github.com/metatube-community/metatube-sdk-go/common/fetch"
 	"github.com/metatube-community/metatube-sdk-go/database"
+	"github.com/metatube-community/metatube-sdk-go/engine/dbengine"
 	mt "github.com/metatube-community/metatube-sdk-go/provider"
 )
 
@@ -26,18 +27,26 @@ const (
 )
 
 type Engine struct {
-	db      *gorm.DB
+	dbe     dbengine.DBEngine
 	name    string
 	timeout time.Duration
 	fetcher *fetch.Fetcher
 	// Engine Logger
 	logger *log.Logger
+	// Guards the provider maps, configs and proxy
+	// fetchers below, which can be changed at runtime.
+	mu sync.RWMutex
+	// Serializes the persisted provider updates.
+	settingMu sync.Mutex
 	// Name:Config Case-Insensitive Map
 	actorProviderConfigs *maps.CaseInsensitiveMap[mt.Config]
 	movieProviderConfigs *maps.CaseInsensitiveMap[mt.Config]
 	// Name:Provider Case-Insensitive Map
 	actorProviders *maps.CaseInsensitiveMap[mt.ActorProvider]
 	movieProviders *maps.CaseInsensitiveMap[mt.MovieProvider]
+	// Name:Provider Case-Insensitive Map of disabled providers
+	disabledActorProviders *maps.CaseInsensitiveMap[mt.ActorProvider]
+	disabledMovieProviders *maps.CaseInsensitiveMap[mt.MovieProvider]
 	// Host:[]Provider Case-Insensitive Map
 	// We need a []mt.ActorProvider here because sometimes providers
 	// can share the same host, but they're two different providers.
@@ -45,21 +54,35 @@ type Engine struct {
 	// E.g., github.com -> [Gfriends, ...]
 	actorHostProviders *maps.CaseInsensitiveMap[[]mt.ActorProvider]
 	movieHostProviders *maps.CaseInsensitiveMap[[]mt.MovieProvider]
-	translator         translate.Translator
+	// Name:Fetcher Case-Insensitive Map for proxied providers
+	// which don't implement the Fetcher interface.
+	proxyFetchers *maps.CaseInsensitiveMap[*fetch.Fetcher]
+	// Name:StopFunc Case-Insensitive Map of proxy health checkers.
+	proxyCheckers *maps.CaseInsensitiveMap[func()]
+	translator    translate.Translator
+	// Running and recently finished match jobs.
+	matchJobs matchJobs
+	// Freshness policies and background refreshes of stale infos.
+	freshness freshnessPolicies
+	refresher refresher
 }
 
 func New(db *gorm.DB, opts ...Option) *Engine {
 	engine := &Engine{
-		db:      db,
+		dbe:     dbengine.New(db),
 		name:    DefaultEngineName,
 		timeout: DefaultRequestTimeout,
 		// pre-initialize case-insensitive maps.
-		actorProviderConfigs: maps.NewCaseInsensitiveMap[mt.Config](),
-		movieProviderConfigs: maps.NewCaseInsensitiveMap[mt.Config](),
-		actorProviders:       maps.NewCaseInsensitiveMap[mt.ActorProvider](),
-		movieProviders:       maps.NewCaseInsensitiveMap[mt.MovieProvider](),
-		actorHostProviders:   maps.NewCaseInsensitiveMap[[]mt.ActorProvider](),
-		movieHostProviders:   maps.NewCaseInsensitiveMap[[]mt.MovieProvider](),
+		actorProviderConfigs:   maps.NewCaseInsensitiveMap[mt.Config](),
+		movieProviderConfigs:   maps.NewCaseInsensitiveMap[mt.Config](),
+		actorProviders:         maps.NewCaseInsensitiveMap[mt.ActorProvider](),
+		movieProviders:         maps.NewCaseInsensitiveMap[mt.MovieProvider](),
+		disabledActorProviders: maps.NewCaseInsensitiveMap[mt.ActorProvider](),
+		disabledMovieProviders: maps.NewCaseInsensitiveMap[mt.MovieProvider](),
+		actorHostProviders:     maps.NewCaseInsensitiveMap[[]mt.ActorProvider](),
+		movieHostProviders:     maps.NewCaseInsensitiveMap[[]mt.MovieProvider](),
+		proxyFetchers:          maps.NewCaseInsensitiveMap[*fetch.Fetcher](),
+		proxyCheckers:          maps.NewCaseInsensitiveMap[func()](),
 		translator: translate.New("openaigen", func(v any) error {
 			// 从配置加载 OpenAIGen 参数
 			config := v.(*openaigen.OpenAIGen)
@@ -88,10 +111,14 @@ func Default() *Engine {
 }
 
 func (e *Engine) IsActorProvider(name string) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
 	return e.actorProviders.Has(name)
 }
 
 func (e *Engine) GetActorProviders() map[string]mt.ActorProvider {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
 	return gomaps.Collect(e.actorProviders.Iterator())
 }
 
@@ -100,8 +127,10 @@ func (e *Engine) GetActorProviderByURL(rawURL string) (mt.ActorProvider, error)
 	if err != nil {
 		return nil, err
 	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
 	for _, p := range e.actorHostProviders.GetOrDefault(u.Hostname(), nil) {
-		if strings.HasPrefix(u.Path, p.URL().Path) {
+		if matchProviderURL(p, u) {
 			return p, nil
 		}
 	}
@@ -109,6 +138,8 @@ func (e *Engine) GetActorProviderByURL(rawURL string) (mt.ActorProvider, error)
 }
 
 func (e *Engine) GetActorProviderByName(name string) (mt.ActorProvider, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
 	provider, ok := e.actorProviders.Get(name)
 	if !ok {
 		return nil, mt.ErrProviderNotFound
@@ -125,10 +156,14 @@ func (e *Engine) MustGetActorProviderByName(name string) mt.ActorProvider {
 }
 
 func (e *Engine) IsMovieProvider(name string) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
 	return e.movieProviders.Has(name)
 }
 
 func (e *Engine) GetMovieProviders() map[string]mt.MovieProvider {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
 	return gomaps.Collect(e.movieProviders.Iterator())
 }
 
@@ -137,8 +172,10 @@ func (e *Engine) GetMovieProviderByURL(rawURL string) (mt.MovieProvider, error)
 	if err != nil {
 		return nil, err
 	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
 	for _, p := range e.movieHostProviders.GetOrDefault(u.Hostname(), nil) {
-		if strings.HasPrefix(u.Path, p.URL().Path) {
+		if matchProviderURL(p, u) {
 			return p, nil
 		}
 	}
@@ -146,6 +183,8 @@ func (e *Engine) GetMovieProviderByURL(rawURL string) (mt.MovieProvider, error)
 }
 
 func (e *Engine) GetMovieProviderByName(name string) (mt.MovieProvider, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
 	provider, ok := e.movieProviders.Get(name)
 	if !ok {
 		return nil, mt.ErrProviderNotFound
@@ -164,12 +203,39 @@ func (e *Engine) MustGetMovieProviderByName(name string) mt.MovieProvider {
 // Fetch fetches content from url. If the provider
 // is nil, the default fetcher will be used.
 func (e *Engine) Fetch(url string, provider mt.Provider) (*http.Response, error) {
+	return e.FetchWithOptions(url, provider)
+}
+
+// optionFetcher is the fetcher which accepts request options,
+// e.g.: providers embedding *fetch.Fetcher.
+type optionFetcher interface {
+	Get(url string, opts ...fetch.Option) (*http.Response, error)
+}
+
+// FetchWithOptions is like Fetch, but also applies request options,
+// e.g.: Range header. Options are ignored by provider's own fetcher
+// which does not accept them.
+func (e *Engine) FetchWithOptions(url string, provider mt.Provider, opts ...fetch.Option) (*http.Response, error) {
 	// Provider which implements Fetcher interface should be
 	// used to fetch all its corresponding resources.
+	// Fetch from the working mirror, if any.
+	url = preferredURL(provider, url)
+	if fetcher, ok := provider.(optionFetcher); ok && len(opts) > 0 {
+		return fetcher.Get(url, opts...)
+	}
 	if fetcher, ok := provider.(mt.Fetcher); ok {
 		return fetcher.Fetch(url)
 	}
-	return e.fetcher.Fetch(url)
+	// Proxied providers should fetch through their proxies as well.
+	if provider != nil {
+		e.mu.RLock()
+		fetcher, ok := e.proxyFetchers.Get(provider.Name())
+		e.mu.RUnlock()
+		if ok {
+			return fetcher.Get(url, opts...)
+		}
+	}
+	return e.fetcher.Get(url, opts...)
 }
 
 // String returns the name of the Engine instance.
@@ -0,0 +1,85 @@
+package engine
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metatube-community/metatube-sdk-go/common/fetch"
+	"github.com/metatube-community/metatube-sdk-go/common/ratelimit"
+	"github.com/metatube-community/metatube-sdk-go/internal/envconfig"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+	onepondo "github.com/metatube-community/metatube-sdk-go/provider/1pondo"
+)
+
+func TestEngine_FetchWithOptions(t *testing.T) {
+	video := bytes.Repeat([]byte("0123456789"), 1024)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "mb.mp4", time.Time{}, bytes.NewReader(video))
+	}))
+	defer srv.Close()
+
+	e := &Engine{}
+	resp, err := e.FetchWithOptions(srv.URL+"/mb.mp4", onepondo.New(),
+		fetch.WithRaiseForStatus(false),
+		fetch.WithHeader("Range", "bytes=100-199"))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	assert.Equal(t, "bytes 100-199/10240", resp.Header.Get("Content-Range"))
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, video[100:200], data)
+}
+
+// imageHostProvider serves its images from another host.
+type imageHostProvider struct {
+	mt.MovieProvider
+	imageHost string
+}
+
+func (*imageHostProvider) Name() string { return "IMAGES" }
+
+func (*imageHostProvider) URL() *url.URL {
+	return &url.URL{Scheme: "https", Host: "images.test", Path: "/"}
+}
+
+func (p *imageHostProvider) ResourceHosts() []string { return []string{p.imageHost} }
+
+func TestEngine_FetchRateLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("image"))
+	}))
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	e := New(openTestDB(t))
+	provider := &imageHostProvider{imageHost: u.Hostname()}
+	config := envconfig.NewConfig()
+	config.Set("max_concurrency", "1")
+	require.NoError(t, e.applyProviderConfig(movieProviderType, provider, config))
+	defer ratelimit.DefaultRegistry.SetGroup(rateLimitHosts(provider), ratelimit.Config{})
+
+	// the image host shares the limiter of the provider host.
+	limiter := ratelimit.DefaultRegistry.Limiter(u.Hostname())
+	require.NotNil(t, limiter)
+	assert.Same(t, limiter, ratelimit.DefaultRegistry.Limiter(provider.URL().Hostname()))
+	assert.Equal(t, 1, limiter.Config().Concurrency)
+
+	resp, err := e.Fetch(srv.URL+"/cover.jpg", provider)
+	require.NoError(t, err)
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	stats := limiter.Stats()
+	assert.EqualValues(t, 1, stats.Requests)
+	assert.Equal(t, 0, stats.InFlight)
+}
@@ -0,0 +1,29 @@
+package engine
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/metatube-community/metatube-sdk-go/errors"
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+// GetReleaseFeed gets the recently released or upcoming movies
+// of the provider by page, page starts from 1.
+func (e *Engine) GetReleaseFeed(name string, page int) ([]*model.MovieSearchResult, error) {
+	provider, err := e.GetMovieProviderByName(name)
+	if err != nil {
+		return nil, err
+	}
+	feeder, ok := provider.(mt.ReleaseFeeder)
+	if !ok {
+		return nil, errors.New(http.StatusBadRequest,
+			fmt.Sprintf("release feed not supported by %s", provider.Name()))
+	}
+	results, err := feeder.GetReleaseFeed(page)
+	if err != nil {
+		return nil, err
+	}
+	return validMovieResults(provider, results), nil
+}
@@ -0,0 +1,209 @@
+package engine
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/metatube-community/metatube-sdk-go/collection/sets"
+	"github.com/metatube-community/metatube-sdk-go/engine/providerid"
+	"github.com/metatube-community/metatube-sdk-go/errors"
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+// getActorFilmographer finds the provider by name, either actor or
+// movie provider, which implements the ActorFilmographer interface.
+func (e *Engine) getActorFilmographer(name string) (mt.Provider, mt.ActorFilmographer, error) {
+	var provider mt.Provider
+	if p, err := e.GetActorProviderByName(name); err == nil {
+		provider = p
+	} else if p, err := e.GetMovieProviderByName(name); err == nil {
+		provider = p
+	} else {
+		return nil, nil, err
+	}
+	filmographer, ok := provider.(mt.ActorFilmographer)
+	if !ok {
+		return nil, nil, errors.New(http.StatusBadRequest,
+			fmt.Sprintf("filmography not supported by %s", provider.Name()))
+	}
+	return provider, filmographer, nil
+}
+
+// GetActorFilmography gets the movies of the actor by page, page starts from 1.
+func (e *Engine) GetActorFilmography(pid providerid.ProviderID, page int) ([]*model.MovieSearchResult, error) {
+	_, filmographer, err := e.getActorFilmographer(pid.Provider)
+	if err != nil {
+		return nil, err
+	}
+	results, err := filmographer.GetActorFilmography(pid.ID, page)
+	if err != nil {
+		return nil, err
+	}
+	return e.normalizeFilmography(results), nil
+}
+
+// GetActorFilmographyAll gets the movies of the actor by page from all
+// filmographer providers, the actor is looked up in other providers by
+// its name and aliases, and the same page is requested from each one.
+func (e *Engine) GetActorFilmographyAll(pid providerid.ProviderID, page int) (results []*model.MovieSearchResult, err error) {
+	source, filmographer, err := e.getActorFilmographer(pid.Provider)
+	if err != nil {
+		return nil, err
+	}
+	if results, err = filmographer.GetActorFilmography(pid.ID, page); err != nil {
+		return nil, err
+	}
+
+	var names []string
+	if e.IsActorProvider(source.Name()) {
+		info, err := e.GetActorInfoByProviderID(pid, true)
+		if err != nil {
+			return nil, err
+		}
+		names = append([]string{info.Name}, info.Aliases...)
+	} else if name := mostFrequentActor(results); name != "" {
+		// movie providers have no actor info, so we guess the
+		// name by the actor who appears most in the movies.
+		names = []string{name}
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+		ds []string
+	)
+	for _, provider := range e.getActorFilmographers() {
+		if provider.Name() == source.Name() {
+			continue
+		}
+		wg.Add(1)
+		go func(provider mt.Provider) {
+			defer wg.Done()
+			startTime := time.Now()
+			innerResults, innerErr := e.getActorFilmographyByNames(provider, names, page)
+
+			mu.Lock()
+			defer mu.Unlock()
+			ds = append(ds, func(a, b, c any) string {
+				if c == nil {
+					c = "no error"
+				}
+				return fmt.Sprintf("%s(%s):<%v>", a, b, c)
+			}(
+				provider.Name(),
+				time.Since(startTime),
+				innerErr,
+			))
+			if innerErr == nil {
+				results = append(results, innerResults...)
+			}
+		}(provider)
+	}
+	wg.Wait()
+
+	e.logger.Printf("Get filmography of %s:%s page %d: %s", pid.Provider, pid.ID, page, strings.Join(ds, " | "))
+
+	// remove duplicate results, if any.
+	msr := sets.NewOrderedSetWithHash(func(v *model.MovieSearchResult) string { return v.Provider + v.ID })
+	msr.Add(results...)
+	results = e.normalizeFilmography(msr.AsSlice())
+	// newest first.
+	sort.SliceStable(results, func(i, j int) bool {
+		return time.Time(results[i].ReleaseDate).After(time.Time(results[j].ReleaseDate))
+	})
+	return results, nil
+}
+
+// getActorFilmographers returns enabled providers which can both
+// look up actors by name, and list the movies of the actor.
+func (e *Engine) getActorFilmographers() (providers []mt.Provider) {
+	seen := make(map[string]struct{})
+	add := func(provider mt.Provider) {
+		_, isFilmographer := provider.(mt.ActorFilmographer)
+		_, isSearcher := provider.(mt.ActorSearcher)
+		if _, ok := seen[provider.Name()]; ok || !isFilmographer || !isSearcher {
+			return
+		}
+		seen[provider.Name()] = struct{}{}
+		providers = append(providers, provider)
+	}
+	for _, provider := range e.GetActorProviders() {
+		add(provider)
+	}
+	for _, provider := range e.GetMovieProviders() {
+		add(provider)
+	}
+	return
+}
+
+// getActorFilmographyByNames looks up the actor by names, and returns
+// the filmography of the first actor whose name or aliases match.
+func (e *Engine) getActorFilmographyByNames(provider mt.Provider, names []string, page int) ([]*model.MovieSearchResult, error) {
+	searcher := provider.(mt.ActorSearcher)
+	for _, name := range names {
+		candidates, err := searcher.SearchActor(name)
+		if err != nil {
+			continue
+		}
+		for _, candidate := range candidates {
+			if !matchActorNames(names, append([]string{candidate.Name}, candidate.Aliases...)) {
+				continue
+			}
+			return provider.(mt.ActorFilmographer).GetActorFilmography(candidate.ID, page)
+		}
+	}
+	return nil, nil // not found.
+}
+
+// normalizeFilmography drops invalid results, and rewrites the homepages
+// to the working mirror of their providers.
+func (e *Engine) normalizeFilmography(results []*model.MovieSearchResult) []*model.MovieSearchResult {
+	filtered := make([]*model.MovieSearchResult, 0, len(results))
+	for _, result := range results {
+		if !result.IsValid() {
+			continue
+		}
+		if provider, err := e.GetMovieProviderByName(result.Provider); err == nil {
+			result.Homepage = preferredURL(provider, result.Homepage)
+		}
+		filtered = append(filtered, result)
+	}
+	return filtered
+}
+
+// matchActorNames reports whether any name of a equals any name of b,
+// ignoring cases and spaces.
+func matchActorNames(a, b []string) bool {
+	normalize := func(s string) string {
+		return strings.ToLower(strings.Join(strings.Fields(s), ""))
+	}
+	for _, x := range a {
+		if x = normalize(x); x == "" {
+			continue
+		}
+		for _, y := range b {
+			if x == normalize(y) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mostFrequentActor returns the actor appears most in the results.
+func mostFrequentActor(results []*model.MovieSearchResult) (name string) {
+	counts := make(map[string]int)
+	for _, result := range results {
+		for _, actor := range result.Actors {
+			if counts[actor]++; counts[actor] > counts[name] {
+				name = actor
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,247 @@
+package engine
+
+import (
+	"sync"
+	"time"
+
+	"github.com/metatube-community/metatube-sdk-go/collection/maps"
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+const (
+	// DefaultMovieRecentPeriod is how long movies are considered
+	// recent after their release, if not set by the policy.
+	DefaultMovieRecentPeriod = 90 * 24 * time.Hour
+
+	// maxRefreshConcurrency is the number of background refreshes
+	// running at a time, the others wait in the queue.
+	maxRefreshConcurrency = 4
+	// maxRefreshQueue limits the queued refreshes, stale infos are
+	// still served when the queue is full, and queued on next read.
+	maxRefreshQueue = 1000
+
+	// refreshRetryBaseDelay is the backoff after the first failed
+	// refresh of an info, which is doubled on every failure.
+	refreshRetryBaseDelay = 5 * time.Minute
+	refreshRetryMaxDelay  = 24 * time.Hour
+	// maxRefreshFailures limits the failed infos tracked for backoff.
+	maxRefreshFailures = 10000
+)
+
+// FreshnessPolicy is the max age of the cached infos before they are
+// stale, and refreshed in the background on read. Zero means never.
+type FreshnessPolicy struct {
+	// MovieUpcoming is for movies to be released.
+	MovieUpcoming time.Duration
+	// MovieRecent is for movies released within MovieRecentPeriod,
+	// or the ones with unknown release dates.
+	MovieRecent time.Duration
+	// MovieOld is for movies released before MovieRecentPeriod.
+	MovieOld          time.Duration
+	MovieRecentPeriod time.Duration
+	// Actor is for all actors.
+	Actor time.Duration
+}
+
+// merge returns the policy with zero fields set by the fallback.
+func (p FreshnessPolicy) merge(fallback FreshnessPolicy) FreshnessPolicy {
+	or := func(v, fallback time.Duration) time.Duration {
+		if v != 0 {
+			return v
+		}
+		return fallback
+	}
+	return FreshnessPolicy{
+		MovieUpcoming:     or(p.MovieUpcoming, fallback.MovieUpcoming),
+		MovieRecent:       or(p.MovieRecent, fallback.MovieRecent),
+		MovieOld:          or(p.MovieOld, fallback.MovieOld),
+		MovieRecentPeriod: or(p.MovieRecentPeriod, fallback.MovieRecentPeriod),
+		Actor:             or(p.Actor, fallback.Actor),
+	}
+}
+
+func (p FreshnessPolicy) movieMaxAge(info *model.MovieInfo, now time.Time) time.Duration {
+	release := time.Time(info.ReleaseDate)
+	period := p.MovieRecentPeriod
+	if period == 0 {
+		period = DefaultMovieRecentPeriod
+	}
+	switch {
+	case release.After(now):
+		return p.MovieUpcoming
+	case release.IsZero(), now.Sub(release) <= period:
+		return p.MovieRecent
+	default:
+		return p.MovieOld
+	}
+}
+
+// Freshness is the age and staleness of a cached info.
+type Freshness struct {
+	Age    time.Duration
+	MaxAge time.Duration
+	Stale  bool
+}
+
+func newFreshness(updatedAt time.Time, maxAge time.Duration) Freshness {
+	var age time.Duration
+	if !updatedAt.IsZero() /* not saved */ {
+		age = max(0, time.Since(updatedAt))
+	}
+	return Freshness{
+		Age:    age,
+		MaxAge: maxAge,
+		Stale:  maxAge > 0 && age > maxAge,
+	}
+}
+
+// freshnessPolicies holds the default and per provider policies.
+type freshnessPolicies struct {
+	mu        sync.RWMutex
+	fallback  FreshnessPolicy
+	providers *maps.CaseInsensitiveMap[FreshnessPolicy]
+}
+
+func (f *freshnessPolicies) set(fallback FreshnessPolicy, providers map[string]FreshnessPolicy) {
+	m := maps.NewCaseInsensitiveMap[FreshnessPolicy]()
+	for name, policy := range providers {
+		m.Set(name, policy)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fallback, f.providers = fallback, m
+}
+
+func (f *freshnessPolicies) get(provider string) FreshnessPolicy {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.providers != nil {
+		if policy, ok := f.providers.Get(provider); ok {
+			return policy.merge(f.fallback)
+		}
+	}
+	return f.fallback
+}
+
+// refresher runs the background refreshes with bounded concurrency,
+// the same info is queued only once at a time, and backs off after
+// failures, so that the ones gone from providers are not retried on
+// every read.
+type refresher struct {
+	mu       sync.Mutex
+	pending  map[string]struct{}
+	failures map[string]*refreshFailure
+	sem      chan struct{}
+}
+
+type refreshFailure struct {
+	count   int
+	retryAt time.Time
+}
+
+func (r *refresher) enqueue(key string, refresh func() error) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.pending == nil {
+		r.pending = make(map[string]struct{})
+		r.failures = make(map[string]*refreshFailure)
+		r.sem = make(chan struct{}, maxRefreshConcurrency)
+	}
+	if _, ok := r.pending[key]; ok || len(r.pending) >= maxRefreshQueue {
+		return false
+	}
+	if f, ok := r.failures[key]; ok && time.Now().Before(f.retryAt) {
+		return false
+	}
+	r.pending[key] = struct{}{}
+	go func() {
+		var err error
+		defer func() {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			delete(r.pending, key)
+			r.done(key, err)
+		}()
+		r.sem <- struct{}{}
+		defer func() { <-r.sem }()
+		err = refresh()
+	}()
+	return true
+}
+
+// done records the result of the refresh, r.mu must be held.
+func (r *refresher) done(key string, err error) {
+	if err == nil {
+		delete(r.failures, key)
+		return
+	}
+	now := time.Now()
+	f, ok := r.failures[key]
+	if !ok {
+		if len(r.failures) >= maxRefreshFailures {
+			for k, v := range r.failures {
+				if now.After(v.retryAt) {
+					delete(r.failures, k)
+				}
+			}
+			if len(r.failures) >= maxRefreshFailures {
+				return // retried as usual.
+			}
+		}
+		f = &refreshFailure{}
+		r.failures[key] = f
+	}
+	f.count++
+	f.retryAt = now.Add(min(refreshRetryBaseDelay<<min(f.count-1, 16), refreshRetryMaxDelay))
+}
+
+// SetFreshnessPolicies sets the default policy and the ones by provider
+// names, which fall back to the default for zero fields.
+func (e *Engine) SetFreshnessPolicies(fallback FreshnessPolicy, providers map[string]FreshnessPolicy) {
+	e.freshness.set(fallback, providers)
+}
+
+// MovieInfoFreshness returns the freshness of the movie info by its
+// last update time and the policy of its provider.
+func (e *Engine) MovieInfoFreshness(info *model.MovieInfo) Freshness {
+	maxAge := e.freshness.get(info.Provider).movieMaxAge(info, time.Now())
+	return newFreshness(info.UpdatedAt, maxAge)
+}
+
+// ActorInfoFreshness returns the freshness of the actor info.
+func (e *Engine) ActorInfoFreshness(info *model.ActorInfo) Freshness {
+	return newFreshness(info.UpdatedAt, e.freshness.get(info.Provider).Actor)
+}
+
+// refreshMovieInfoIfStale queues a background refresh of the cached
+// movie info if it's stale, while the stale one is served meanwhile.
+func (e *Engine) refreshMovieInfoIfStale(provider mt.MovieProvider, info *model.MovieInfo) {
+	if !e.MovieInfoFreshness(info).Stale {
+		return
+	}
+	id := info.ID
+	e.refresher.enqueue("movie:"+provider.Name()+":"+id, func() error {
+		_, err := e.getMovieInfoByProviderID(provider, id, false)
+		if err != nil {
+			e.logger.Printf("Refresh stale movie info %s:%s: %v", provider.Name(), id, err)
+		}
+		return err
+	})
+}
+
+// refreshActorInfoIfStale queues a background refresh of the cached
+// actor info if it's stale.
+func (e *Engine) refreshActorInfoIfStale(provider mt.ActorProvider, info *model.ActorInfo) {
+	if !e.ActorInfoFreshness(info).Stale {
+		return
+	}
+	id := info.ID
+	e.refresher.enqueue("actor:"+provider.Name()+":"+id, func() error {
+		_, err := e.getActorInfoByProviderID(provider, id, false)
+		if err != nil {
+			e.logger.Printf("Refresh stale actor info %s:%s: %v", provider.Name(), id, err)
+		}
+		return err
+	})
+}
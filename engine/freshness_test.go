@@ -0,0 +1,106 @@
+package engine
+
+import (
+	goerr "errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/datatypes"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+func TestFreshnessPolicy_MovieMaxAge(t *testing.T) {
+	const day = 24 * time.Hour
+	var (
+		now    = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+		policy = FreshnessPolicy{
+			MovieUpcoming: day,
+			MovieRecent:   7 * day,
+			MovieOld:      365 * day,
+		}
+	)
+	for _, unit := range []struct {
+		release time.Time
+		want    time.Duration
+	}{
+		{now.AddDate(0, 0, 10), day},
+		{time.Time{}, 7 * day},
+		{now.AddDate(0, 0, -30), 7 * day},
+		{now.AddDate(-1, 0, 0), 365 * day},
+	} {
+		info := &model.MovieInfo{ReleaseDate: datatypes.Date(unit.release)}
+		assert.Equal(t, unit.want, policy.movieMaxAge(info, now), unit.release)
+	}
+}
+
+func TestFreshnessPolicies(t *testing.T) {
+	f := &freshnessPolicies{}
+	assert.Zero(t, f.get("FANZA"))
+
+	f.set(FreshnessPolicy{MovieOld: time.Hour, Actor: time.Minute},
+		map[string]FreshnessPolicy{"fanza": {MovieOld: time.Second}})
+	assert.Equal(t, FreshnessPolicy{MovieOld: time.Second, Actor: time.Minute}, f.get("FANZA"))
+	assert.Equal(t, FreshnessPolicy{MovieOld: time.Hour, Actor: time.Minute}, f.get("MGS"))
+}
+
+func TestFreshness(t *testing.T) {
+	assert.False(t, newFreshness(time.Time{}, time.Hour).Stale)
+	assert.False(t, newFreshness(time.Now().Add(-2*time.Hour), 0).Stale)
+	assert.False(t, newFreshness(time.Now().Add(-time.Minute), time.Hour).Stale)
+	assert.True(t, newFreshness(time.Now().Add(-2*time.Hour), time.Hour).Stale)
+}
+
+func TestRefresher(t *testing.T) {
+	var (
+		r       refresher
+		wg      sync.WaitGroup
+		release = make(chan struct{})
+	)
+	wg.Add(1)
+	assert.True(t, r.enqueue("a", func() error {
+		defer wg.Done()
+		<-release
+		return nil
+	}))
+	// queued only once at a time.
+	assert.False(t, r.enqueue("a", func() error { return nil }))
+	close(release)
+	wg.Wait()
+
+	assert.Eventually(t, func() bool {
+		return r.enqueue("a", func() error { return nil })
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestRefresher_Backoff(t *testing.T) {
+	var r refresher
+	assert.True(t, r.enqueue("a", func() error { return goerr.New("failed") }))
+	assert.Eventually(t, func() bool {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return len(r.pending) == 0
+	}, time.Second, 10*time.Millisecond)
+
+	// not retried until the backoff expires.
+	assert.False(t, r.enqueue("a", func() error { return nil }))
+	assert.True(t, r.enqueue("b", func() error { return nil }))
+
+	r.mu.Lock()
+	f := r.failures["a"]
+	assert.Equal(t, 1, f.count)
+	assert.WithinDuration(t, time.Now().Add(refreshRetryBaseDelay), f.retryAt, time.Second)
+	f.retryAt = time.Now()
+	r.mu.Unlock()
+
+	// a successful refresh clears the failure.
+	assert.True(t, r.enqueue("a", func() error { return nil }))
+	assert.Eventually(t, func() bool {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		_, ok := r.failures["a"]
+		return !ok
+	}, time.Second, 10*time.Millisecond)
+}
@@ -28,8 +28,12 @@ func (e *Engine) GetActorPrimaryImage(pid providerid.ProviderID) (image.Image, e
 	if len(info.Images) == 0 {
 		return nil, mt.ErrImageNotFound
 	}
+	provider, err := e.GetActorProviderByName(pid.Provider)
+	if err != nil {
+		return nil, err
+	}
 	return e.GetImageByURL(
-		e.MustGetActorProviderByName(pid.Provider), info.Images[0],
+		provider, info.Images[0],
 		R.PrimaryImageRatio, defaultActorPrimaryImagePosition, false,
 	)
 }
@@ -47,9 +51,12 @@ func (e *Engine) GetMoviePrimaryImage(pid providerid.ProviderID, ratio, pos floa
 		pos = defaultMoviePrimaryImagePosition
 		auto = number.RequiresFaceDetection(info.Number)
 	}
+	provider, err := e.GetMovieProviderByName(pid.Provider)
+	if err != nil {
+		return nil, err
+	}
 	return e.GetImageByURL(
-		e.MustGetMovieProviderByName(pid.Provider),
-		url, ratio, pos, auto,
+		provider, url, ratio, pos, auto,
 	)
 }
 
@@ -58,8 +65,12 @@ func (e *Engine) GetMovieThumbImage(pid providerid.ProviderID) (image.Image, err
 	if err != nil {
 		return nil, err
 	}
+	provider, err := e.GetMovieProviderByName(pid.Provider)
+	if err != nil {
+		return nil, err
+	}
 	return e.GetImageByURL(
-		e.MustGetMovieProviderByName(pid.Provider), url,
+		provider, url,
 		R.ThumbImageRatio, defaultMovieThumbImagePosition, false,
 	)
 }
@@ -69,8 +80,12 @@ func (e *Engine) GetMovieBackdropImage(pid providerid.ProviderID) (image.Image,
 	if err != nil {
 		return nil, err
 	}
+	provider, err := e.GetMovieProviderByName(pid.Provider)
+	if err != nil {
+		return nil, err
+	}
 	return e.GetImageByURL(
-		e.MustGetMovieProviderByName(pid.Provider), url,
+		provider, url,
 		R.BackdropImageRatio, defaultMovieBackdropImagePosition, false,
 	)
 }
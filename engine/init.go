@@ -1,10 +1,17 @@
 package engine
 
 import (
+	"fmt"
 	"log"
 	"os"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/metatube-community/metatube-sdk-go/common/fetch"
+	"github.com/metatube-community/metatube-sdk-go/common/proxy"
+	"github.com/metatube-community/metatube-sdk-go/common/ratelimit"
 	mt "github.com/metatube-community/metatube-sdk-go/provider"
 )
 
@@ -33,25 +40,60 @@ func (e *Engine) initActorProviders() {
 			s.SetRequestTimeout(e.timeout /* global timeout */)
 		}
 
+		if s, ok := provider.(mt.SessionSetter); ok {
+			s.SetSessionStore(&sessionStore{dbe: e.dbe})
+		}
+
 		if config, hasConfig := e.actorProviderConfigs.Get(name); hasConfig {
-			e.applyProviderConfig("actor", provider, config)
+			if err := e.applyProviderConfig(actorProviderType, provider, config); err != nil {
+				e.logger.Fatal(err)
+			}
 		}
 
 		if provider.Priority() <= 0 {
 			e.logger.Printf("Disable actor provider: %s", provider.Name())
+			e.disableActorProvider(name, provider)
 			continue
 		}
 
-		// Add actor provider by name.
-		e.actorProviders.Set(name, provider)
-		// Add actor provider by host.
-		host := provider.URL().Hostname()
+		e.enableActorProvider(name, provider)
+	}
+}
+
+// enableActorProvider adds the actor provider by name and host,
+// e.mu must be held if called after initialization.
+func (e *Engine) enableActorProvider(name string, provider mt.ActorProvider) {
+	e.disabledActorProviders.Delete(name)
+	if e.actorProviders.Has(name) {
+		return
+	}
+	// Add actor provider by name.
+	e.actorProviders.Set(name, provider)
+	// Add actor provider by hosts, including mirrors.
+	for _, host := range providerHosts(provider) {
 		e.actorHostProviders.Set(host,
 			append(e.actorHostProviders.
 				GetOrDefault(host, nil), provider))
 	}
 }
 
+// disableActorProvider removes the actor provider by name and host,
+// e.mu must be held if called after initialization.
+func (e *Engine) disableActorProvider(name string, provider mt.ActorProvider) {
+	e.actorProviders.Delete(name)
+	e.disabledActorProviders.Set(name, provider)
+	// Remove from all hosts, as mirrors may have changed.
+	for host, providers := range e.actorHostProviders.Iterator() {
+		providers = slices.DeleteFunc(slices.Clone(providers),
+			func(p mt.ActorProvider) bool { return p == provider })
+		if len(providers) > 0 {
+			e.actorHostProviders.Set(host, providers)
+		} else {
+			e.actorHostProviders.Delete(host)
+		}
+	}
+}
+
 // initMovieProviders initializes movie providers.
 func (e *Engine) initMovieProviders() {
 	for name, factory := range mt.RangeMovieFactory {
@@ -61,29 +103,70 @@ func (e *Engine) initMovieProviders() {
 			s.SetRequestTimeout(e.timeout /* global timeout */)
 		}
 
-		if config, hasConfig := e.actorProviderConfigs.Get(name); hasConfig {
-			e.applyProviderConfig("movie", provider, config)
+		if s, ok := provider.(mt.SessionSetter); ok {
+			s.SetSessionStore(&sessionStore{dbe: e.dbe})
+		}
+
+		if config, hasConfig := e.movieProviderConfigs.Get(name); hasConfig {
+			if err := e.applyProviderConfig(movieProviderType, provider, config); err != nil {
+				e.logger.Fatal(err)
+			}
 		}
 
 		if provider.Priority() <= 0 {
 			e.logger.Printf("Disable movie provider: %s", provider.Name())
+			e.disableMovieProvider(name, provider)
 			continue
 		}
 
-		// Add movie provider by name.
-		e.movieProviders.Set(name, provider)
-		// Add movie provider by host.
-		host := provider.URL().Hostname()
+		e.enableMovieProvider(name, provider)
+	}
+}
+
+// enableMovieProvider adds the movie provider by name and host,
+// e.mu must be held if called after initialization.
+func (e *Engine) enableMovieProvider(name string, provider mt.MovieProvider) {
+	e.disabledMovieProviders.Delete(name)
+	if e.movieProviders.Has(name) {
+		return
+	}
+	// Add movie provider by name.
+	e.movieProviders.Set(name, provider)
+	// Add movie provider by hosts, including mirrors.
+	for _, host := range providerHosts(provider) {
 		e.movieHostProviders.Set(host,
 			append(e.movieHostProviders.
 				GetOrDefault(host, nil), provider))
 	}
 }
 
-func (e *Engine) applyProviderConfig(providerType string, provider mt.Provider, config mt.Config) {
+// disableMovieProvider removes the movie provider by name and host,
+// e.mu must be held if called after initialization.
+func (e *Engine) disableMovieProvider(name string, provider mt.MovieProvider) {
+	e.movieProviders.Delete(name)
+	e.disabledMovieProviders.Set(name, provider)
+	// Remove from all hosts, as mirrors may have changed.
+	for host, providers := range e.movieHostProviders.Iterator() {
+		providers = slices.DeleteFunc(slices.Clone(providers),
+			func(p mt.MovieProvider) bool { return p == provider })
+		if len(providers) > 0 {
+			e.movieHostProviders.Set(host, providers)
+		} else {
+			e.movieHostProviders.Delete(host)
+		}
+	}
+}
+
+func (e *Engine) applyProviderConfig(providerType string, provider mt.Provider, config mt.Config) error {
 	const (
-		priorityConfigKey = "priority"
-		timeoutConfigKey  = "timeout"
+		priorityConfigKey    = "priority"
+		timeoutConfigKey     = "timeout"
+		rateLimitConfigKey   = "rate_limit"
+		rateBurstConfigKey   = "rate_burst"
+		concurrencyConfigKey = "max_concurrency"
+		proxyConfigKey       = "proxy"
+		proxyCheckConfigKey  = "proxy_check_interval"
+		mirrorsConfigKey     = "mirrors"
 	)
 
 	// Apply overridden priority.
@@ -102,10 +185,122 @@ func (e *Engine) applyProviderConfig(providerType string, provider mt.Provider,
 		}
 	}
 
+	// Apply proxy pool.
+	if config.Has(proxyConfigKey) {
+		if v, err := config.GetString(proxyConfigKey); err == nil {
+			var interval time.Duration
+			if config.Has(proxyCheckConfigKey) {
+				interval, _ = config.GetDuration(proxyCheckConfigKey)
+			}
+			if err := e.applyProviderProxy(providerType, provider, v, interval); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Apply mirror base URLs.
+	if config.Has(mirrorsConfigKey) {
+		if v, err := config.GetString(mirrorsConfigKey); err == nil {
+			if err := e.applyProviderMirrors(providerType, provider, v); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Apply rate limits shared by all hosts of the provider,
+	// after mirrors, so that their hosts are included.
+	if config.Has(rateLimitConfigKey) ||
+		config.Has(rateBurstConfigKey) ||
+		config.Has(concurrencyConfigKey) {
+		limits, _ := ratelimit.DefaultRegistry.Config(provider.URL().Hostname())
+		if v, err := config.GetFloat64(rateLimitConfigKey); err == nil {
+			limits.Rate = v
+		}
+		if v, err := config.GetInt64(rateBurstConfigKey); err == nil {
+			limits.Burst = int(v)
+		}
+		if v, err := config.GetInt64(concurrencyConfigKey); err == nil {
+			limits.Concurrency = int(v)
+		}
+		e.logger.Printf("Override %s provider rate limits: %s=%+v", providerType, provider.Name(), limits)
+		ratelimit.DefaultRegistry.SetGroup(rateLimitHosts(provider), limits)
+	}
+
+	// Apply session credentials and cookies.
+	if err := e.applyProviderSession(providerType, provider, config); err != nil {
+		return err
+	}
+
 	// Apply full config.
 	if s, ok := provider.(mt.ConfigSetter); ok {
 		if err := s.SetConfig(config); err != nil {
-			e.logger.Fatalf("Set %s provider config for %s: %v", providerType, provider.Name(), err)
+			return fmt.Errorf("set %s provider config for %s: %w", providerType, provider.Name(), err)
+		}
+	}
+	return nil
+}
+
+func (e *Engine) applyProviderProxy(providerType string, provider mt.Provider, proxies string, interval time.Duration) error {
+	s, ok := provider.(mt.ProxySetter)
+	if !ok {
+		e.logger.Printf("Proxy is not supported by %s provider: %s", providerType, provider.Name())
+		return nil
+	}
+	pool, err := proxy.Parse(proxies)
+	if err != nil {
+		return fmt.Errorf("parse %s provider proxy for %s: %w", providerType, provider.Name(), err)
+	}
+	e.logger.Printf("Override %s provider proxy: %s=%d proxies", providerType, provider.Name(), pool.Len())
+	s.SetProxy(pool)
+
+	// Fetch resources through the same proxies.
+	if _, isFetcher := provider.(mt.Fetcher); !isFetcher {
+		sw := proxy.NewSwitch()
+		sw.Set(pool)
+		e.proxyFetchers.Set(provider.Name(), fetch.Default(&fetch.Config{
+			Timeout: e.timeout,
+			Proxy:   sw,
+		}))
+	}
+
+	// Stop previous health checker, if any.
+	if stop, ok := e.proxyCheckers.Get(provider.Name()); ok {
+		stop()
+		e.proxyCheckers.Delete(provider.Name())
+	}
+	if interval > 0 {
+		e.proxyCheckers.Set(provider.Name(),
+			pool.StartHealthCheck(provider.URL().String(), interval))
+	}
+	return nil
+}
+
+func (e *Engine) applyProviderMirrors(providerType string, provider mt.Provider, mirrors string) error {
+	s, ok := provider.(mt.MirrorSetter)
+	if !ok {
+		e.logger.Printf("Mirrors are not supported by %s provider: %s", providerType, provider.Name())
+		return nil
+	}
+	urls := strings.FieldsFunc(mirrors, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+	if err := s.SetMirrors(urls); err != nil {
+		return fmt.Errorf("set %s provider mirrors for %s: %w", providerType, provider.Name(), err)
+	}
+	e.logger.Printf("Override %s provider mirrors: %s=%d mirrors", providerType, provider.Name(), len(urls))
+	return nil
+}
+
+// rateLimitHosts returns all hosts of the provider, including
+// mirrors and resource hosts, which share the rate limits.
+func rateLimitHosts(provider mt.Provider) []string {
+	hosts := providerHosts(provider)
+	if g, ok := provider.(mt.ResourceHostsGetter); ok {
+		for _, host := range g.ResourceHosts() {
+			if host = strings.ToLower(host); !slices.Contains(hosts, host) {
+				hosts = append(hosts, host)
+			}
 		}
 	}
+	return hosts
 }
@@ -0,0 +1,214 @@
+package engine
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/metatube-community/metatube-sdk-go/common/comparer"
+	"github.com/metatube-community/metatube-sdk-go/common/number"
+	"github.com/metatube-community/metatube-sdk-go/engine/providerid"
+	"github.com/metatube-community/metatube-sdk-go/errors"
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+const (
+	// DefaultMatchThreshold is the minimum confidence of a match.
+	DefaultMatchThreshold = 0.7
+
+	// maxMatchCandidates is the max number of runner-ups returned.
+	maxMatchCandidates = 5
+	// maxMatchInfoFetches limits the movie infos fetched to compare
+	// durations, as they are not available in search results.
+	maxMatchInfoFetches = 3
+)
+
+// Weights of each part of the match confidence.
+const (
+	matchNumberWeight   = 0.7
+	matchPriorityWeight = 0.1
+	matchDateWeight     = 0.1
+	matchDurationWeight = 0.1
+)
+
+var ErrNoConfidentMatch = errors.New(http.StatusNotFound, "no confident match")
+
+// MatchCandidate is a search result scored against the filename.
+type MatchCandidate struct {
+	*model.MovieSearchResult
+	Confidence float64 `json:"confidence"`
+}
+
+// MatchResult is the best matched movie of the filename.
+type MatchResult struct {
+	Number     *number.Number    `json:"number"`
+	Info       *model.MovieInfo  `json:"info"`
+	Confidence float64           `json:"confidence"`
+	Candidates []*MatchCandidate `json:"candidates,omitempty"`
+}
+
+// matchScore holds the parts of a candidate's confidence.
+type matchScore struct {
+	result           *model.MovieSearchResult
+	info             *model.MovieInfo
+	number, priority float64
+	date, duration   float64
+	hasDuration      bool
+}
+
+func (s *matchScore) confidence() float64 {
+	sum := s.number*matchNumberWeight +
+		s.priority*matchPriorityWeight +
+		s.date*matchDateWeight
+	weights := matchNumberWeight + matchPriorityWeight + matchDateWeight
+	if s.hasDuration {
+		sum += s.duration * matchDurationWeight
+		weights += matchDurationWeight
+	}
+	return math.Round(sum/weights*1000) / 1000
+}
+
+// MatchMovie parses the filename, searches all providers and returns the
+// best matched movie with its confidence, and the runner-ups. Duration of
+// the file in seconds is optional, and is compared with movie runtime.
+func (e *Engine) MatchMovie(filename string, duration int, threshold float64) (*MatchResult, error) {
+	n := number.Parse(filename)
+	if n.Number == "" {
+		return nil, mt.ErrInvalidKeyword
+	}
+	results, err := e.SearchMovieAll(n.Number, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var maxPriority float64
+	for _, provider := range e.GetMovieProviders() {
+		maxPriority = math.Max(maxPriority, provider.Priority())
+	}
+
+	scores := make([]*matchScore, 0, len(results))
+	for _, result := range results {
+		var priority float64
+		if provider, err := e.GetMovieProviderByName(result.Provider); err == nil && maxPriority > 0 {
+			priority = provider.Priority() / maxPriority
+		}
+		scores = append(scores, newMatchScore(n, result, priority))
+	}
+	sortMatchScores(scores)
+
+	// fetch infos of the top candidates, either to compare their
+	// durations, or to return the info of the best one.
+	fetches := 1
+	if duration > 0 {
+		fetches = maxMatchInfoFetches
+	}
+	for i := 0; i < len(scores) && fetches > 0; i++ {
+		s := scores[i]
+		info, err := e.GetMovieInfoByProviderID(providerid.ProviderID{
+			Provider: s.result.Provider,
+			ID:       s.result.ID,
+		}, true)
+		if err != nil {
+			e.logger.Printf("Match %s: get info of %s:%s: %v", filename, s.result.Provider, s.result.ID, err)
+			continue
+		}
+		s.info = info
+		if duration > 0 && info.Runtime > 0 {
+			s.hasDuration = true
+			s.duration = durationPlausibility(info.Runtime*60, duration)
+		}
+		fetches--
+	}
+	sortMatchScores(scores)
+
+	var best *matchScore
+	for _, s := range scores {
+		if s.info != nil {
+			best = s
+			break
+		}
+	}
+	if best == nil || best.confidence() < threshold {
+		return nil, ErrNoConfidentMatch
+	}
+
+	match := &MatchResult{
+		Number:     n,
+		Info:       best.info,
+		Confidence: best.confidence(),
+	}
+	for _, s := range scores {
+		if s == best {
+			continue
+		}
+		if len(match.Candidates) >= maxMatchCandidates {
+			break
+		}
+		match.Candidates = append(match.Candidates, &MatchCandidate{
+			MovieSearchResult: s.result,
+			Confidence:        s.confidence(),
+		})
+	}
+	return match, nil
+}
+
+// newMatchScore scores the search result against the parsed number,
+// priority is the provider priority relative to the max one.
+func newMatchScore(n *number.Number, result *model.MovieSearchResult, priority float64) *matchScore {
+	s := &matchScore{
+		result: result,
+		// the similarity is negative for numbers very different.
+		number:   math.Max(0, comparer.Compare(normalizeMatchNumber(n.Number), normalizeMatchNumber(result.Number))),
+		priority: priority,
+		date:     releaseDatePlausibility(time.Time(result.ReleaseDate)),
+	}
+	if n.Studio != "" && strings.EqualFold(n.Studio, result.Provider) {
+		s.priority = 1 // hinted by filename.
+	}
+	return s
+}
+
+func sortMatchScores(scores []*matchScore) {
+	sort.SliceStable(scores, func(i, j int) bool {
+		return scores[i].confidence() > scores[j].confidence()
+	})
+}
+
+// normalizeMatchNumber keeps only letters and digits in upper case,
+// so that ABP-030 and abp030 are considered the same.
+func normalizeMatchNumber(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return -1
+	}, s)
+}
+
+// releaseDatePlausibility returns 1 for dates in a reasonable range, 0 for
+// dates far in the future or past, and 0.5 for unknown dates.
+func releaseDatePlausibility(date time.Time) float64 {
+	switch {
+	case date.IsZero():
+		return 0.5
+	case date.Year() < 1970, date.After(time.Now().AddDate(1, 0, 0)):
+		return 0
+	default:
+		return 1
+	}
+}
+
+// durationPlausibility returns how close the file duration is to the
+// movie runtime, both in seconds. Files shorter than the runtime are
+// penalized less, as they can be one part of a multi-part movie.
+func durationPlausibility(runtime, duration int) float64 {
+	diff := float64(runtime - duration)
+	if diff > 0 {
+		diff /= 2
+	}
+	return math.Max(0, 1-math.Abs(diff)/float64(runtime))
+}
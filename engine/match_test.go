@@ -0,0 +1,58 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/datatypes"
+
+	"github.com/metatube-community/metatube-sdk-go/common/number"
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+func TestNewMatchScore(t *testing.T) {
+	released := datatypes.Date(time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC))
+	for _, unit := range []struct {
+		name     string
+		filename string
+		result   string
+		provider string
+		match    bool
+	}{
+		{"exact", "ABP-030.mp4", "ABP-030", "JavBus", true},
+		{"exact case and separator", "abp030.mp4", "ABP-030", "JavBus", true},
+		{"exact with title", "[ABP-030] Some Movie Title 1080p.mkv", "ABP-030", "JavBus", true},
+		{"exact with studio", "FC2-PPV-1234567.mp4", "FC2-1234567", "FC2", true},
+		{"fuzzy suffix", "ABP-030.mp4", "ABP-030SP", "JavBus", true},
+		{"fuzzy prefix", "ABP-030.mp4", "KABP-030", "JavBus", true},
+		{"mismatched digits", "ABP-030.mp4", "ABP-999", "JavBus", false},
+		{"mismatched prefix", "ABP-030.mp4", "SSIS-001", "JavBus", false},
+		{"mismatched title", "[ABP-030] Some Movie Title.mp4", "Some Movie Title", "JavBus", false},
+	} {
+		t.Run(unit.name, func(t *testing.T) {
+			n := number.Parse(unit.filename)
+			s := newMatchScore(n, &model.MovieSearchResult{
+				Number:      unit.result,
+				Provider:    unit.provider,
+				ReleaseDate: released,
+			}, 1)
+			assert.True(t, s.confidence() >= 0 && s.confidence() <= 1, s.confidence())
+			assert.Equal(t, unit.match, s.confidence() >= DefaultMatchThreshold, "%s vs %s", n.Number, unit.result)
+		})
+	}
+}
+
+func TestDurationPlausibility(t *testing.T) {
+	for _, unit := range []struct {
+		runtime, duration int
+		want              float64
+	}{
+		{7200, 7200, 1},
+		{7200, 3600, 0.75}, // one part of a multi-part movie.
+		{7200, 10800, 0.5},
+		{7200, 21600, 0},
+	} {
+		assert.InDelta(t, unit.want, durationPlausibility(unit.runtime, unit.duration), 1e-9, unit)
+	}
+}
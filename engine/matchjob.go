@@ -0,0 +1,410 @@
+package engine
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	goerr "errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/metatube-community/metatube-sdk-go/common/number"
+	"github.com/metatube-community/metatube-sdk-go/errors"
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+const (
+	// DefaultMatchJobConcurrency is the number of items matched at a time.
+	DefaultMatchJobConcurrency = 4
+	// MaxMatchJobConcurrency is the max allowed concurrency of a job.
+	MaxMatchJobConcurrency = 16
+	// MaxMatchJobItems is the max number of items in a single job.
+	MaxMatchJobItems = 50000
+
+	// matchJobRetention is how long finished jobs are kept.
+	matchJobRetention = 24 * time.Hour
+	// matchJobSaveInterval is how often the progress of running jobs
+	// is saved, which is also the heartbeat of the jobs.
+	matchJobSaveInterval = 10 * time.Second
+	// matchJobStaleAfter is how long a running job can go without
+	// heartbeats, before it's considered interrupted, e.g. restarted.
+	matchJobStaleAfter = 6 * matchJobSaveInterval
+)
+
+var ErrMatchJobNotFound = errors.New(http.StatusNotFound, "match job not found")
+
+type MatchJobStatus string
+
+const (
+	MatchJobRunning MatchJobStatus = "running"
+	MatchJobDone    MatchJobStatus = "done"
+	// MatchJobInterrupted is a job stopped before done, e.g. by a
+	// restart, its results are lost.
+	MatchJobInterrupted MatchJobStatus = "interrupted"
+)
+
+// MatchJobItem is the match result of an input filename or number.
+type MatchJobItem struct {
+	Input  string       `json:"input"`
+	Result *MatchResult `json:"result,omitempty"`
+	Error  string       `json:"error,omitempty"`
+	done   bool
+}
+
+// MatchJobProgress is a snapshot of the job progress.
+type MatchJobProgress struct {
+	ID         string         `json:"id"`
+	Status     MatchJobStatus `json:"status"`
+	Total      int            `json:"total"`
+	Unique     int            `json:"unique"`
+	Completed  int            `json:"completed"`
+	Matched    int            `json:"matched"`
+	CreatedAt  time.Time      `json:"created_at"`
+	FinishedAt *time.Time     `json:"finished_at,omitempty"`
+}
+
+// MatchJob matches many filenames in the background, identical
+// numbers within a job are matched only once.
+type MatchJob struct {
+	mu       sync.RWMutex
+	progress MatchJobProgress
+	items    []*MatchJobItem
+}
+
+// Progress returns a snapshot of the job progress.
+func (j *MatchJob) Progress() MatchJobProgress {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.progress
+}
+
+// completedItems returns the completed items in input order by page,
+// limit <= 0 means no limit.
+func (j *MatchJob) completedItems(limit, offset int) []MatchJobItem {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	var items []MatchJobItem
+	for _, item := range j.items {
+		if !item.done {
+			continue
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		if limit > 0 && len(items) >= limit {
+			break
+		}
+		items = append(items, *item)
+	}
+	return items
+}
+
+// complete sets the results of the items of the same number, each
+// item keeps the number parsed from its own input, e.g. parts and
+// subtitle flags differ.
+func (j *MatchJob) complete(items []*MatchJobItem, result *MatchResult, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	for _, item := range items {
+		item.Result, item.done = result, true
+		if result != nil {
+			r := *result
+			r.Number = number.Parse(item.Input)
+			item.Result = &r
+		}
+		if err != nil {
+			item.Error = err.Error()
+		} else {
+			j.progress.Matched++
+		}
+		j.progress.Completed++
+	}
+}
+
+func (j *MatchJob) finish() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	now := time.Now()
+	j.progress.Status = MatchJobDone
+	j.progress.FinishedAt = &now
+}
+
+// model returns the persisted form of the job.
+func (j *MatchJob) model() *model.MatchJob {
+	j.mu.RLock()
+	p := j.progress
+	j.mu.RUnlock()
+	m := &model.MatchJob{
+		ID:         p.ID,
+		Status:     string(p.Status),
+		Total:      p.Total,
+		Unique:     p.Unique,
+		Completed:  p.Completed,
+		Matched:    p.Matched,
+		FinishedAt: p.FinishedAt,
+	}
+	m.CreatedAt = p.CreatedAt
+	return m
+}
+
+// itemModels returns the persisted form of the completed items.
+func (j *MatchJob) itemModels() ([]*model.MatchJobItem, error) {
+	items := j.completedItems(0, 0)
+	models := make([]*model.MatchJobItem, 0, len(items))
+	for i, item := range items {
+		m := &model.MatchJobItem{
+			JobID: j.progress.ID,
+			Seq:   i,
+			Input: item.Input,
+			Error: item.Error,
+		}
+		if item.Result != nil {
+			data, err := json.Marshal(item.Result)
+			if err != nil {
+				return nil, err
+			}
+			m.Result = data
+		}
+		models = append(models, m)
+	}
+	return models, nil
+}
+
+// newMatchJobItemFromModel restores the item saved in DB.
+func newMatchJobItemFromModel(m *model.MatchJobItem) (MatchJobItem, error) {
+	item := MatchJobItem{Input: m.Input, Error: m.Error, done: true}
+	if len(m.Result) > 0 {
+		item.Result = &MatchResult{}
+		if err := json.Unmarshal(m.Result, item.Result); err != nil {
+			return item, err
+		}
+	}
+	return item, nil
+}
+
+// newMatchJobFromModel restores the job saved in DB, without items.
+func newMatchJobFromModel(m *model.MatchJob) *MatchJob {
+	job := &MatchJob{
+		progress: MatchJobProgress{
+			ID:         m.ID,
+			Status:     MatchJobStatus(m.Status),
+			Total:      m.Total,
+			Unique:     m.Unique,
+			Completed:  m.Completed,
+			Matched:    m.Matched,
+			CreatedAt:  m.CreatedAt,
+			FinishedAt: m.FinishedAt,
+		},
+	}
+	if job.progress.Status == MatchJobRunning && time.Since(m.UpdatedAt) > matchJobStaleAfter {
+		job.progress.Status = MatchJobInterrupted
+	}
+	return job
+}
+
+// matchJobs holds the running jobs of this instance, finished jobs
+// are read from DB.
+type matchJobs struct {
+	mu   sync.Mutex
+	jobs map[string]*MatchJob
+}
+
+func (s *matchJobs) add(job *MatchJob) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.jobs == nil {
+		s.jobs = make(map[string]*MatchJob)
+	}
+	// prune expired jobs, which failed to be saved when finished.
+	for id, j := range s.jobs {
+		if p := j.Progress(); p.FinishedAt != nil && time.Since(*p.FinishedAt) > matchJobRetention {
+			delete(s.jobs, id)
+		}
+	}
+	s.jobs[job.progress.ID] = job
+}
+
+func (s *matchJobs) remove(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.jobs, id)
+}
+
+func (s *matchJobs) get(id string) (*MatchJob, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	return job, ok
+}
+
+// StartMatchJob starts matching the inputs in the background with
+// bounded concurrency, and returns the job to track its progress.
+// Inputs without any number are failed without matching.
+func (e *Engine) StartMatchJob(inputs []string, threshold float64, concurrency int) (*MatchJob, error) {
+	if len(inputs) == 0 || len(inputs) > MaxMatchJobItems {
+		return nil, errors.New(http.StatusBadRequest, "invalid number of items")
+	}
+	if concurrency <= 0 {
+		concurrency = DefaultMatchJobConcurrency
+	}
+	concurrency = min(concurrency, MaxMatchJobConcurrency)
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	job := &MatchJob{
+		progress: MatchJobProgress{
+			ID:        hex.EncodeToString(id),
+			Status:    MatchJobRunning,
+			Total:     len(inputs),
+			CreatedAt: time.Now(),
+		},
+		items: make([]*MatchJobItem, 0, len(inputs)),
+	}
+
+	// group items by the normalized number.
+	var (
+		keys    []string
+		groups  = make(map[string][]*MatchJobItem)
+		invalid []*MatchJobItem
+	)
+	for _, input := range inputs {
+		item := &MatchJobItem{Input: input}
+		job.items = append(job.items, item)
+		key := normalizeMatchNumber(number.Trim(input))
+		if key == "" {
+			invalid = append(invalid, item)
+			continue
+		}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], item)
+	}
+	job.progress.Unique = len(keys)
+	for _, item := range invalid {
+		job.complete([]*MatchJobItem{item}, nil, mt.ErrInvalidKeyword)
+	}
+
+	if n, err := e.dbe.DeleteMatchJobs(time.Now().Add(-matchJobRetention)); err != nil {
+		e.logger.Printf("Prune match jobs: %v", err)
+	} else if n > 0 {
+		e.logger.Printf("Pruned %d expired match jobs", n)
+	}
+	if err := e.dbe.SaveMatchJob(job.model(), nil); err != nil {
+		return nil, err
+	}
+	e.matchJobs.add(job)
+
+	go func() {
+		var (
+			wg   sync.WaitGroup
+			sem  = make(chan struct{}, concurrency)
+			stop = make(chan struct{})
+			done = make(chan struct{})
+		)
+		go func() {
+			defer close(done)
+			ticker := time.NewTicker(matchJobSaveInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-stop:
+					return
+				case <-ticker.C:
+					e.saveMatchJobProgress(job)
+				}
+			}
+		}()
+
+		for _, key := range keys {
+			items := groups[key]
+			sem <- struct{}{}
+			wg.Add(1)
+			go func() {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				result, err := e.MatchMovie(items[0].Input, 0, threshold)
+				job.complete(items, result, err)
+			}()
+		}
+		wg.Wait()
+
+		// the progress must not be saved after the final save.
+		close(stop)
+		<-done
+		e.finishMatchJob(job)
+		e.logger.Printf("Match job %s done: %d/%d matched", job.progress.ID, job.Progress().Matched, len(inputs))
+	}()
+	return job, nil
+}
+
+func (e *Engine) saveMatchJobProgress(job *MatchJob) {
+	m := job.model()
+	if err := e.dbe.SaveMatchJobProgress(m); err != nil {
+		e.logger.Printf("Save match job %s progress: %v", m.ID, err)
+	}
+}
+
+// finishMatchJob saves the finished job with its items, and evicts it
+// from memory, or keeps it there if it fails to be saved.
+func (e *Engine) finishMatchJob(job *MatchJob) {
+	job.finish()
+	m := job.model()
+	items, err := job.itemModels()
+	if err == nil {
+		err = e.dbe.SaveMatchJob(m, items)
+	}
+	if err != nil {
+		e.logger.Printf("Save match job %s: %v", job.Progress().ID, err)
+		return
+	}
+	e.matchJobs.remove(m.ID)
+}
+
+// GetMatchJob returns the match job by id.
+func (e *Engine) GetMatchJob(id string) (*MatchJob, error) {
+	if job, ok := e.matchJobs.get(id); ok {
+		return job, nil
+	}
+	m, err := e.dbe.GetMatchJob(id)
+	if err != nil {
+		if goerr.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMatchJobNotFound
+		}
+		return nil, err
+	}
+	return newMatchJobFromModel(m), nil
+}
+
+// GetMatchJobItems returns the completed items of the match job in
+// input order by page, limit <= 0 means no limit.
+func (e *Engine) GetMatchJobItems(id string, limit, offset int) ([]MatchJobItem, error) {
+	if job, ok := e.matchJobs.get(id); ok {
+		return job.completedItems(limit, offset), nil
+	}
+	if _, err := e.GetMatchJob(id); err != nil {
+		return nil, err
+	}
+	models, err := e.dbe.GetMatchJobItems(id, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]MatchJobItem, 0, len(models))
+	for _, m := range models {
+		item, err := newMatchJobItemFromModel(m)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
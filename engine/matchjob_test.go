@@ -0,0 +1,117 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metatube-community/metatube-sdk-go/common/number"
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+func waitMatchJob(t *testing.T, e *Engine, id string) *MatchJob {
+	var job *MatchJob
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = e.GetMatchJob(id)
+		require.NoError(t, err)
+		return job.Progress().Status == MatchJobDone
+	}, 5*time.Second, 10*time.Millisecond)
+	return job
+}
+
+func TestEngine_StartMatchJob(t *testing.T) {
+	db := openTestDB(t)
+	e := New(db)
+
+	_, err := e.StartMatchJob(nil, DefaultMatchThreshold, 0)
+	assert.Error(t, err)
+
+	// inputs without numbers are failed without matching.
+	job, err := e.StartMatchJob([]string{"", "---.mp4", "!!!"}, DefaultMatchThreshold, 0)
+	require.NoError(t, err)
+	id := job.Progress().ID
+
+	job = waitMatchJob(t, e, id)
+	progress := job.Progress()
+	assert.Equal(t, 3, progress.Total)
+	assert.Zero(t, progress.Unique)
+	assert.Equal(t, 3, progress.Completed)
+	assert.Zero(t, progress.Matched)
+	items, err := e.GetMatchJobItems(id, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+	for _, item := range items {
+		assert.Equal(t, mt.ErrInvalidKeyword.Error(), item.Error)
+	}
+
+	// finished jobs are evicted from memory, and read from DB.
+	require.Eventually(t, func() bool {
+		_, ok := e.matchJobs.get(id)
+		return !ok
+	}, 5*time.Second, 10*time.Millisecond)
+	job, err = e.GetMatchJob(id)
+	require.NoError(t, err)
+	assert.Equal(t, progress.Completed, job.Progress().Completed)
+	saved, err := e.GetMatchJobItems(id, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, items, saved)
+
+	// items are read by pages.
+	saved, err = e.GetMatchJobItems(id, 2, 1)
+	require.NoError(t, err)
+	assert.Equal(t, items[1:3], saved)
+
+	_, err = e.GetMatchJob("unknown")
+	assert.ErrorIs(t, err, ErrMatchJobNotFound)
+	_, err = e.GetMatchJobItems("unknown", 0, 0)
+	assert.ErrorIs(t, err, ErrMatchJobNotFound)
+}
+
+func TestMatchJob_Complete(t *testing.T) {
+	job := &MatchJob{}
+	items := []*MatchJobItem{
+		{Input: "ABP-123.mp4"},
+		{Input: "ABP-123-C-cd2.mp4"},
+	}
+	job.items = items
+	result := &MatchResult{Number: number.Parse(items[0].Input), Confidence: 0.9}
+	job.complete(items, result, nil)
+
+	// the same match, but the number of each input.
+	for _, item := range job.completedItems(0, 0) {
+		require.NotNil(t, item.Result)
+		assert.Equal(t, "ABP-123", item.Result.Number.Number)
+		assert.Equal(t, 0.9, item.Result.Confidence)
+	}
+	assert.False(t, items[0].Result.Number.Subtitle)
+	assert.True(t, items[1].Result.Number.Subtitle)
+	assert.Equal(t, 2, items[1].Result.Number.Part)
+	assert.Equal(t, 2, job.Progress().Matched)
+}
+
+func TestEngine_GetMatchJob(t *testing.T) {
+	db := openTestDB(t)
+	e := New(db)
+
+	// running on no instance, e.g. restarted.
+	stale := &model.MatchJob{ID: "stale", Status: string(MatchJobRunning), Total: 1}
+	stale.UpdatedAt = time.Now().Add(-2 * matchJobStaleAfter)
+	require.NoError(t, db.Create(stale).Error)
+	job, err := e.GetMatchJob(stale.ID)
+	require.NoError(t, err)
+	assert.Equal(t, MatchJobInterrupted, job.Progress().Status)
+
+	// expired jobs are pruned when a job is started.
+	expired := &model.MatchJob{ID: "expired", Status: string(MatchJobDone)}
+	expired.UpdatedAt = time.Now().Add(-2 * matchJobRetention)
+	require.NoError(t, db.Create(expired).Error)
+	started, err := e.StartMatchJob([]string{""}, DefaultMatchThreshold, 0)
+	require.NoError(t, err)
+	waitMatchJob(t, e, started.Progress().ID)
+	_, err = e.GetMatchJob(expired.ID)
+	assert.ErrorIs(t, err, ErrMatchJobNotFound)
+}
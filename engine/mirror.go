@@ -0,0 +1,48 @@
+package engine
+
+import (
+	"net/url"
+	"strings"
+
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+// providerBaseURLs returns all base URLs of the provider, including mirrors.
+func providerBaseURLs(provider mt.Provider) []*url.URL {
+	if g, ok := provider.(mt.MirrorGetter); ok {
+		return g.Mirrors()
+	}
+	return []*url.URL{provider.URL()}
+}
+
+// providerHosts returns the unique hostnames of all base URLs of the provider.
+func providerHosts(provider mt.Provider) (hosts []string) {
+	seen := make(map[string]struct{})
+	for _, u := range providerBaseURLs(provider) {
+		host := strings.ToLower(u.Hostname())
+		if _, ok := seen[host]; !ok {
+			seen[host] = struct{}{}
+			hosts = append(hosts, host)
+		}
+	}
+	return
+}
+
+// matchProviderURL reports whether u belongs to any base URL of the provider.
+func matchProviderURL(provider mt.Provider, u *url.URL) bool {
+	for _, base := range providerBaseURLs(provider) {
+		if strings.EqualFold(u.Hostname(), base.Hostname()) &&
+			strings.HasPrefix(u.Path, base.Path) {
+			return true
+		}
+	}
+	return false
+}
+
+// preferredURL rewrites the URL to the working mirror of the provider, if any.
+func preferredURL(provider mt.Provider, rawURL string) string {
+	if g, ok := provider.(mt.MirrorGetter); ok && rawURL != "" {
+		return g.PreferredURL(rawURL)
+	}
+	return rawURL
+}
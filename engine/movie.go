@@ -7,8 +7,6 @@ import (
 	"sync"
 	"time"
 
-	"gorm.io/gorm/clause"
-
 	"github.com/metatube-community/metatube-sdk-go/collection/sets"
 	"github.com/metatube-community/metatube-sdk-go/collection/slices"
 	"github.com/metatube-community/metatube-sdk-go/common/comparer"
@@ -18,34 +16,23 @@ import (
 	mt "github.com/metatube-community/metatube-sdk-go/provider"
 )
 
-func (e *Engine) searchMovieFromDB(keyword string, provider mt.MovieProvider, all bool) (results []*model.MovieSearchResult, err error) {
-	var infos []*model.MovieInfo
-	tx := e.db.
-		// Note: keyword might be an ID or just a regular number, so we should
-		// query both of them for best match. Also, case should not matter.
-		Where("number = ? COLLATE NOCASE", keyword).
-		Or("id = ? COLLATE NOCASE", keyword)
-	if all {
-		err = tx.Find(&infos).Error
-	} else {
-		err = e.db.
-			Where("provider = ?", provider.Name()).
-			Where(tx).
-			Find(&infos).Error
-	}
-	if err == nil {
-		for _, info := range infos {
-			if !info.IsValid() {
-				// normally it is valid, but just in case.
-				continue
-			}
-			results = append(results, info.ToSearchResult())
-		}
+func (e *Engine) searchMovieFromDB(keyword string, provider mt.MovieProvider, all bool) ([]*model.MovieSearchResult, error) {
+	var name string
+	if !all {
+		name = provider.Name()
 	}
-	return
+	// Note: keyword might be an ID or just a regular number, so we should
+	// query both of them for the exact match. Also, case should not matter.
+	return e.dbe.FindMovies(keyword, name)
 }
 
 func (e *Engine) searchMovie(keyword string, provider mt.MovieProvider, fallback bool) (results []*model.MovieSearchResult, err error) {
+	defer func() {
+		// rewrite homepages to the working mirror.
+		for _, result := range results {
+			result.Homepage = preferredURL(provider, result.Homepage)
+		}
+	}()
 	// Regular keyword searching.
 	if searcher, ok := provider.(mt.MovieSearcher); ok {
 		if keyword = searcher.NormalizeMovieKeyword(keyword); keyword == "" {
@@ -76,6 +63,20 @@ func (e *Engine) searchMovie(keyword string, provider mt.MovieProvider, fallback
 	return []*model.MovieSearchResult{info.ToSearchResult()}, nil
 }
 
+// validMovieResults drops invalid results, and rewrites the
+// homepages to the working mirror of the provider.
+func validMovieResults(provider mt.MovieProvider, results []*model.MovieSearchResult) []*model.MovieSearchResult {
+	filtered := make([]*model.MovieSearchResult, 0, len(results))
+	for _, result := range results {
+		if !result.IsValid() {
+			continue
+		}
+		result.Homepage = preferredURL(provider, result.Homepage)
+		filtered = append(filtered, result)
+	}
+	return filtered
+}
+
 func (e *Engine) SearchMovie(keyword, name string, fallback bool) ([]*model.MovieSearchResult, error) {
 	if keyword = number.Trim(keyword); keyword == "" {
 		return nil, mt.ErrInvalidKeyword
@@ -97,8 +98,10 @@ func (e *Engine) searchMovieAll(keyword string) (results []*model.MovieSearchRes
 	}
 	respCh := make(chan response)
 
+	providers := e.GetMovieProviders()
+
 	var wg sync.WaitGroup
-	for _, provider := range e.movieProviders.Iterator() {
+	for _, provider := range providers {
 		wg.Add(1)
 		// Goroutine started time.
 		startTime := time.Now()
@@ -121,7 +124,7 @@ func (e *Engine) searchMovieAll(keyword string) (results []*model.MovieSearchRes
 		close(respCh)
 	}()
 
-	ds := make([]string, 0, e.movieProviders.Len())
+	ds := make([]string, 0, len(providers))
 	// response channel.
 	for resp := range respCh {
 		ds = append(ds, func(a, b, c any) string {
@@ -169,12 +172,14 @@ func (e *Engine) SearchMovieAll(keyword string, fallback bool) (results []*model
 			if !result.IsValid() /* validation check */ {
 				continue
 			}
-			if _, err := e.GetMovieProviderByName(result.Provider); err != nil {
+			provider, err := e.GetMovieProviderByName(result.Provider)
+			if err != nil {
 				e.logger.Printf("ignore provider %s as not found", result.Provider)
 				continue
 			}
+			result.Homepage = preferredURL(provider, result.Homepage)
 			priority := comparer.Compare(keyword, result.Number) *
-				e.MustGetMovieProviderByName(result.Provider).Priority()
+				provider.Priority()
 			ps.Append(result, priority)
 		}
 		// sort by priority.
@@ -199,12 +204,7 @@ func (e *Engine) SearchMovieAll(keyword string, fallback bool) (results []*model
 }
 
 func (e *Engine) getMovieInfoFromDB(provider mt.MovieProvider, id string) (*model.MovieInfo, error) {
-	info := &model.MovieInfo{}
-	err := e.db. // Exact match here.
-			Where("provider = ?", provider.Name()).
-			Where("id = ? COLLATE NOCASE", id).
-			First(info).Error
-	return info, err
+	return e.dbe.GetMovieInfo(providerid.ProviderID{Provider: provider.Name(), ID: id})
 }
 
 func (e *Engine) getMovieInfoWithCallback(provider mt.MovieProvider, id string, lazy bool, callback func() (*model.MovieInfo, error)) (info *model.MovieInfo, err error) {
@@ -214,9 +214,25 @@ func (e *Engine) getMovieInfoWithCallback(provider mt.MovieProvider, id string,
 			err = mt.ErrIncompleteMetadata
 		}
 	}()
+	defer func() {
+		// rewrite homepage to the working mirror, this happens
+		// after auto-save, so the original homepage is stored.
+		if err == nil && info != nil {
+			info.Homepage = preferredURL(provider, info.Homepage)
+		}
+	}()
+	defer func() {
+		// apply manual overrides after auto-save, so that they
+		// are never stored in, nor replaced by the metadata.
+		if err == nil && info != nil {
+			e.applyMovieOverride(info)
+		}
+	}()
 	// Query DB first (by id).
 	if lazy {
 		if info, err = e.getMovieInfoFromDB(provider, id); err == nil && info.IsValid() {
+			// serve the stale info while it's being refreshed.
+			e.refreshMovieInfoIfStale(provider, info)
 			return // ignore DB query error.
 		}
 	}
@@ -240,9 +256,8 @@ func (e *Engine) getMovieInfoWithCallback(provider mt.MovieProvider, id string,
 					return
 				}
 			}
-			e.db.Clauses(clause.OnConflict{
-				UpdateAll: true,
-			}).Create(info) // ignore error
+			e.dbe.SaveMovieInfo(info) // ignore error
+			e.saveMovieCollections(info)
 		}
 	}()
 	return callback()
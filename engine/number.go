@@ -0,0 +1,145 @@
+package engine
+
+import (
+	goerr "errors"
+	"net/http"
+	"strings"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	"github.com/metatube-community/metatube-sdk-go/errors"
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+const (
+	DefaultNumberDigits    = 3
+	DefaultNumberMaxMisses = 20
+)
+
+var (
+	ErrNumberPrefixNotFound = errors.New(http.StatusNotFound, "number prefix not found")
+	ErrNumberPrefixExists   = errors.New(http.StatusConflict, "number prefix already exists")
+	ErrInvalidNumberPrefix  = errors.New(http.StatusBadRequest, "invalid number prefix or range")
+)
+
+// NumberPrefixUpdate describes changes of a tracked number prefix, nil
+// fields are left unchanged, or set to the defaults on creation.
+type NumberPrefixUpdate struct {
+	Digits    *int
+	MinSuffix *int
+	MaxSuffix *int
+	MaxMisses *int
+	Paused    *bool
+	// Reset clears the crawl progress, so that the prefix is crawled
+	// from the start, and the gaps are tried again.
+	Reset bool
+}
+
+// GetNumberPrefixes returns all number prefixes tracked by the crawler.
+func (e *Engine) GetNumberPrefixes() ([]*model.NumberStatus, error) {
+	return e.dbe.GetNumberStatuses()
+}
+
+// GetNumberPrefixMovies returns the provider, id and number of the saved
+// movies numbered by the prefix, the suffixes are not checked.
+func (e *Engine) GetNumberPrefixMovies(prefix string) ([]*model.MovieSearchResult, error) {
+	return e.dbe.GetMovieNumbers(prefix)
+}
+
+func (e *Engine) GetNumberPrefix(prefix string) (*model.NumberStatus, error) {
+	status, err := e.dbe.GetNumberStatus(prefix)
+	if goerr.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNumberPrefixNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// AddNumberPrefix tracks the number prefix, e.g. ABP, to be crawled by
+// the number status job. The suffixes are 3 digits by default, and the
+// range defaults to all suffixes of the digits except zero.
+func (e *Engine) AddNumberPrefix(prefix string, u *NumberPrefixUpdate) (*model.NumberStatus, error) {
+	if _, err := e.GetNumberPrefix(prefix); err == nil {
+		return nil, ErrNumberPrefixExists
+	} else if !goerr.Is(err, ErrNumberPrefixNotFound) {
+		return nil, err
+	}
+	status := &model.NumberStatus{
+		NumberPrefix: strings.ToUpper(prefix),
+		Digits:       DefaultNumberDigits,
+		MinSuffix:    1,
+		MaxSuffix:    model.MaxNumberSuffix(DefaultNumberDigits),
+		MaxMisses:    DefaultNumberMaxMisses,
+	}
+	applyNumberPrefixUpdate(status, u)
+	if !status.IsValid() {
+		return nil, ErrInvalidNumberPrefix
+	}
+	if err := e.dbe.CreateNumberStatus(status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// UpdateNumberPrefix applies the update to the tracked number prefix.
+func (e *Engine) UpdateNumberPrefix(prefix string, u *NumberPrefixUpdate) (*model.NumberStatus, error) {
+	status, err := e.GetNumberPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+	applyNumberPrefixUpdate(status, u)
+	if !status.IsValid() {
+		return nil, ErrInvalidNumberPrefix
+	}
+	if err = e.dbe.SaveNumberStatus(status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// DeleteNumberPrefix stops tracking the number prefix, the movies
+// found are kept.
+func (e *Engine) DeleteNumberPrefix(prefix string) error {
+	if _, err := e.GetNumberPrefix(prefix); err != nil {
+		return err
+	}
+	return e.dbe.DeleteNumberStatus(prefix)
+}
+
+func applyNumberPrefixUpdate(status *model.NumberStatus, u *NumberPrefixUpdate) {
+	if u.Digits != nil {
+		// the max suffix follows the digits, unless it's customized.
+		if u.MaxSuffix == nil && *u.Digits > 0 && *u.Digits <= model.MaxNumberDigits &&
+			status.MaxSuffix == model.MaxNumberSuffix(status.Digits) {
+			status.MaxSuffix = model.MaxNumberSuffix(*u.Digits)
+		}
+		status.Digits = *u.Digits
+	}
+	if u.MinSuffix != nil {
+		status.MinSuffix = *u.MinSuffix
+	}
+	if u.MaxSuffix != nil {
+		status.MaxSuffix = *u.MaxSuffix
+	}
+	if u.MaxMisses != nil {
+		status.MaxMisses = *u.MaxMisses
+	}
+	if u.Reset {
+		status.LastFound, status.Found = 0, 0
+		status.Gaps = datatypes.NewJSONType[[]int](nil)
+		status.LastError = ""
+	}
+	switch {
+	case u.Paused != nil && *u.Paused:
+		status.Status = model.NumberStatusPaused
+	case u.Paused != nil || status.Status != model.NumberStatusPaused:
+		// the range may be extended beyond the last found.
+		status.Status = model.NumberStatusActive
+		if status.LastFound >= status.MaxSuffix {
+			status.Status = model.NumberStatusDone
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	mt "github.com/metatube-community/metatube-sdk-go/provider"
+	"github.com/metatube-community/metatube-sdk-go/translate"
 )
 
 type Option func(*Engine)
@@ -31,3 +32,17 @@ func WithMovieProviderConfig(name string, config mt.Config) Option {
 		e.movieProviderConfigs.Set(name, config)
 	}
 }
+
+func WithTranslator(translator translate.Translator) Option {
+	return func(e *Engine) {
+		e.translator = translator
+	}
+}
+
+// WithFreshnessPolicies sets the default freshness policy, and the
+// ones by provider names, see Engine.SetFreshnessPolicies.
+func WithFreshnessPolicies(fallback FreshnessPolicy, providers map[string]FreshnessPolicy) Option {
+	return func(e *Engine) {
+		e.freshness.set(fallback, providers)
+	}
+}
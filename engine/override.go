@@ -0,0 +1,162 @@
+package engine
+
+import (
+	"bytes"
+	goerr "errors"
+	"maps"
+	"net/http"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	"github.com/metatube-community/metatube-sdk-go/engine/providerid"
+	"github.com/metatube-community/metatube-sdk-go/errors"
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+// OverrideMovieInfo sets the manual overrides of the movie info, which
+// are locked from scrapes and applied on read. The overrides are replaced
+// by the fields, or merged into if merge is true, where null values unlock
+// the fields. It returns the movie info with the overrides applied.
+func (e *Engine) OverrideMovieInfo(pid providerid.ProviderID, fields model.OverrideFields, merge bool) (*model.MovieInfo, error) {
+	provider, err := e.GetMovieProviderByName(pid.Provider)
+	if err != nil {
+		return nil, err
+	}
+	// make sure the original info is saved.
+	if _, err = e.getMovieInfoByProviderID(provider, pid.ID, true); err != nil {
+		return nil, err
+	}
+	info, err := e.getMovieInfoFromDB(provider, provider.NormalizeMovieID(pid.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	key := providerid.ProviderID{Provider: info.Provider, ID: info.ID}
+	current := make(model.OverrideFields)
+	if merge {
+		override, err := e.dbe.GetMovieOverride(key)
+		if err != nil && !goerr.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if err == nil {
+			maps.Copy(current, override.Fields.Data())
+		}
+	}
+	mergeOverrideFields(current, fields)
+
+	override := &model.MovieOverride{
+		ID:       info.ID,
+		Provider: info.Provider,
+		Fields:   datatypes.NewJSONType(current),
+	}
+	if err = override.Apply(info); err != nil {
+		return nil, errors.New(http.StatusBadRequest, err.Error())
+	}
+	if !info.IsValid() {
+		return nil, errors.New(http.StatusBadRequest, "overridden movie info is invalid")
+	}
+	if len(current) == 0 {
+		err = e.dbe.DeleteMovieOverride(key)
+	} else {
+		err = e.dbe.SaveMovieOverride(override)
+	}
+	if err != nil {
+		return nil, err
+	}
+	info.Homepage = preferredURL(provider, info.Homepage)
+	return info, nil
+}
+
+// OverrideActorInfo sets the manual overrides of the actor info, the
+// same as OverrideMovieInfo.
+func (e *Engine) OverrideActorInfo(pid providerid.ProviderID, fields model.OverrideFields, merge bool) (*model.ActorInfo, error) {
+	provider, err := e.GetActorProviderByName(pid.Provider)
+	if err != nil {
+		return nil, err
+	}
+	// make sure the original info is saved.
+	if _, err = e.getActorInfoByProviderID(provider, pid.ID, true); err != nil {
+		return nil, err
+	}
+	info, err := e.getActorInfoFromDB(provider, provider.NormalizeActorID(pid.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	key := providerid.ProviderID{Provider: info.Provider, ID: info.ID}
+	current := make(model.OverrideFields)
+	if merge {
+		override, err := e.dbe.GetActorOverride(key)
+		if err != nil && !goerr.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if err == nil {
+			maps.Copy(current, override.Fields.Data())
+		}
+	}
+	mergeOverrideFields(current, fields)
+
+	override := &model.ActorOverride{
+		ID:       info.ID,
+		Provider: info.Provider,
+		Fields:   datatypes.NewJSONType(current),
+	}
+	if err = override.Apply(info); err != nil {
+		return nil, errors.New(http.StatusBadRequest, err.Error())
+	}
+	if !info.IsValid() {
+		return nil, errors.New(http.StatusBadRequest, "overridden actor info is invalid")
+	}
+	if len(current) == 0 {
+		err = e.dbe.DeleteActorOverride(key)
+	} else {
+		err = e.dbe.SaveActorOverride(override)
+	}
+	if err != nil {
+		return nil, err
+	}
+	info.Homepage = preferredURL(provider, info.Homepage)
+	return info, nil
+}
+
+// applyMovieOverride applies the manual overrides of the movie info if
+// any, errors are only logged, as the original info is still usable.
+func (e *Engine) applyMovieOverride(info *model.MovieInfo) {
+	override, err := e.dbe.GetMovieOverride(providerid.ProviderID{Provider: info.Provider, ID: info.ID})
+	if err != nil {
+		if !goerr.Is(err, gorm.ErrRecordNotFound) {
+			e.logger.Printf("Get movie override of %s:%s: %v", info.Provider, info.ID, err)
+		}
+		return
+	}
+	if err = override.Apply(info); err != nil {
+		e.logger.Printf("Apply movie override of %s:%s: %v", info.Provider, info.ID, err)
+	}
+}
+
+// applyActorOverride applies the manual overrides of the actor info.
+func (e *Engine) applyActorOverride(info *model.ActorInfo) {
+	override, err := e.dbe.GetActorOverride(providerid.ProviderID{Provider: info.Provider, ID: info.ID})
+	if err != nil {
+		if !goerr.Is(err, gorm.ErrRecordNotFound) {
+			e.logger.Printf("Get actor override of %s:%s: %v", info.Provider, info.ID, err)
+		}
+		return
+	}
+	if err = override.Apply(info); err != nil {
+		e.logger.Printf("Apply actor override of %s:%s: %v", info.Provider, info.ID, err)
+	}
+}
+
+// mergeOverrideFields merges the fields into dst, null values
+// remove the fields instead.
+func mergeOverrideFields(dst, fields model.OverrideFields) {
+	for key, value := range fields {
+		if bytes.Equal(bytes.TrimSpace(value), []byte("null")) {
+			delete(dst, key)
+			continue
+		}
+		dst[key] = value
+	}
+}
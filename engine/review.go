@@ -4,7 +4,6 @@ import (
 	"fmt"
 
 	"gorm.io/datatypes"
-	"gorm.io/gorm/clause"
 
 	"github.com/metatube-community/metatube-sdk-go/engine/providerid"
 	"github.com/metatube-community/metatube-sdk-go/model"
@@ -12,12 +11,7 @@ import (
 )
 
 func (e *Engine) getMovieReviewsFromDB(provider mt.MovieProvider, id string) (*model.MovieReviewInfo, error) {
-	info := &model.MovieReviewInfo{}
-	err := e.db. // Exact match here.
-			Where("provider = ?", provider.Name()).
-			Where("id = ? COLLATE NOCASE", id).
-			First(info).Error
-	return info, err
+	return e.dbe.GetMovieReviewInfo(providerid.ProviderID{Provider: provider.Name(), ID: id})
 }
 
 func (e *Engine) getMovieReviewsWithCallback(provider mt.MovieProvider, id string, lazy bool,
@@ -38,9 +32,7 @@ func (e *Engine) getMovieReviewsWithCallback(provider mt.MovieProvider, id strin
 	// delayed info auto-save.
 	defer func() {
 		if err == nil && info.IsValid() {
-			e.db.Clauses(clause.OnConflict{
-				UpdateAll: true,
-			}).Create(info) // ignore error
+			e.dbe.SaveMovieReviewInfo(info) // ignore error
 		}
 	}()
 
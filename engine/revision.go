@@ -0,0 +1,57 @@
+package engine
+
+import (
+	goerr "errors"
+	"net/http"
+
+	"gorm.io/gorm"
+
+	"github.com/metatube-community/metatube-sdk-go/engine/providerid"
+	"github.com/metatube-community/metatube-sdk-go/errors"
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+var ErrRevisionNotFound = errors.New(http.StatusNotFound, "revision not found")
+
+// GetMovieInfoRevisions returns the change history of the movie info
+// saved in DB, newest first.
+func (e *Engine) GetMovieInfoRevisions(pid providerid.ProviderID, limit, offset int) ([]*model.MetadataRevision, error) {
+	if _, err := e.GetMovieProviderByName(pid.Provider); err != nil {
+		return nil, err
+	}
+	return e.dbe.GetRevisions(model.MovieRevision, pid, limit, offset)
+}
+
+// GetActorInfoRevisions returns the change history of the actor info
+// saved in DB, newest first.
+func (e *Engine) GetActorInfoRevisions(pid providerid.ProviderID, limit, offset int) ([]*model.MetadataRevision, error) {
+	if _, err := e.GetActorProviderByName(pid.Provider); err != nil {
+		return nil, err
+	}
+	return e.dbe.GetRevisions(model.ActorRevision, pid, limit, offset)
+}
+
+// RollbackMovieInfo restores the movie info to the given revision.
+func (e *Engine) RollbackMovieInfo(pid providerid.ProviderID, revision uint64) (*model.MovieInfo, error) {
+	info, err := e.dbe.RollbackMovieInfo(pid, revision)
+	if err != nil {
+		if goerr.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRevisionNotFound
+		}
+		return nil, err
+	}
+	e.saveMovieCollections(info)
+	return info, nil
+}
+
+// RollbackActorInfo restores the actor info to the given revision.
+func (e *Engine) RollbackActorInfo(pid providerid.ProviderID, revision uint64) (*model.ActorInfo, error) {
+	info, err := e.dbe.RollbackActorInfo(pid, revision)
+	if err != nil {
+		if goerr.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRevisionNotFound
+		}
+		return nil, err
+	}
+	return info, nil
+}
@@ -0,0 +1,25 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metatube-community/metatube-sdk-go/engine/providerid"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+	onepondo "github.com/metatube-community/metatube-sdk-go/provider/1pondo"
+)
+
+func TestEngine_GetInfoRevisions(t *testing.T) {
+	e := New(openTestDB(t))
+
+	revisions, err := e.GetMovieInfoRevisions(providerid.ProviderID{Provider: onepondo.Name, ID: "123"}, 20, 0)
+	require.NoError(t, err)
+	assert.Empty(t, revisions)
+
+	_, err = e.GetMovieInfoRevisions(providerid.ProviderID{Provider: "unknown", ID: "123"}, 20, 0)
+	assert.ErrorIs(t, err, mt.ErrProviderNotFound)
+	_, err = e.GetActorInfoRevisions(providerid.ProviderID{Provider: "unknown", ID: "123"}, 20, 0)
+	assert.ErrorIs(t, err, mt.ErrProviderNotFound)
+}
@@ -0,0 +1,80 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	"github.com/metatube-community/metatube-sdk-go/engine/dbengine"
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+var _ mt.SessionStore = (*sessionStore)(nil)
+
+// sessionStore persists provider session cookies in the DB.
+type sessionStore struct {
+	dbe dbengine.DBEngine
+}
+
+func (s *sessionStore) LoadCookies(name string) ([]*http.Cookie, error) {
+	session, err := s.dbe.GetProviderSession(name)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return session.HTTPCookies(), nil
+}
+
+func (s *sessionStore) SaveCookies(name string, cookies []*http.Cookie) error {
+	return s.dbe.SaveProviderSession(&model.ProviderSession{
+		Provider: name,
+		Cookies:  datatypes.NewJSONType(model.NewSessionCookies(cookies)),
+	})
+}
+
+func (e *Engine) applyProviderSession(providerType string, provider mt.Provider, config mt.Config) error {
+	const (
+		usernameConfigKey = "username"
+		passwordConfigKey = "password"
+		cookiesConfigKey  = "cookies"
+	)
+
+	s, ok := provider.(mt.SessionSetter)
+	if !ok || !s.SessionEnabled() {
+		if config.Has(usernameConfigKey) || config.Has(cookiesConfigKey) {
+			e.logger.Printf("Session is not supported by %s provider: %s", providerType, provider.Name())
+		}
+		return nil
+	}
+
+	// Apply login credentials, the password is never logged.
+	if config.Has(usernameConfigKey) || config.Has(passwordConfigKey) {
+		username, _ := config.GetString(usernameConfigKey)
+		password, _ := config.GetString(passwordConfigKey)
+		if (username == "") != (password == "") {
+			return fmt.Errorf("both %s and %s are required for %s provider: %s",
+				usernameConfigKey, passwordConfigKey, providerType, provider.Name())
+		}
+		e.logger.Printf("Override %s provider credentials: %s=%s", providerType, provider.Name(), username)
+		s.SetCredentials(username, password)
+	}
+
+	// Apply cookies in the Cookie header format, e.g. "a=1; b=2".
+	if config.Has(cookiesConfigKey) {
+		if v, err := config.GetString(cookiesConfigKey); err == nil {
+			cookies, err := http.ParseCookie(v)
+			if err != nil {
+				return fmt.Errorf("parse %s provider cookies for %s: %w", providerType, provider.Name(), err)
+			}
+			e.logger.Printf("Override %s provider cookies: %s=%d cookies", providerType, provider.Name(), len(cookies))
+			s.SetSessionCookies(cookies)
+		}
+	}
+	return nil
+}
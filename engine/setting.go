@@ -0,0 +1,282 @@
+package engine
+
+import (
+	goerr "errors"
+	"fmt"
+	gomaps "maps"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	"github.com/metatube-community/metatube-sdk-go/errors"
+	"github.com/metatube-community/metatube-sdk-go/internal/envconfig"
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+var (
+	ErrInvalidProviderType   = errors.New(http.StatusBadRequest, "invalid provider type")
+	ErrInvalidProviderUpdate = errors.New(http.StatusBadRequest, "invalid provider update")
+)
+
+// ProviderUpdate describes runtime changes of a provider,
+// nil fields are left unchanged.
+type ProviderUpdate struct {
+	Enabled  *bool
+	Priority *float64
+	Timeout  *time.Duration
+	// Config is merged into the current provider
+	// config, and then the whole config is reapplied.
+	Config map[string]string
+}
+
+// UpdateProvider applies the update to the provider at runtime and
+// persists it, so that it survives restarts. It's safe to call while
+// providers are being used.
+func (e *Engine) UpdateProvider(providerType, name string, u *ProviderUpdate) (*ProviderCapability, error) {
+	e.settingMu.Lock()
+	defer e.settingMu.Unlock()
+
+	e.mu.RLock()
+	provider, err := e.lookupProvider(providerType, name)
+	e.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	// Save first, so that a failed save leaves the runtime state
+	// unchanged, and restore the setting if the update is rejected.
+	prev, err := e.saveProviderSetting(providerType, provider.Name(), u)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = e.updateProvider(providerType, provider.Name(), u); err != nil {
+		if rErr := e.restoreProviderSetting(providerType, provider.Name(), prev); rErr != nil {
+			e.logger.Printf("Restore %s provider setting for %s: %v", providerType, provider.Name(), rErr)
+		}
+		return nil, err
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.newProviderCapability(providerType, provider, e.isProviderEnabled(providerType, provider.Name())), nil
+}
+
+// LoadProviderSettings applies the persisted provider settings, it should
+// be called after the DB migration. Unknown providers are ignored.
+func (e *Engine) LoadProviderSettings() error {
+	settings, err := e.dbe.GetProviderSettings()
+	if err != nil {
+		return err
+	}
+	for _, setting := range settings {
+		if _, err := e.updateProvider(setting.Type, setting.Name, &ProviderUpdate{
+			Enabled:  setting.Enabled,
+			Priority: setting.Priority,
+			Timeout:  setting.Timeout,
+			Config:   setting.Config.Data(),
+		}); err != nil {
+			e.logger.Printf("Apply %s provider setting for %s: %v", setting.Type, setting.Name, err)
+		}
+	}
+	return nil
+}
+
+// ReloadProviderConfig merges the config into the provider config and
+// reapplies it at runtime without persisting. If the priority is given,
+// the provider is enabled when it's positive, or disabled otherwise.
+func (e *Engine) ReloadProviderConfig(providerType, name string, config map[string]string) error {
+	u := &ProviderUpdate{Config: config}
+	for key, value := range config {
+		if strings.EqualFold(key, "priority") {
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return errors.New(http.StatusBadRequest, fmt.Sprintf("invalid priority: %s", value))
+			}
+			enabled := v > 0
+			u.Priority, u.Enabled = &v, &enabled
+		}
+	}
+	_, err := e.updateProvider(providerType, name, u)
+	return err
+}
+
+func (e *Engine) updateProvider(providerType, name string, u *ProviderUpdate) (mt.Provider, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	provider, err := e.lookupProvider(providerType, name)
+	if err != nil {
+		return nil, err
+	}
+	name = provider.Name()
+
+	// Validate before any changes.
+	if u.Enabled != nil && *u.Enabled {
+		priority := provider.Priority()
+		if u.Priority != nil {
+			priority = *u.Priority
+		}
+		if priority <= 0 {
+			return nil, errors.New(http.StatusBadRequest, "priority must be positive to enable provider")
+		}
+	}
+	if u.Timeout != nil {
+		if *u.Timeout <= 0 {
+			return nil, errors.New(http.StatusBadRequest, "timeout must be positive")
+		}
+		if _, ok := provider.(mt.RequestTimeoutSetter); !ok {
+			return nil, errors.New(http.StatusBadRequest, "timeout is not supported by provider")
+		}
+	}
+
+	// Merge and reapply config.
+	if len(u.Config) > 0 {
+		configs := e.actorProviderConfigs
+		if providerType == movieProviderType {
+			configs = e.movieProviderConfigs
+		}
+		config := envconfig.NewConfig()
+		if c, ok := configs.Get(name); ok {
+			if ec, ok := c.(*envconfig.Config); ok {
+				config = ec.Copy()
+			}
+		}
+		for key, value := range u.Config {
+			config.Set(key, value)
+		}
+		if err = e.applyProviderConfig(providerType, provider, config); err != nil {
+			return nil, errors.New(http.StatusBadRequest, err.Error())
+		}
+		configs.Set(name, config)
+	}
+
+	if u.Timeout != nil {
+		e.logger.Printf("Update %s provider request timeout: %s=%s", providerType, name, *u.Timeout)
+		provider.(mt.RequestTimeoutSetter).SetRequestTimeout(*u.Timeout)
+	}
+
+	if u.Priority != nil {
+		e.logger.Printf("Update %s provider priority: %s=%.2f", providerType, name, *u.Priority)
+		provider.SetPriority(*u.Priority)
+	}
+
+	wasEnabled := e.isProviderEnabled(providerType, name)
+	enabled := wasEnabled
+	switch {
+	case u.Enabled != nil:
+		enabled = *u.Enabled
+	case provider.Priority() <= 0:
+		enabled = false // same as initialization.
+	}
+	if enabled != wasEnabled {
+		e.logger.Printf("Update %s provider enabled: %s=%t", providerType, name, enabled)
+	} else if enabled && len(u.Config) > 0 {
+		// Re-register hosts, as mirrors may have changed.
+		e.setProviderEnabled(providerType, provider, false)
+	}
+	e.setProviderEnabled(providerType, provider, enabled)
+	return provider, nil
+}
+
+// lookupProvider finds the provider, enabled or not, e.mu must be held.
+func (e *Engine) lookupProvider(providerType, name string) (mt.Provider, error) {
+	switch providerType {
+	case actorProviderType:
+		if provider, ok := e.actorProviders.Get(name); ok {
+			return provider, nil
+		}
+		if provider, ok := e.disabledActorProviders.Get(name); ok {
+			return provider, nil
+		}
+	case movieProviderType:
+		if provider, ok := e.movieProviders.Get(name); ok {
+			return provider, nil
+		}
+		if provider, ok := e.disabledMovieProviders.Get(name); ok {
+			return provider, nil
+		}
+	default:
+		return nil, ErrInvalidProviderType
+	}
+	return nil, mt.ErrProviderNotFound
+}
+
+// isProviderEnabled must be called with e.mu held.
+func (e *Engine) isProviderEnabled(providerType, name string) bool {
+	if providerType == actorProviderType {
+		return e.actorProviders.Has(name)
+	}
+	return e.movieProviders.Has(name)
+}
+
+// setProviderEnabled must be called with e.mu held.
+func (e *Engine) setProviderEnabled(providerType string, provider mt.Provider, enabled bool) {
+	switch providerType {
+	case actorProviderType:
+		p := provider.(mt.ActorProvider)
+		if enabled {
+			e.enableActorProvider(p.Name(), p)
+		} else {
+			e.disableActorProvider(p.Name(), p)
+		}
+	case movieProviderType:
+		p := provider.(mt.MovieProvider)
+		if enabled {
+			e.enableMovieProvider(p.Name(), p)
+		} else {
+			e.disableMovieProvider(p.Name(), p)
+		}
+	}
+}
+
+// saveProviderSetting merges the update into the persisted setting,
+// and returns the previous one, or nil if not exists.
+func (e *Engine) saveProviderSetting(providerType, name string, u *ProviderUpdate) (*model.ProviderSetting, error) {
+	var prev *model.ProviderSetting
+	setting, err := e.dbe.GetProviderSetting(providerType, name)
+	switch {
+	case err == nil:
+		p := *setting
+		prev = &p
+	case goerr.Is(err, gorm.ErrRecordNotFound):
+		setting = &model.ProviderSetting{}
+	default:
+		return nil, fmt.Errorf("load %s provider setting for %s: %w", providerType, name, err)
+	}
+	setting.Type, setting.Name = providerType, name
+	if u.Enabled != nil {
+		setting.Enabled = u.Enabled
+	}
+	if u.Priority != nil {
+		setting.Priority = u.Priority
+	}
+	if u.Timeout != nil {
+		setting.Timeout = u.Timeout
+	}
+	// copy, as the previous setting shares the map.
+	config := gomaps.Clone(setting.Config.Data())
+	if config == nil {
+		config = make(map[string]string)
+	}
+	for key, value := range u.Config {
+		config[key] = value
+	}
+	setting.Config = datatypes.NewJSONType(config)
+	if err = e.dbe.SaveProviderSetting(setting); err != nil {
+		return nil, fmt.Errorf("save %s provider setting for %s: %w", providerType, name, err)
+	}
+	return prev, nil
+}
+
+// restoreProviderSetting restores the previous setting returned by
+// saveProviderSetting, or deletes the setting if there was none.
+func (e *Engine) restoreProviderSetting(providerType, name string, prev *model.ProviderSetting) error {
+	if prev == nil {
+		return e.dbe.DeleteProviderSetting(providerType, name)
+	}
+	return e.dbe.SaveProviderSetting(prev)
+}
@@ -0,0 +1,142 @@
+package engine
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/metatube-community/metatube-sdk-go/common/fetch"
+	"github.com/metatube-community/metatube-sdk-go/database"
+	"github.com/metatube-community/metatube-sdk-go/database/migrate"
+	"github.com/metatube-community/metatube-sdk-go/model"
+	onepondo "github.com/metatube-community/metatube-sdk-go/provider/1pondo"
+	"github.com/metatube-community/metatube-sdk-go/provider/mgstage"
+	"github.com/metatube-community/metatube-sdk-go/provider/theporndb"
+)
+
+func openTestDB(t *testing.T) *gorm.DB {
+	db, err := database.Open(&database.Config{
+		DSN:                  ":memory:",
+		MaxOpenConns:         1, // keep the memory DB.
+		DisableAutomaticPing: true,
+		LogLevel:             logger.Silent,
+	})
+	require.NoError(t, err)
+	_, err = migrate.New(db).Up()
+	require.NoError(t, err)
+	return db
+}
+
+func TestEngine_UpdateProvider(t *testing.T) {
+	db := openTestDB(t)
+	e := New(db)
+
+	priority := 10.0
+	_, err := e.UpdateProvider(movieProviderType, onepondo.Name, &ProviderUpdate{Priority: &priority})
+	require.NoError(t, err)
+	assert.Equal(t, priority, e.MustGetMovieProviderByName(onepondo.Name).Priority())
+
+	// rejected update restores the saved setting.
+	disabled := false
+	_, err = e.UpdateProvider(movieProviderType, onepondo.Name, &ProviderUpdate{
+		Enabled: &disabled,
+		Config:  map[string]string{"proxy": "://invalid"},
+	})
+	assert.Error(t, err)
+	setting := &model.ProviderSetting{}
+	require.NoError(t, db.First(setting, "type = ? AND name = ?", movieProviderType, onepondo.Name).Error)
+	assert.Nil(t, setting.Enabled)
+	assert.Empty(t, setting.Config.Data())
+
+	// failed save leaves the runtime state unchanged.
+	require.NoError(t, db.Migrator().DropTable(&model.ProviderSetting{}))
+	_, err = e.UpdateProvider(movieProviderType, onepondo.Name, &ProviderUpdate{Enabled: &disabled})
+	assert.Error(t, err)
+	assert.True(t, e.IsMovieProvider(onepondo.Name))
+}
+
+func TestEngine_UpdateProviderCredentials(t *testing.T) {
+	e := New(openTestDB(t))
+
+	// a password alone is rejected.
+	_, err := e.UpdateProvider(movieProviderType, mgstage.Name, &ProviderUpdate{
+		Config: map[string]string{"password": "secret"},
+	})
+	assert.Error(t, err)
+
+	// credentials are applied, but the values are never exposed.
+	capability, err := e.UpdateProvider(movieProviderType, mgstage.Name, &ProviderUpdate{
+		Config: map[string]string{"username": "alice", "password": "secret"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"password", "username"}, capability.ConfigKeys)
+	data, err := json.Marshal(capability)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "secret")
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestEngine_UpdateProviderDuringSearches(t *testing.T) {
+	// answer all requests locally, the process-wide wrapper
+	// requires the test not to run in parallel.
+	fetch.SetTransportWrapper(func(http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusNotFound,
+				Header:     make(http.Header),
+				Body:       http.NoBody,
+				Request:    req,
+			}, nil
+		})
+	})
+	t.Cleanup(func() { fetch.SetTransportWrapper(nil) })
+
+	e := New(openTestDB(t))
+	_, err := e.UpdateProvider(movieProviderType, theporndb.SceneProviderName, &ProviderUpdate{
+		Config: map[string]string{"access_token": "token"},
+	})
+	require.NoError(t, err)
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for _, name := range []string{onepondo.Name, theporndb.SceneProviderName} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					_, _ = e.MustGetMovieProviderByName(name).GetMovieInfoByID("123456_789")
+				}
+			}
+		}()
+	}
+	for i := range 20 {
+		timeout := time.Duration(i+1) * time.Second
+		for _, name := range []string{onepondo.Name, theporndb.SceneProviderName} {
+			_, err = e.UpdateProvider(movieProviderType, name, &ProviderUpdate{
+				Timeout: &timeout,
+				Config:  map[string]string{"access_token": strings.Repeat("t", i+1)},
+			})
+			require.NoError(t, err)
+		}
+	}
+	close(stop)
+	wg.Wait()
+	assert.Equal(t, 20*time.Second, e.MustGetMovieProviderByName(onepondo.Name).(interface {
+		RequestTimeout() time.Duration
+	}).RequestTimeout())
+}
@@ -0,0 +1,23 @@
+package engine
+
+import (
+	"github.com/metatube-community/metatube-sdk-go/engine/providerid"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+// GetMoviePreviewVideoURL returns the preview video url of the movie,
+// the MP4 one is preferred unless hls is set or there is no MP4 one.
+func (e *Engine) GetMoviePreviewVideoURL(pid providerid.ProviderID, hls bool) (string, error) {
+	info, err := e.GetMovieInfoByProviderID(pid, true)
+	if err != nil {
+		return "", err
+	}
+	url := info.PreviewVideoURL
+	if hls && info.PreviewVideoHLSURL != "" || url == "" {
+		url = info.PreviewVideoHLSURL
+	}
+	if url == "" {
+		return "", mt.ErrVideoNotFound
+	}
+	return url, nil
+}
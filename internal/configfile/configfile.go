@@ -0,0 +1,237 @@
+package configfile
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config is the structured config file, all fields are optional.
+type Config struct {
+	Server      ServerConfig      `yaml:"server" toml:"server"`
+	Database    DatabaseConfig    `yaml:"database" toml:"database"`
+	Engine      EngineConfig      `yaml:"engine" toml:"engine"`
+	Translation TranslationConfig `yaml:"translation" toml:"translation"`
+	Providers   ProvidersConfig   `yaml:"providers" toml:"providers"`
+}
+
+type ServerConfig struct {
+	Bind  string `yaml:"bind,omitempty" toml:"bind,omitempty"`
+	Port  string `yaml:"port,omitempty" toml:"port,omitempty"`
+	Token string `yaml:"token,omitempty" toml:"token,omitempty"`
+}
+
+type DatabaseConfig struct {
+	DSN          string `yaml:"dsn,omitempty" toml:"dsn,omitempty"`
+	MaxIdleConns int    `yaml:"max_idle_conns,omitempty" toml:"max_idle_conns,omitempty"`
+	MaxOpenConns int    `yaml:"max_open_conns,omitempty" toml:"max_open_conns,omitempty"`
+	AutoMigrate  bool   `yaml:"auto_migrate,omitempty" toml:"auto_migrate,omitempty"`
+	PreparedStmt bool   `yaml:"prepared_stmt,omitempty" toml:"prepared_stmt,omitempty"`
+}
+
+type EngineConfig struct {
+	RequestTimeout Duration        `yaml:"request_timeout,omitempty" toml:"request_timeout,omitempty"`
+	FeedInterval   Duration        `yaml:"feed_interval,omitempty" toml:"feed_interval,omitempty"`
+	Freshness      FreshnessConfig `yaml:"freshness,omitempty" toml:"freshness,omitempty"`
+	Jobs           JobsConfig      `yaml:"jobs,omitempty" toml:"jobs,omitempty"`
+}
+
+// JobsConfig is the config of the job queue.
+type JobsConfig struct {
+	Workers int `yaml:"workers,omitempty" toml:"workers,omitempty"`
+	// NumberStatusSchedule is the cron schedule of the number status job.
+	NumberStatusSchedule string `yaml:"number_status_schedule,omitempty" toml:"number_status_schedule,omitempty"`
+}
+
+// FreshnessConfig holds the max ages of cached metadata, the policies
+// of providers fall back to the default one for the unset fields.
+type FreshnessConfig struct {
+	Default   FreshnessPolicy            `yaml:"default,omitempty" toml:"default,omitempty"`
+	Providers map[string]FreshnessPolicy `yaml:"providers,omitempty" toml:"providers,omitempty"`
+}
+
+type FreshnessPolicy struct {
+	MovieUpcoming     Duration `yaml:"movie_upcoming,omitempty" toml:"movie_upcoming,omitempty"`
+	MovieRecent       Duration `yaml:"movie_recent,omitempty" toml:"movie_recent,omitempty"`
+	MovieOld          Duration `yaml:"movie_old,omitempty" toml:"movie_old,omitempty"`
+	MovieRecentPeriod Duration `yaml:"movie_recent_period,omitempty" toml:"movie_recent_period,omitempty"`
+	Actor             Duration `yaml:"actor,omitempty" toml:"actor,omitempty"`
+}
+
+type TranslationConfig struct {
+	// Engine is the translator name, e.g. openaigen.
+	Engine string `yaml:"engine,omitempty" toml:"engine,omitempty"`
+	// Options are decoded into the translator by its JSON tags.
+	Options map[string]string `yaml:"options,omitempty" toml:"options,omitempty"`
+}
+
+// ProviderConfig is the config of a single provider, e.g. priority,
+// timeout and proxy. Values can be any scalar and are used as strings.
+type ProviderConfig map[string]any
+
+// ProvidersConfig holds provider configs by name, the common
+// ones apply to both actor and movie providers of the same name.
+type ProvidersConfig struct {
+	Common map[string]ProviderConfig `yaml:"common,omitempty" toml:"common,omitempty"`
+	Actor  map[string]ProviderConfig `yaml:"actor,omitempty" toml:"actor,omitempty"`
+	Movie  map[string]ProviderConfig `yaml:"movie,omitempty" toml:"movie,omitempty"`
+}
+
+// Duration is a time.Duration in the text form, e.g. "1m30s".
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Strings returns the config values in string form.
+func (c ProviderConfig) Strings() map[string]string {
+	m := make(map[string]string, len(c))
+	for k, v := range c {
+		m[k] = fmt.Sprint(v)
+	}
+	return m
+}
+
+// ActorProviderConfigs returns the actor provider configs
+// in string form, with the common configs merged.
+func (c *Config) ActorProviderConfigs() map[string]map[string]string {
+	return mergeProviderConfigs(c.Providers.Actor, c.Providers.Common)
+}
+
+// MovieProviderConfigs returns the movie provider configs
+// in string form, with the common configs merged.
+func (c *Config) MovieProviderConfigs() map[string]map[string]string {
+	return mergeProviderConfigs(c.Providers.Movie, c.Providers.Common)
+}
+
+func mergeProviderConfigs(primary, fallback map[string]ProviderConfig) map[string]map[string]string {
+	merged := make(map[string]map[string]string)
+	for _, configs := range []map[string]ProviderConfig{fallback, primary} {
+		for name, config := range configs {
+			name = strings.ToUpper(name)
+			if merged[name] == nil {
+				merged[name] = make(map[string]string)
+			}
+			for k, v := range config.Strings() {
+				merged[name][strings.ToLower(k)] = v
+			}
+		}
+	}
+	return merged
+}
+
+// Flags returns the non-zero values of the config by flag names.
+func (c *Config) Flags() map[string]string {
+	flags := make(map[string]string)
+	set := func(name, value string, isZero bool) {
+		if !isZero {
+			flags[name] = value
+		}
+	}
+	set("bind", c.Server.Bind, c.Server.Bind == "")
+	set("port", c.Server.Port, c.Server.Port == "")
+	set("token", c.Server.Token, c.Server.Token == "")
+	set("dsn", c.Database.DSN, c.Database.DSN == "")
+	set("request-timeout", time.Duration(c.Engine.RequestTimeout).String(), c.Engine.RequestTimeout == 0)
+	set("feed-interval", time.Duration(c.Engine.FeedInterval).String(), c.Engine.FeedInterval == 0)
+	set("job-workers", strconv.Itoa(c.Engine.Jobs.Workers), c.Engine.Jobs.Workers == 0)
+	set("number-status-schedule", c.Engine.Jobs.NumberStatusSchedule, c.Engine.Jobs.NumberStatusSchedule == "")
+	set("db-max-idle-conns", strconv.Itoa(c.Database.MaxIdleConns), c.Database.MaxIdleConns == 0)
+	set("db-max-open-conns", strconv.Itoa(c.Database.MaxOpenConns), c.Database.MaxOpenConns == 0)
+	set("db-auto-migrate", strconv.FormatBool(c.Database.AutoMigrate), !c.Database.AutoMigrate)
+	set("db-prepared-stmt", strconv.FormatBool(c.Database.PreparedStmt), !c.Database.PreparedStmt)
+	return flags
+}
+
+// Validate checks the config and returns all errors found.
+func (c *Config) Validate() error {
+	var errs []error
+	if c.Server.Port != "" {
+		if port, err := strconv.Atoi(c.Server.Port); err != nil || port <= 0 || port > 65535 {
+			errs = append(errs, fmt.Errorf("server.port: invalid port: %s", c.Server.Port))
+		}
+	}
+	if c.Server.Bind != "" && net.ParseIP(c.Server.Bind) == nil && strings.ContainsAny(c.Server.Bind, ":/ ") {
+		errs = append(errs, fmt.Errorf("server.bind: invalid address: %s", c.Server.Bind))
+	}
+	if c.Database.MaxIdleConns < 0 {
+		errs = append(errs, errors.New("database.max_idle_conns: must not be negative"))
+	}
+	if c.Database.MaxOpenConns < 0 {
+		errs = append(errs, errors.New("database.max_open_conns: must not be negative"))
+	}
+	if v := time.Duration(c.Engine.RequestTimeout); v != 0 && v < time.Second {
+		errs = append(errs, errors.New("engine.request_timeout: must be at least 1s"))
+	}
+	if c.Engine.FeedInterval < 0 {
+		errs = append(errs, errors.New("engine.feed_interval: must not be negative"))
+	}
+	if c.Engine.Jobs.Workers < 0 {
+		errs = append(errs, errors.New("engine.jobs.workers: must not be negative"))
+	}
+	for name, policy := range c.Engine.Freshness.Providers {
+		if err := policy.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("engine.freshness.providers.%s.%w", name, err))
+		}
+	}
+	if err := c.Engine.Freshness.Default.validate(); err != nil {
+		errs = append(errs, fmt.Errorf("engine.freshness.default.%w", err))
+	}
+	if len(c.Translation.Options) > 0 && c.Translation.Engine == "" {
+		errs = append(errs, errors.New("translation.engine: required when options are set"))
+	}
+	for section, configs := range map[string]map[string]ProviderConfig{
+		"common": c.Providers.Common,
+		"actor":  c.Providers.Actor,
+		"movie":  c.Providers.Movie,
+	} {
+		for name, config := range configs {
+			for key, value := range config.Strings() {
+				if err := validateProviderValue(strings.ToLower(key), value); err != nil {
+					errs = append(errs, fmt.Errorf("providers.%s.%s.%s: %w", section, name, key, err))
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (p FreshnessPolicy) validate() error {
+	for name, v := range map[string]Duration{
+		"movie_upcoming":      p.MovieUpcoming,
+		"movie_recent":        p.MovieRecent,
+		"movie_old":           p.MovieOld,
+		"movie_recent_period": p.MovieRecentPeriod,
+		"actor":               p.Actor,
+	} {
+		if v < 0 {
+			return fmt.Errorf("%s: must not be negative", name)
+		}
+	}
+	return nil
+}
+
+func validateProviderValue(key, value string) (err error) {
+	switch key {
+	case "priority", "rate_limit":
+		_, err = strconv.ParseFloat(value, 64)
+	case "rate_burst", "max_concurrency":
+		_, err = strconv.ParseInt(value, 10, 64)
+	case "timeout", "proxy_check_interval":
+		_, err = time.ParseDuration(value)
+	}
+	return
+}
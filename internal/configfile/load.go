@@ -0,0 +1,85 @@
+package configfile
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	FormatYAML = "yaml"
+	FormatTOML = "toml"
+)
+
+// FormatOf returns the config format by file extension.
+func FormatOf(path string) (string, error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	case ".toml":
+		return FormatTOML, nil
+	default:
+		return "", fmt.Errorf("unsupported config file format: %q", ext)
+	}
+}
+
+// Load reads, decodes and validates the config file,
+// unknown fields are treated as errors.
+func Load(path string) (*Config, error) {
+	format, err := FormatOf(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c, err := Parse(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("load config file %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Parse decodes and validates the config data in the given format.
+func Parse(data []byte, format string) (*Config, error) {
+	c := &Config{}
+	switch format {
+	case FormatYAML:
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		// an empty document is a valid config.
+		if err := dec.Decode(c); err != nil && len(bytes.TrimSpace(data)) > 0 {
+			return nil, err
+		}
+	case FormatTOML:
+		dec := toml.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(c); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported config format: %s", format)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Marshal encodes the config in the given format.
+func (c *Config) Marshal(format string) ([]byte, error) {
+	switch format {
+	case FormatYAML:
+		return yaml.Marshal(c)
+	case FormatTOML:
+		return toml.Marshal(c)
+	default:
+		return nil, fmt.Errorf("unsupported config format: %s", format)
+	}
+}
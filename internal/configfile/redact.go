@@ -0,0 +1,83 @@
+package configfile
+
+import (
+	"maps"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const redacted = "xxxxx"
+
+// secretKeyRegexp matches config keys which hold secrets.
+var secretKeyRegexp = regexp.MustCompile(`(?i)(password|passwd|token|secret|auth|cookie|api[-_]?key|access[-_]?key)`)
+
+// dsnPasswordRegexp matches the password in key/value DSNs, e.g. "password=xxx".
+var dsnPasswordRegexp = regexp.MustCompile(`(?i)(password=)('[^']*'|\S+)`)
+
+// Redacted returns a copy of the config with secrets redacted.
+func (c *Config) Redacted() *Config {
+	r := *c
+	if r.Server.Token != "" {
+		r.Server.Token = redacted
+	}
+	r.Database.DSN = redactDSN(r.Database.DSN)
+	if r.Translation.Options != nil {
+		r.Translation.Options = maps.Clone(r.Translation.Options)
+		for k := range r.Translation.Options {
+			if secretKeyRegexp.MatchString(k) {
+				r.Translation.Options[k] = redacted
+			}
+		}
+	}
+	r.Providers = ProvidersConfig{
+		Common: redactProviderConfigs(c.Providers.Common),
+		Actor:  redactProviderConfigs(c.Providers.Actor),
+		Movie:  redactProviderConfigs(c.Providers.Movie),
+	}
+	return &r
+}
+
+func redactProviderConfigs(configs map[string]ProviderConfig) map[string]ProviderConfig {
+	if configs == nil {
+		return nil
+	}
+	r := make(map[string]ProviderConfig, len(configs))
+	for name, config := range configs {
+		rc := make(ProviderConfig, len(config))
+		for k, v := range config {
+			switch {
+			case secretKeyRegexp.MatchString(k):
+				rc[k] = redacted
+			case strings.EqualFold(k, "proxy"):
+				rc[k] = redactURLs(v)
+			default:
+				rc[k] = v
+			}
+		}
+		r[name] = rc
+	}
+	return r
+}
+
+// redactURLs redacts the credentials of comma or space separated URLs.
+func redactURLs(v any) any {
+	s, ok := v.(string)
+	if !ok {
+		return v
+	}
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' })
+	for i, field := range fields {
+		if u, err := url.Parse(field); err == nil && u.User != nil {
+			fields[i] = u.Redacted()
+		}
+	}
+	return strings.Join(fields, ",")
+}
+
+func redactDSN(dsn string) string {
+	if u, err := url.Parse(dsn); err == nil && u.User != nil {
+		return u.Redacted()
+	}
+	return dsnPasswordRegexp.ReplaceAllString(dsn, "${1}"+redacted)
+}
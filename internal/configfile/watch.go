@@ -0,0 +1,34 @@
+package configfile
+
+import (
+	"os"
+	"time"
+)
+
+// Watch polls the config file every interval, and calls onChange with the
+// reloaded config, or the error if it fails to load, once the file has been
+// modified. The returned stop func stops watching.
+func Watch(path string, interval time.Duration, onChange func(*Config, error)) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		last, _ := os.Stat(path)
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			fi, err := os.Stat(path)
+			if err != nil || (last != nil &&
+				fi.ModTime().Equal(last.ModTime()) &&
+				fi.Size() == last.Size()) {
+				continue // ignore missing file, e.g. during replacement.
+			}
+			last = fi
+			onChange(Load(path))
+		}
+	}()
+	return func() { close(done) }
+}
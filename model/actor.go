@@ -37,10 +37,11 @@ type ActorInfo struct {
 	Measurements string         `json:"measurements"`
 	Nationality  string         `json:"nationality"`
 	Height       int            `json:"height"`
-	Aliases      pq.StringArray `json:"aliases" gorm:"type:text[]"`
-	Images       pq.StringArray `json:"images" gorm:"type:text[]"`
+	Aliases      pq.StringArray `json:"aliases" gorm:"type:text[];serializer:stringarray"`
+	Images       pq.StringArray `json:"images" gorm:"type:text[];serializer:stringarray"`
 	Birthday     datatypes.Date `json:"birthday"`
 	DebutDate    datatypes.Date `json:"debut_date"`
+	LockedFields []string       `json:"locked_fields,omitempty" gorm:"-"` // fields overridden manually.
 	TimeTracker  `json:"-"`
 }
 
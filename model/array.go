@@ -0,0 +1,73 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm/schema"
+)
+
+func init() {
+	schema.RegisterSerializer("stringarray", stringArraySerializer{})
+}
+
+type jsonArraysKey struct{}
+
+// WithJSONArrays returns a context in which the string array columns
+// are written as JSON arrays instead of text[] literals, as MySQL has
+// no array types.
+func WithJSONArrays(ctx context.Context) context.Context {
+	return context.WithValue(ctx, jsonArraysKey{}, true)
+}
+
+// IsJSONArrays reports whether the context is from WithJSONArrays.
+func IsJSONArrays(ctx context.Context) bool {
+	v, _ := ctx.Value(jsonArraysKey{}).(bool)
+	return v
+}
+
+// stringArraySerializer stores pq.StringArray fields as text[] arrays in
+// Postgres and SQLite as before, and as JSON arrays in MySQL. Either of
+// them is scanned regardless of the DB.
+type stringArraySerializer struct{}
+
+func (stringArraySerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue any) error {
+	var (
+		a    pq.StringArray
+		data []byte
+	)
+	switch v := dbValue.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	}
+	if len(data) > 0 && data[0] == '[' {
+		if err := json.Unmarshal(data, (*[]string)(&a)); err != nil {
+			return err
+		}
+	} else if dbValue != nil {
+		if err := a.Scan(dbValue); err != nil {
+			return err
+		}
+	}
+	field.ReflectValueOf(ctx, dst).Set(reflect.ValueOf(a))
+	return nil
+}
+
+func (stringArraySerializer) Value(ctx context.Context, _ *schema.Field, _ reflect.Value, fieldValue any) (any, error) {
+	a, _ := fieldValue.(pq.StringArray)
+	if !IsJSONArrays(ctx) {
+		return a.Value()
+	}
+	if a == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal([]string(a))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm/schema"
+)
+
+func TestStringArraySerializer(t *testing.T) {
+	s, err := schema.Parse(&MovieInfo{}, &sync.Map{}, schema.NamingStrategy{})
+	require.NoError(t, err)
+	field := s.LookUpField("actors")
+	require.NotNil(t, field)
+	ctx := context.Background()
+
+	for _, unit := range []struct {
+		ctx  context.Context
+		in   pq.StringArray
+		want any
+	}{
+		{ctx, pq.StringArray{"A", "B C"}, `{"A","B C"}`},
+		{ctx, nil, nil},
+		{WithJSONArrays(ctx), pq.StringArray{"A", "B C"}, `["A","B C"]`},
+		{WithJSONArrays(ctx), pq.StringArray{}, `[]`},
+		{WithJSONArrays(ctx), nil, nil},
+	} {
+		got, err := stringArraySerializer{}.Value(unit.ctx, field, reflect.Value{}, unit.in)
+		require.NoError(t, err)
+		assert.Equal(t, unit.want, got, unit.in)
+	}
+
+	for _, unit := range []struct {
+		in   any
+		want pq.StringArray
+	}{
+		{`{"A","B C"}`, pq.StringArray{"A", "B C"}},
+		{[]byte(`["A","B C"]`), pq.StringArray{"A", "B C"}},
+		{`[]`, pq.StringArray{}},
+		{nil, nil},
+	} {
+		info := &MovieInfo{Actors: pq.StringArray{"old"}}
+		require.NoError(t, stringArraySerializer{}.Scan(ctx, field, reflect.ValueOf(info), unit.in))
+		assert.Equal(t, unit.want, info.Actors, unit.in)
+	}
+}
@@ -0,0 +1,28 @@
+package model
+
+const (
+	MakersTableName = "makers"
+	SeriesTableName = "series"
+)
+
+// Maker is a movie maker (studio) collected from the movie metadata.
+type Maker struct {
+	Name        string `json:"name" gorm:"primaryKey"`
+	TimeTracker `json:"-"`
+}
+
+func (*Maker) TableName() string {
+	return MakersTableName
+}
+
+// Series is a movie series collected from the movie metadata, the
+// maker is part of the key, as series names may collide across makers.
+type Series struct {
+	Name        string `json:"name" gorm:"primaryKey"`
+	Maker       string `json:"maker" gorm:"primaryKey"`
+	TimeTracker `json:"-"`
+}
+
+func (*Series) TableName() string {
+	return SeriesTableName
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"gorm.io/datatypes"
+)
+
+const FeedCheckpointsTableName = "feed_checkpoints"
+
+// FeedCheckpoint records how far the release feed of a provider has
+// been ingested, so that the next run can stop at where it left off.
+type FeedCheckpoint struct {
+	Provider        string         `json:"provider" gorm:"primaryKey"`
+	LastID          string         `json:"last_id"`
+	LastReleaseDate datatypes.Date `json:"last_release_date"`
+	Ingested        int64          `json:"ingested"`
+	// ResumePage is the next page of an interrupted walk, which is
+	// resumed by the next run, or 0 if none. The newest movie of the
+	// walk becomes the last one when the walk is done.
+	ResumePage         int            `json:"resume_page,omitempty"`
+	PendingID          string         `json:"pending_id,omitempty"`
+	PendingReleaseDate datatypes.Date `json:"pending_release_date"`
+	TimeTracker        `json:"-"`
+}
+
+func (*FeedCheckpoint) TableName() string {
+	return FeedCheckpointsTableName
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+const (
+	JobsTableName    = "jobs"
+	JobRunsTableName = "job_runs"
+)
+
+// Job is a named job of a registered type, which runs by its cron
+// schedule or when triggered manually.
+type Job struct {
+	Name string `json:"name" gorm:"primaryKey"`
+	Type string `json:"type"`
+	// Schedule is the cron expression, empty for manual jobs.
+	Schedule    string         `json:"schedule"`
+	Payload     datatypes.JSON `json:"payload,omitempty"`
+	MaxAttempts int            `json:"max_attempts"`
+	Paused      bool           `json:"paused"`
+	NextRunAt   *time.Time     `json:"next_run_at,omitempty"`
+	LastRunAt   *time.Time     `json:"last_run_at,omitempty"`
+	// Version is increased on every schedule change, so that only
+	// one instance can enqueue the run of the same schedule.
+	Version     int64 `json:"-"`
+	TimeTracker `json:"-"`
+}
+
+func (*Job) TableName() string {
+	return JobsTableName
+}
+
+type JobRunStatus string
+
+const (
+	JobRunPending   JobRunStatus = "pending"
+	JobRunRunning   JobRunStatus = "running"
+	JobRunSucceeded JobRunStatus = "succeeded"
+	JobRunFailed    JobRunStatus = "failed"
+	JobRunCanceled  JobRunStatus = "canceled"
+)
+
+// IsFinished reports whether the run will not be run again.
+func (s JobRunStatus) IsFinished() bool {
+	return s == JobRunSucceeded || s == JobRunFailed || s == JobRunCanceled
+}
+
+// JobRun is a queued, running or finished run of a job.
+type JobRun struct {
+	ID          uint64         `json:"id" gorm:"primaryKey"`
+	JobName     string         `json:"job_name"`
+	Type        string         `json:"type"`
+	Payload     datatypes.JSON `json:"payload,omitempty"`
+	Status      JobRunStatus   `json:"status"`
+	Attempts    int            `json:"attempts"`
+	MaxAttempts int            `json:"max_attempts"`
+	// RunAt is when the run is due, i.e. when it's retried.
+	RunAt time.Time `json:"run_at"`
+	// Owner is the instance running the job, which holds the lease
+	// until LeaseUntil, the run is retried if the lease expires.
+	Owner      string     `json:"owner,omitempty"`
+	LeaseUntil *time.Time `json:"lease_until,omitempty"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+func (*JobRun) TableName() string {
+	return JobRunsTableName
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+const (
+	MatchJobsTableName     = "match_jobs"
+	MatchJobItemsTableName = "match_job_items"
+)
+
+// MatchJob is the persisted progress of a batch match job, the
+// completed items are saved as MatchJobItem when the job is finished.
+type MatchJob struct {
+	ID          string     `json:"id" gorm:"primaryKey"`
+	Status      string     `json:"status"`
+	Total       int        `json:"total"`
+	Unique      int        `json:"unique" gorm:"column:unique_count"`
+	Completed   int        `json:"completed"`
+	Matched     int        `json:"matched"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	TimeTracker `json:"-"`
+}
+
+func (*MatchJob) TableName() string {
+	return MatchJobsTableName
+}
+
+// MatchJobItem is a completed item of a finished match job, a row
+// per item, so that large jobs are saved and read by pages.
+type MatchJobItem struct {
+	JobID string `json:"-" gorm:"primaryKey"`
+	// Seq is the index of the item in the input order.
+	Seq    int            `json:"-" gorm:"primaryKey"`
+	Input  string         `json:"input"`
+	Result datatypes.JSON `json:"result,omitempty"`
+	Error  string         `json:"error,omitempty"`
+}
+
+func (*MatchJobItem) TableName() string {
+	return MatchJobItemsTableName
+}
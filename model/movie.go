@@ -77,7 +77,7 @@ type MovieInfo struct {
 	Homepage string `json:"homepage"`
 
 	Director string         `json:"director"`
-	Actors   pq.StringArray `json:"actors" gorm:"type:text[]"`
+	Actors   pq.StringArray `json:"actors" gorm:"type:text[];serializer:stringarray"`
 
 	ThumbURL           string         `json:"thumb_url"`
 	BigThumbURL        string         `json:"big_thumb_url"`
@@ -85,17 +85,20 @@ type MovieInfo struct {
 	BigCoverURL        string         `json:"big_cover_url"`
 	PreviewVideoURL    string         `json:"preview_video_url"`
 	PreviewVideoHLSURL string         `json:"preview_video_hls_url"`
-	PreviewImages      pq.StringArray `json:"preview_images" gorm:"type:text[]"`
+	PreviewImages      pq.StringArray `json:"preview_images" gorm:"type:text[];serializer:stringarray"`
 
 	Maker  string         `json:"maker"`
 	Label  string         `json:"label"`
 	Series string         `json:"series"`
-	Genres pq.StringArray `json:"genres" gorm:"type:text[]"`
+	Genres pq.StringArray `json:"genres" gorm:"type:text[];serializer:stringarray"`
 	Score  float64        `json:"score"`
 
 	Runtime     int            `json:"runtime"`
 	ReleaseDate datatypes.Date `json:"release_date"`
 
+	// LockedFields are the fields overridden manually.
+	LockedFields []string `json:"locked_fields,omitempty" gorm:"-"`
+
 	TimeTracker `json:"-"`
 }
 
@@ -126,14 +129,3 @@ func (m *MovieInfo) ToSearchResult() *MovieSearchResult {
 		ReleaseDate: m.ReleaseDate,
 	}
 }
-
-// NumberStatus 表示number_prefix的状态
-type NumberStatus struct {
-	NumberPrefix string `json:"number_prefix" gorm:"primaryKey"`
-	Status       int    `json:"status" gorm:"index"`
-	TimeTracker  `json:"-"`
-}
-
-func (n *NumberStatus) TableName() string {
-	return "number_status"
-}
@@ -0,0 +1,76 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+const NumberStatusTableName = "number_status"
+
+// Status of the number prefixes.
+const (
+	// NumberStatusActive prefixes are crawled by the number status job.
+	NumberStatusActive = iota
+	// NumberStatusDone prefixes have the last number in range found.
+	NumberStatusDone
+	// NumberStatusPaused prefixes are skipped until resumed.
+	NumberStatusPaused
+)
+
+// MaxNumberDigits is the max width of the number suffixes.
+const MaxNumberDigits = 6
+
+var numberPrefixRe = regexp.MustCompile(`^[A-Z0-9]+$`)
+
+// NumberStatus is a number prefix tracked by the crawler, e.g. ABP, with
+// the range of its numbers and the crawl progress.
+type NumberStatus struct {
+	NumberPrefix string `json:"number_prefix" gorm:"primaryKey"`
+	Status       int    `json:"status" gorm:"index"`
+	// Digits is the zero-padded width of the suffixes, e.g. 3 for ABP-001.
+	Digits    int `json:"digits"`
+	MinSuffix int `json:"min_suffix"`
+	MaxSuffix int `json:"max_suffix"`
+	// MaxMisses is the number of consecutive misses after the last found
+	// number before a run stops, zero means no limit.
+	MaxMisses int `json:"max_misses"`
+	// LastFound is the largest suffix found, where the next run starts.
+	LastFound int `json:"last_found"`
+	// Found is the number of movies found by the crawler.
+	Found int64 `json:"found"`
+	// Gaps are the missing suffixes below LastFound which were tried
+	// and not found, they are not tried again until reset.
+	Gaps        datatypes.JSONType[[]int] `json:"gaps"`
+	LastRunAt   *time.Time                `json:"last_run_at,omitempty"`
+	LastError   string                    `json:"last_error,omitempty"`
+	TimeTracker `json:"-"`
+}
+
+func (*NumberStatus) TableName() string {
+	return NumberStatusTableName
+}
+
+// Number returns the number of the suffix, e.g. ABP-001.
+func (n *NumberStatus) Number(suffix int) string {
+	return fmt.Sprintf("%s-%0*d", n.NumberPrefix, n.Digits, suffix)
+}
+
+func (n *NumberStatus) IsValid() bool {
+	return numberPrefixRe.MatchString(n.NumberPrefix) &&
+		n.Digits > 0 && n.Digits <= MaxNumberDigits &&
+		n.MinSuffix >= 0 && n.MinSuffix <= n.MaxSuffix &&
+		n.MaxSuffix <= MaxNumberSuffix(n.Digits) &&
+		n.MaxMisses >= 0
+}
+
+// MaxNumberSuffix returns the largest suffix of the width, e.g. 999 for 3.
+func MaxNumberSuffix(digits int) int {
+	v := 1
+	for range digits {
+		v *= 10
+	}
+	return v - 1
+}
@@ -0,0 +1,99 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+
+	"gorm.io/datatypes"
+)
+
+const (
+	MovieOverridesTableName = "movie_overrides"
+	ActorOverridesTableName = "actor_overrides"
+)
+
+// OverrideFields are the JSON values of the overridden fields by
+// their JSON names, e.g. {"title": "..."}.
+type OverrideFields = map[string]json.RawMessage
+
+// reservedFields can't be overridden, as they identify the record.
+var reservedFields = []string{"id", "provider", "locked_fields"}
+
+// MovieOverride is the manual override of a movie info, kept apart
+// from the metadata so that it's never replaced by scrapes.
+type MovieOverride struct {
+	ID          string                             `json:"id" gorm:"primaryKey"`
+	Provider    string                             `json:"provider" gorm:"primaryKey"`
+	Fields      datatypes.JSONType[OverrideFields] `json:"fields"`
+	TimeTracker `json:"-"`
+}
+
+func (*MovieOverride) TableName() string {
+	return MovieOverridesTableName
+}
+
+func (o *MovieOverride) IsValid() bool {
+	return o.ID != "" && o.Provider != "" && len(o.Fields.Data()) > 0
+}
+
+// Apply sets the overridden fields of the info and locks them.
+func (o *MovieOverride) Apply(info *MovieInfo) error {
+	if err := applyFields(info, o.Fields.Data()); err != nil {
+		return err
+	}
+	info.LockedFields = slices.Sorted(maps.Keys(o.Fields.Data()))
+	return nil
+}
+
+// ActorOverride is the manual override of an actor info.
+type ActorOverride struct {
+	ID          string                             `json:"id" gorm:"primaryKey"`
+	Provider    string                             `json:"provider" gorm:"primaryKey"`
+	Fields      datatypes.JSONType[OverrideFields] `json:"fields"`
+	TimeTracker `json:"-"`
+}
+
+func (*ActorOverride) TableName() string {
+	return ActorOverridesTableName
+}
+
+func (o *ActorOverride) IsValid() bool {
+	return o.ID != "" && o.Provider != "" && len(o.Fields.Data()) > 0
+}
+
+// Apply sets the overridden fields of the info and locks them.
+func (o *ActorOverride) Apply(info *ActorInfo) error {
+	if err := applyFields(info, o.Fields.Data()); err != nil {
+		return err
+	}
+	info.LockedFields = slices.Sorted(maps.Keys(o.Fields.Data()))
+	return nil
+}
+
+// applyFields sets the fields of v by their JSON names, unknown
+// or reserved fields and mistyped values are rejected.
+func applyFields(v any, fields OverrideFields) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	merged := make(map[string]json.RawMessage)
+	if err = json.Unmarshal(data, &merged); err != nil {
+		return err
+	}
+	for key, value := range fields {
+		if slices.Contains(reservedFields, key) {
+			return fmt.Errorf("field cannot be overridden: %s", key)
+		}
+		merged[key] = value
+	}
+	if data, err = json.Marshal(merged); err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+func TestMovieOverride_Apply(t *testing.T) {
+	for _, unit := range []struct {
+		fields OverrideFields
+		title  string
+		actors pq.StringArray
+		locked []string
+		hasErr bool
+	}{
+		{OverrideFields{"title": json.RawMessage(`"Fixed"`)}, "Fixed", pq.StringArray{"A"}, []string{"title"}, false},
+		{OverrideFields{"actors": json.RawMessage(`["B","C"]`), "title": json.RawMessage(`"Fixed"`)}, "Fixed", pq.StringArray{"B", "C"}, []string{"actors", "title"}, false},
+		{OverrideFields{"unknown": json.RawMessage(`1`)}, "", nil, nil, true},
+		{OverrideFields{"id": json.RawMessage(`"other"`)}, "", nil, nil, true},
+		{OverrideFields{"runtime": json.RawMessage(`"long"`)}, "", nil, nil, true},
+	} {
+		info := &MovieInfo{
+			ID:       "abp-001",
+			Provider: "FANZA",
+			Title:    "Garbage",
+			Actors:   pq.StringArray{"A"},
+		}
+		override := &MovieOverride{
+			ID:       info.ID,
+			Provider: info.Provider,
+			Fields:   datatypes.NewJSONType(unit.fields),
+		}
+		err := override.Apply(info)
+		if unit.hasErr {
+			assert.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		assert.Equal(t, unit.title, info.Title)
+		assert.Equal(t, unit.actors, info.Actors)
+		assert.Equal(t, unit.locked, info.LockedFields)
+		assert.Equal(t, "abp-001", info.ID)
+	}
+}
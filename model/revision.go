@@ -0,0 +1,53 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+const MetadataRevisionsTableName = "metadata_revisions"
+
+// RevisionKind is the kind of metadata a revision belongs to.
+type RevisionKind string
+
+const (
+	MovieRevision RevisionKind = "movie"
+	ActorRevision RevisionKind = "actor"
+)
+
+// RevisionSource is what caused a metadata change.
+type RevisionSource string
+
+const (
+	RevisionSourceScrape   RevisionSource = "scrape"
+	RevisionSourceRollback RevisionSource = "rollback"
+	RevisionSourceImport   RevisionSource = "import"
+	// RevisionSourceBaseline is the data saved before it was tracked.
+	RevisionSourceBaseline RevisionSource = "baseline"
+)
+
+// FieldChange is the old and new JSON values of a changed field,
+// the old value is absent for newly created records.
+type FieldChange struct {
+	Old json.RawMessage `json:"old,omitempty"`
+	New json.RawMessage `json:"new"`
+}
+
+// MetadataRevision is a change of movie or actor metadata, with the
+// field-level changes and the full data after the change.
+type MetadataRevision struct {
+	ID        uint64                                      `json:"id" gorm:"primaryKey"`
+	Kind      RevisionKind                                `json:"kind"`
+	Provider  string                                      `json:"provider"`
+	RecordID  string                                      `json:"record_id"`
+	Source    RevisionSource                              `json:"source"`
+	Changes   datatypes.JSONType[map[string]*FieldChange] `json:"changes"`
+	Data      datatypes.JSON                              `json:"data"`
+	CreatedAt time.Time                                   `json:"created_at"`
+}
+
+func (*MetadataRevision) TableName() string {
+	return MetadataRevisionsTableName
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"net/http"
+
+	"gorm.io/datatypes"
+)
+
+const ProviderSessionsTableName = "provider_sessions"
+
+// ProviderSession stores persistent session cookies of a provider.
+type ProviderSession struct {
+	Provider    string                               `json:"provider" gorm:"primaryKey"`
+	Cookies     datatypes.JSONType[[]*SessionCookie] `json:"cookies"`
+	TimeTracker `json:"-"`
+}
+
+func (*ProviderSession) TableName() string {
+	return ProviderSessionsTableName
+}
+
+type SessionCookie struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func NewSessionCookies(cookies []*http.Cookie) []*SessionCookie {
+	sc := make([]*SessionCookie, 0, len(cookies))
+	for _, cookie := range cookies {
+		sc = append(sc, &SessionCookie{Name: cookie.Name, Value: cookie.Value})
+	}
+	return sc
+}
+
+func (s *ProviderSession) HTTPCookies() []*http.Cookie {
+	cookies := make([]*http.Cookie, 0, len(s.Cookies.Data()))
+	for _, cookie := range s.Cookies.Data() {
+		cookies = append(cookies, &http.Cookie{Name: cookie.Name, Value: cookie.Value})
+	}
+	return cookies
+}
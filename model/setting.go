@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+const ProviderSettingsTableName = "provider_settings"
+
+// ProviderSetting stores runtime overrides of a provider,
+// nil fields mean not overridden.
+type ProviderSetting struct {
+	Type        string                                `json:"type" gorm:"primaryKey"`
+	Name        string                                `json:"name" gorm:"primaryKey"`
+	Enabled     *bool                                 `json:"enabled,omitempty"`
+	Priority    *float64                              `json:"priority,omitempty"`
+	Timeout     *time.Duration                        `json:"timeout,omitempty"`
+	Config      datatypes.JSONType[map[string]string] `json:"config"`
+	TimeTracker `json:"-"`
+}
+
+func (*ProviderSetting) TableName() string {
+	return ProviderSettingsTableName
+}
@@ -10,6 +10,7 @@ import (
 var (
 	_ provider.MovieProvider = (*TenMusume)(nil)
 	_ provider.MovieReviewer = (*TenMusume)(nil)
+	_ provider.ReleaseFeeder = (*TenMusume)(nil)
 )
 
 const (
@@ -22,3 +22,10 @@ func TestOnePondo_GetMovieReviewsByID(t *testing.T) {
 		"071912_387",
 	})
 }
+
+func TestOnePondo_GetReleaseFeed(t *testing.T) {
+	testkit.Test(t, New, []string{
+		"1",
+		"2",
+	})
+}
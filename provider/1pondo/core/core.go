@@ -14,7 +14,9 @@ import (
 	"github.com/nlnwa/whatwg-url/url"
 	"golang.org/x/text/language"
 
+	"github.com/metatube-community/metatube-sdk-go/common/fetch"
 	"github.com/metatube-community/metatube-sdk-go/common/parser"
+	"github.com/metatube-community/metatube-sdk-go/common/ratelimit"
 	"github.com/metatube-community/metatube-sdk-go/model"
 	"github.com/metatube-community/metatube-sdk-go/provider/internal/scraper"
 )
@@ -25,8 +27,12 @@ const (
 	movieReviewPath        = "/dyn/phpauto/new_movie_reviews/movie_id/%s.json"
 	movieGalleryPath       = "/dyn/dla/json/movie_gallery/%s.json"
 	movieLegacyGalleryPath = "/dyn/phpauto/movie_galleries/movie_id/%s.json"
+	movieListPath          = "/dyn/phpauto/movie_lists/list_newest_%d.json"
 )
 
+// movieListPageSize is the number of movies per list page.
+const movieListPageSize = 50
+
 type Core struct {
 	*scraper.Scraper
 
@@ -43,11 +49,13 @@ type Core struct {
 	// Paths
 	GalleryPath       string
 	LegacyGalleryPath string
+
+	// Fetcher for resources, e.g. images and preview videos.
+	fetcher *fetch.Fetcher
 }
 
 func (core *Core) Init() *Core {
 	t := http.DefaultTransport.(*http.Transport).Clone()
-	t.MaxConnsPerHost = 2
 	t.MaxIdleConnsPerHost = 2
 	t.IdleConnTimeout = 5 * time.Minute
 
@@ -62,15 +70,27 @@ func (core *Core) Init() *Core {
 		}),
 		scraper.WithDisableCookies(),
 		scraper.WithTransport(t), // Set custom HTTP transport.
+		scraper.WithRateLimit(ratelimit.Config{Concurrency: 2}),
 	)
+
+	// No whole request timeout, as preview videos are streamed,
+	// the response header is bounded by the transport instead.
+	ft := http.DefaultTransport.(*http.Transport).Clone()
+	ft.ResponseHeaderTimeout = 15 * time.Second
+	core.fetcher = fetch.Default(&fetch.Config{
+		Transport: ft,
+		Proxy:     core.Proxy(),
+	})
 	return core
 }
 
 func (core *Core) Fetch(url string) (resp *http.Response, err error) {
-	return (&http.Client{
-		Transport: http.DefaultTransport.(*http.Transport).Clone(),
-		Timeout:   15 * time.Second,
-	}).Get(url)
+	return core.Get(url, fetch.WithRaiseForStatus(false))
+}
+
+// Get fetches the url with request options, e.g. Range header.
+func (core *Core) Get(url string, opts ...fetch.Option) (resp *http.Response, err error) {
+	return core.fetcher.Get(url, opts...)
 }
 
 func (core *Core) GetMovieReviewsByID(id string) (reviews []*model.MovieReviewDetail, err error) {
@@ -296,6 +316,66 @@ func (core *Core) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err e
 	return
 }
 
+// GetReleaseFeed impls ReleaseFeeder.GetReleaseFeed.
+func (core *Core) GetReleaseFeed(page int) (results []*model.MovieSearchResult, err error) {
+	if page < 1 {
+		page = 1
+	}
+
+	c := core.ClonedCollector()
+
+	c.OnResponse(func(r *colly.Response) {
+		data := struct {
+			Rows []struct {
+				ActressesJa []string
+				AvgRating   float64
+				MovieID     string
+				MovieThumb  string
+				Release     string
+				ThumbHigh   string
+				ThumbMed    string
+				Title       string
+			}
+		}{}
+		if err = json.Unmarshal(r.Body, &data); err == nil {
+			for _, row := range data.Rows {
+				result := &model.MovieSearchResult{
+					ID:          row.MovieID,
+					Number:      row.MovieID,
+					Title:       row.Title,
+					Provider:    core.Name(),
+					Homepage:    fmt.Sprintf(core.MovieURL, row.MovieID),
+					ReleaseDate: parser.ParseDate(row.Release),
+				}
+				if row.AvgRating <= 5 {
+					result.Score = row.AvgRating
+				}
+				for _, thumb := range []string{row.ThumbHigh, row.ThumbMed} {
+					if thumb != "" {
+						result.CoverURL = r.Request.AbsoluteURL(thumb)
+						result.ThumbURL = result.CoverURL /* use thumb as cover */
+						break
+					}
+				}
+				if row.MovieThumb != "" {
+					result.ThumbURL = r.Request.AbsoluteURL(row.MovieThumb)
+				}
+				for _, actor := range row.ActressesJa {
+					if actor := strings.Trim(actor, "-"); actor != "" {
+						result.Actors = append(result.Actors, actor)
+					}
+				}
+				results = append(results, result)
+			}
+		}
+	})
+
+	if vErr := c.Visit(urlJoin(core.BaseURL, fmt.Sprintf(movieListPath, (page-1)*movieListPageSize))); vErr != nil {
+		err = vErr
+	}
+	return
+}
+
 var urlParser = url.NewParser(url.WithPercentEncodeSinglePercentSign())
 
 func urlJoin(url, path string) string {
@@ -28,9 +28,11 @@ import (
 )
 
 var (
-	_ provider.MovieProvider = (*AVBase)(nil)
-	_ provider.MovieSearcher = (*AVBase)(nil)
-	_ provider.Fetcher       = (*AVBase)(nil)
+	_ provider.MovieProvider     = (*AVBase)(nil)
+	_ provider.MovieSearcher     = (*AVBase)(nil)
+	_ provider.ActorSearcher     = (*AVBase)(nil)
+	_ provider.ActorFilmographer = (*AVBase)(nil)
+	_ provider.Fetcher           = (*AVBase)(nil)
 )
 
 const (
@@ -42,7 +44,8 @@ const (
 	baseURL      = "https://www.avbase.net/"
 	movieURL     = "https://www.avbase.net/works/%s"
 	movieAPIURL  = "https://www.avbase.net/_next/data/%s/works/%s.json?id=%s"
-	searchAPIURL = "https://www.avbase.net/_next/data/%s/works.json?q=%s"
+	searchURL    = "https://www.avbase.net/works?q=%s"
+	searchAPIURL = "https://www.avbase.net/_next/data/%s/works.json?q=%s&page=%d"
 )
 
 type AVBase struct {
@@ -53,14 +56,15 @@ type AVBase struct {
 }
 
 func New() *AVBase {
+	s := scraper.NewDefaultScraper(
+		Name, baseURL, Priority, language.Japanese,
+		scraper.WithHeaders(map[string]string{
+			"Referer": baseURL,
+		}))
 	return &AVBase{
-		Fetcher: fetch.Default(&fetch.Config{SkipVerify: true}),
-		Scraper: scraper.NewDefaultScraper(
-			Name, baseURL, Priority, language.Japanese,
-			scraper.WithHeaders(map[string]string{
-				"Referer": baseURL,
-			})),
-		single: singledo.NewSingle(2 * time.Hour),
+		Fetcher: fetch.Default(&fetch.Config{SkipVerify: true, Proxy: s.Proxy()}),
+		Scraper: s,
+		single:  singledo.NewSingle(2 * time.Hour),
 		providers: map[string]provider.MovieProvider{
 			"duga":    duga.New(),
 			"fanza":   fanza.New(),
@@ -248,7 +252,52 @@ func (ab *AVBase) NormalizeMovieKeyword(keyword string) string {
 	return strings.ToUpper(keyword)
 }
 
-func (ab *AVBase) SearchMovie(keyword string) (results []*model.MovieSearchResult, err error) {
+func (ab *AVBase) SearchMovie(keyword string) ([]*model.MovieSearchResult, error) {
+	results, _, err := ab.searchWorks(keyword, 1)
+	return results, err
+}
+
+// GetActorFilmography impls ActorFilmographer.GetActorFilmography,
+// the id is the actor name, as works are searched by cast names.
+func (ab *AVBase) GetActorFilmography(id string, page int) ([]*model.MovieSearchResult, error) {
+	if page < 1 {
+		page = 1
+	}
+	results, _, err := ab.searchWorks(id, page)
+	return results, err
+}
+
+// SearchActor impls ActorSearcher.SearchActor, which is used to
+// look up actor names only, as AVBase is not an actor provider.
+func (ab *AVBase) SearchActor(keyword string) (results []*model.ActorSearchResult, err error) {
+	_, actors, err := ab.searchWorks(keyword, 1)
+	if err != nil {
+		return
+	}
+	seen := make(map[string]struct{})
+	for _, actor := range actors {
+		if _, ok := seen[actor.Name]; ok ||
+			!strings.Contains(actor.Name, keyword) {
+			continue
+		}
+		seen[actor.Name] = struct{}{}
+		result := &model.ActorSearchResult{
+			ID:       actor.Name,
+			Name:     actor.Name,
+			Provider: ab.Name(),
+			Homepage: fmt.Sprintf(searchURL, url.QueryEscape(actor.Name)),
+		}
+		if actor.ImageURL != "" {
+			result.Images = []string{actor.ImageURL}
+		}
+		results = append(results, result)
+	}
+	return
+}
+
+// searchWorks searches works by page, and returns the results
+// along with all the actors appeared in the matched works.
+func (ab *AVBase) searchWorks(keyword string, page int) (results []*model.MovieSearchResult, actors []actorResponse, err error) {
 	buildID, err := ab.GetBuildID()
 	if err != nil {
 		return
@@ -292,12 +341,13 @@ func (ab *AVBase) SearchMovie(keyword string) (results []*model.MovieSearchResul
 				for _, actor := range work.Actors {
 					result.Actors = append(result.Actors, actor.Name)
 				}
+				actors = append(actors, work.Actors...)
 				results = append(results, result)
 			}
 		}
 	})
 
-	err = c.Visit(fmt.Sprintf(searchAPIURL, buildID, url.QueryEscape(keyword)))
+	err = c.Visit(fmt.Sprintf(searchAPIURL, buildID, url.QueryEscape(keyword), page))
 	return
 }
 
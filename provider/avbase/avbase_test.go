@@ -23,3 +23,9 @@ func TestAVBase_SearchMovie(t *testing.T) {
 		"HMN",
 	})
 }
+
+func TestAVBase_GetActorFilmography(t *testing.T) {
+	testkit.Test(t, New, []string{
+		"三上悠亜",
+	})
+}
@@ -10,6 +10,7 @@ import (
 var (
 	_ provider.MovieProvider = (*Caribbeancom)(nil)
 	_ provider.MovieReviewer = (*Caribbeancom)(nil)
+	_ provider.ReleaseFeeder = (*Caribbeancom)(nil)
 )
 
 const (
@@ -20,6 +21,7 @@ const (
 const (
 	baseURL  = "https://www.caribbeancom.com/"
 	movieURL = "https://www.caribbeancom.com/moviepages/%s/index.html"
+	listURL  = "https://www.caribbeancom.com/listpages/all%d.htm"
 )
 
 type Caribbeancom struct {
@@ -31,6 +33,7 @@ func New() *Caribbeancom {
 		Core: (&core.Core{
 			BaseURL:         baseURL,
 			MovieURL:        movieURL,
+			ListURL:         listURL,
 			DefaultName:     Name,
 			DefaultPriority: Priority,
 			DefaultMaker:    "カリビアンコム",
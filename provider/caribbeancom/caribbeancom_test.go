@@ -19,3 +19,9 @@ func TestCaribbeancom_GetMovieReviewsByID(t *testing.T) {
 		"050422-001",
 	})
 }
+
+func TestCaribbeancom_GetReleaseFeed(t *testing.T) {
+	testkit.Test(t, New, []string{
+		"1",
+	})
+}
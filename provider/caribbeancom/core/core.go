@@ -27,6 +27,7 @@ type Core struct {
 	// URLs
 	BaseURL  string
 	MovieURL string
+	ListURL  string // newest movies by page
 
 	// Values
 	DefaultPriority float64
@@ -105,6 +106,52 @@ func (core *Core) GetMovieReviewsByID(id string) (reviews []*model.MovieReviewDe
 	return
 }
 
+// GetReleaseFeed impls ReleaseFeeder.GetReleaseFeed.
+func (core *Core) GetReleaseFeed(page int) (results []*model.MovieSearchResult, err error) {
+	if page < 1 {
+		page = 1
+	}
+
+	c := core.ClonedCollector()
+
+	c.OnXML(`//div[contains(@class,"grid-item")]`, func(e *colly.XMLElement) {
+		href := e.ChildAttr(`.//a[contains(@href,"/moviepages/")]`, "href")
+		if href == "" {
+			return
+		}
+		homepage := e.Request.AbsoluteURL(href)
+		id, _ := core.ParseMovieIDFromURL(homepage)
+		result := &model.MovieSearchResult{
+			ID:       id,
+			Number:   id,
+			Title:    strings.TrimSpace(e.ChildText(`.//*[contains(@class,"meta-title")]`)),
+			Provider: core.Name(),
+			Homepage: homepage,
+		}
+		if thumb := e.ChildAttr(`.//img`, "src"); thumb != "" {
+			result.ThumbURL = e.Request.AbsoluteURL(thumb)
+			result.CoverURL = result.ThumbURL /* use thumb as cover */
+		}
+		for _, data := range e.ChildTexts(`.//div[contains(@class,"meta-data")]`) {
+			if date := parser.ParseDate(strings.TrimSpace(data)); !time.Time(date).IsZero() {
+				result.ReleaseDate = date
+				break
+			}
+		}
+		for _, actor := range e.ChildTexts(`.//a[contains(@class,"name")]`) {
+			if actor := strings.TrimSpace(actor); actor != "" {
+				result.Actors = append(result.Actors, actor)
+			}
+		}
+		results = append(results, result)
+	})
+
+	if vErr := c.Visit(fmt.Sprintf(core.ListURL, page)); vErr != nil {
+		err = vErr
+	}
+	return
+}
+
 func (core *Core) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err error) {
 	id, err := core.ParseMovieIDFromURL(rawURL)
 	if err != nil {
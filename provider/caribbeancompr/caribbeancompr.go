@@ -10,6 +10,7 @@ import (
 var (
 	_ provider.MovieProvider = (*CaribbeancomPremium)(nil)
 	_ provider.MovieReviewer = (*CaribbeancomPremium)(nil)
+	_ provider.ReleaseFeeder = (*CaribbeancomPremium)(nil)
 )
 
 const (
@@ -20,6 +21,7 @@ const (
 const (
 	baseURL  = "https://www.caribbeancompr.com/"
 	movieURL = "https://www.caribbeancompr.com/moviepages/%s/index.html"
+	listURL  = "https://www.caribbeancompr.com/listpages/all%d.htm"
 )
 
 type CaribbeancomPremium struct {
@@ -31,6 +33,7 @@ func New() *CaribbeancomPremium {
 		Core: (&core.Core{
 			BaseURL:         baseURL,
 			MovieURL:        movieURL,
+			ListURL:         listURL,
 			DefaultName:     Name,
 			DefaultPriority: Priority,
 			DefaultMaker:    "カリビアンコムプレミアム",
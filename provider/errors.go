@@ -10,8 +10,10 @@ var (
 	ErrInvalidID          = errors.New(http.StatusBadRequest, "invalid id")
 	ErrInvalidURL         = errors.New(http.StatusBadRequest, "invalid url")
 	ErrInvalidKeyword     = errors.New(http.StatusBadRequest, "invalid keyword")
+	ErrInvalidCollection  = errors.New(http.StatusBadRequest, "invalid collection")
 	ErrInfoNotFound       = errors.New(http.StatusNotFound, "info not found")
 	ErrImageNotFound      = errors.New(http.StatusNotFound, "image not found")
+	ErrVideoNotFound      = errors.New(http.StatusNotFound, "video not found")
 	ErrProviderNotFound   = errors.New(http.StatusNotFound, "provider not found")
 	ErrIncompleteMetadata = errors.New(http.StatusInternalServerError, "incomplete metadata")
 )
@@ -23,6 +23,7 @@ import (
 
 	"github.com/metatube-community/metatube-sdk-go/collection/sets"
 	"github.com/metatube-community/metatube-sdk-go/common/comparer"
+	"github.com/metatube-community/metatube-sdk-go/common/fetch"
 	"github.com/metatube-community/metatube-sdk-go/common/js"
 	"github.com/metatube-community/metatube-sdk-go/common/number"
 	"github.com/metatube-community/metatube-sdk-go/common/parser"
@@ -35,9 +36,12 @@ import (
 )
 
 var (
-	_ provider.MovieProvider = (*FANZA)(nil)
-	_ provider.MovieSearcher = (*FANZA)(nil)
-	_ provider.MovieReviewer = (*FANZA)(nil)
+	_ provider.MovieProvider     = (*FANZA)(nil)
+	_ provider.MovieSearcher     = (*FANZA)(nil)
+	_ provider.MovieReviewer     = (*FANZA)(nil)
+	_ provider.ActorFilmographer = (*FANZA)(nil)
+	_ provider.MovieBrowser      = (*FANZA)(nil)
+	_ provider.ReleaseFeeder     = (*FANZA)(nil)
 )
 
 const (
@@ -51,6 +55,9 @@ const (
 	baseDigitalURL         = "https://www.dmm.co.jp/digital/" // deprecated
 	baseMonoURL            = "https://www.dmm.co.jp/mono/"
 	searchURL              = "https://www.dmm.co.jp/search/=/searchstr=%s/limit=120/sort=date/"
+	searchActressURL       = "https://www.dmm.co.jp/search/=/article=actress/id=%s/limit=120/sort=date/page=%d/"
+	searchArticleURL       = "https://www.dmm.co.jp/search/=/article=%s/id=%s/limit=120/sort=date/page=%d/"
+	searchNewestURL        = "https://www.dmm.co.jp/search/=/limit=120/sort=date/page=%d/"
 	movieDigitalURL        = "https://video.dmm.co.jp/%s/content/?id=%s"
 	movieDigitalAVURL      = "https://video.dmm.co.jp/av/content/?id=%s"
 	movieDigitalAmateurURL = "https://video.dmm.co.jp/amateur/content/?id=%s"
@@ -76,29 +83,32 @@ type FANZA struct {
 }
 
 func New() *FANZA {
-	httpClient := &http.Client{}
+	s := scraper.NewDefaultScraper(
+		Name, baseURL, Priority, language.Japanese,
+		scraper.WithCookies(baseURL, []*http.Cookie{
+			{Name: "age_check_done", Value: "1"},
+		}),
+		scraper.WithCookies(videoURL, []*http.Cookie{
+			{Name: "age_check_done", Value: "1"},
+		}),
+		scraper.WithResourceHosts(
+			"video.dmm.co.jp",
+			"pics.dmm.co.jp",
+			"awsimgsrc.dmm.co.jp",
+		),
+	)
+	httpClient := &http.Client{
+		Transport: s.WrapTimeout(fetch.WrapTransport(s.Proxy().Wrap(nil))),
+	}
 	return &FANZA{
 		httpClient: httpClient,
 		videoAPI: graphql.NewClient(
 			graphql.WithHTTPClient(httpClient),
 		),
-		Scraper: scraper.NewDefaultScraper(
-			Name, baseURL, Priority, language.Japanese,
-			scraper.WithCookies(baseURL, []*http.Cookie{
-				{Name: "age_check_done", Value: "1"},
-			}),
-			scraper.WithCookies(videoURL, []*http.Cookie{
-				{Name: "age_check_done", Value: "1"},
-			}),
-		),
+		Scraper: s,
 	}
 }
 
-func (fz *FANZA) SetRequestTimeout(timeout time.Duration) {
-	fz.httpClient.Timeout = timeout
-	fz.Scraper.SetRequestTimeout(timeout)
-}
-
 func (fz *FANZA) NormalizeMovieID(id string) string {
 	return strings.ToLower(id) /* FANZA uses lowercase ID */
 }
@@ -604,7 +614,43 @@ func (fz *FANZA) searchMovieNext(keyword string) (results []*model.MovieSearchRe
 	defer func() {
 		fz.sortMovieSearchResults(keyword, results)
 	}()
+	return fz.visitSearchPage(fmt.Sprintf(searchURL, url.QueryEscape(keyword)))
+}
+
+// GetActorFilmography impls ActorFilmographer.GetActorFilmography,
+// the id is the actress id, e.g.: 1044099.
+func (fz *FANZA) GetActorFilmography(id string, page int) ([]*model.MovieSearchResult, error) {
+	if page < 1 {
+		page = 1
+	}
+	return fz.visitSearchPage(fmt.Sprintf(searchActressURL, url.QueryEscape(id), page))
+}
 
+// BrowseMovies impls MovieBrowser.BrowseMovies, the id is the
+// id of maker, label or series, e.g.: 40016.
+func (fz *FANZA) BrowseMovies(collection, id string, page int) ([]*model.MovieSearchResult, error) {
+	switch collection {
+	case provider.MakerCollection,
+		provider.LabelCollection,
+		provider.SeriesCollection:
+	default:
+		return nil, provider.ErrInvalidCollection
+	}
+	if page < 1 {
+		page = 1
+	}
+	return fz.visitSearchPage(fmt.Sprintf(searchArticleURL, collection, url.QueryEscape(id), page))
+}
+
+// GetReleaseFeed impls ReleaseFeeder.GetReleaseFeed.
+func (fz *FANZA) GetReleaseFeed(page int) ([]*model.MovieSearchResult, error) {
+	if page < 1 {
+		page = 1
+	}
+	return fz.visitSearchPage(fmt.Sprintf(searchNewestURL, page))
+}
+
+func (fz *FANZA) visitSearchPage(rawURL string) (results []*model.MovieSearchResult, err error) {
 	c := fz.ClonedCollector()
 	p := searchparse.NewSearchPageParser()
 
@@ -612,7 +658,7 @@ func (fz *FANZA) searchMovieNext(keyword string) (results []*model.MovieSearchRe
 		_ = p.LoadJSCode(e.Text)
 	})
 
-	if err = c.Visit(fmt.Sprintf(searchURL, url.QueryEscape(keyword))); err != nil {
+	if err = c.Visit(rawURL); err != nil {
 		return
 	}
 
@@ -124,6 +124,25 @@ func TestFANZA_SearchMovie(t *testing.T) {
 	})
 }
 
+func TestFANZA_GetActorFilmography(t *testing.T) {
+	testkit.Test(t, New, []string{
+		"1044099",
+	})
+}
+
+func TestFANZA_BrowseMovies(t *testing.T) {
+	testkit.Test(t, New, []string{
+		"maker:40016",
+		"label:25155",
+	})
+}
+
+func TestFANZA_GetReleaseFeed(t *testing.T) {
+	testkit.Test(t, New, []string{
+		"1",
+	})
+}
+
 func TestFANZA_GetMovieReviewsByURL(t *testing.T) {
 	testkit.Test(t, New, []string{
 		"https://www.dmm.co.jp/digital/videoa/-/detail/=/cid=dass00256/",
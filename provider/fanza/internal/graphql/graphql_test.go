@@ -7,8 +7,18 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/metatube-community/metatube-sdk-go/common/fetch"
+	"github.com/metatube-community/metatube-sdk-go/provider/internal/testkit"
 )
 
+func newTestClient(t *testing.T) *Client {
+	testkit.UseCassette(t)
+	client := NewClient(WithHTTPClient(&http.Client{Transport: fetch.WrapTransport(nil)}))
+	client.gc.Log = func(s string) { t.Log(s) }
+	return client
+}
+
 func TestBuildQueryOptions(t *testing.T) {
 	tests := []struct {
 		inputURL string
@@ -29,8 +39,7 @@ func TestBuildQueryOptions(t *testing.T) {
 }
 
 func TestClient_GetContentPageData(t *testing.T) {
-	client := NewClient(WithHTTPClient(http.DefaultClient))
-	client.gc.Log = func(s string) { t.Log(s) }
+	client := newTestClient(t)
 
 	content, err := client.GetContentPageData("1start00190", ContentPageDataQueryOptions{IsAv: true})
 	require.NoError(t, err)
@@ -41,16 +50,14 @@ func TestClient_GetContentPageData(t *testing.T) {
 }
 
 func TestClient_GetContentPageData_Error(t *testing.T) {
-	client := NewClient(WithHTTPClient(http.DefaultClient))
-	client.gc.Log = func(s string) { t.Log(s) }
+	client := newTestClient(t)
 
 	_, err := client.GetContentPageData("oj8k666", ContentPageDataQueryOptions{IsAv: true})
 	require.ErrorIs(t, err, ErrNullResponse)
 }
 
 func TestClient_GetUserReviews(t *testing.T) {
-	client := NewClient(WithHTTPClient(http.DefaultClient))
-	client.gc.Log = func(s string) { t.Log(s) }
+	client := newTestClient(t)
 
 	content, err := client.GetUserReviews("1start00190", 0)
 	require.NoError(t, err)
@@ -61,8 +68,7 @@ func TestClient_GetUserReviews(t *testing.T) {
 }
 
 func TestClient_GetUserReviews_Error(t *testing.T) {
-	client := NewClient(WithHTTPClient(http.DefaultClient))
-	client.gc.Log = func(s string) { t.Log(s) }
+	client := newTestClient(t)
 
 	_, err := client.GetUserReviews("oj8k666", 0)
 	require.ErrorIs(t, err, ErrNullResponse)
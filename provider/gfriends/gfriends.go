@@ -38,18 +38,22 @@ const (
 
 type Gfriends struct {
 	*scraper.Scraper
+	tree *fileTree
 }
 
 func New() *Gfriends {
-	return &Gfriends{scraper.NewDefaultScraper(
-		Name, baseURL, Priority,
-		language.Japanese,
-		scraper.WithDisableCookies(),
-	)}
+	return &Gfriends{
+		Scraper: scraper.NewDefaultScraper(
+			Name, baseURL, Priority,
+			language.Japanese,
+			scraper.WithDisableCookies(),
+		),
+		tree: newFileTree(2*time.Hour, fetch.Default(nil)),
+	}
 }
 
 func (gf *Gfriends) GetActorInfoByID(id string) (*model.ActorInfo, error) {
-	images, err := _fileTree.query(id)
+	images, err := gf.tree.query(id)
 	if len(images) == 0 {
 		if err != nil {
 			return nil, err
@@ -98,13 +102,9 @@ func (gf *Gfriends) SearchActor(keyword string) (results []*model.ActorSearchRes
 	return
 }
 
-var (
-	_fileTree = newFileTree(2 * time.Hour)
-	_fetcher  = fetch.Default(nil)
-)
-
 type fileTree struct {
-	single *singledo.Single
+	single  *singledo.Single
+	fetcher *fetch.Fetcher
 
 	// `Content`
 	Content *maps.OrderedMap[string, *maps.OrderedMap[string, string]] `json:"Content"`
@@ -117,9 +117,10 @@ type fileTree struct {
 	//} `json:"Information"`
 }
 
-func newFileTree(wait time.Duration) *fileTree {
+func newFileTree(wait time.Duration, fetcher *fetch.Fetcher) *fileTree {
 	return &fileTree{
 		single:  singledo.NewSingle(wait),
+		fetcher: fetcher,
 		Content: maps.NewOrderedMap[string, *maps.OrderedMap[string, string]](),
 	}
 }
@@ -145,7 +146,7 @@ func (ft *fileTree) query(s string) (images []string, err error) {
 }
 
 func (ft *fileTree) update() error {
-	resp, err := _fetcher.Fetch(jsonURL)
+	resp, err := ft.fetcher.Fetch(jsonURL)
 	if err != nil {
 		return err
 	}
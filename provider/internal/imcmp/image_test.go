@@ -4,9 +4,12 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/metatube-community/metatube-sdk-go/provider/internal/testkit"
 )
 
 func TestSimilar(t *testing.T) {
+	testkit.UseCassette(t)
 	for _, item := range []struct {
 		imgUrl1, imgUrl2 string
 		similar          bool
@@ -0,0 +1,174 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.uber.org/atomic"
+
+	"github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+var (
+	_ provider.MirrorSetter = (*Scraper)(nil)
+	_ provider.MirrorGetter = (*Scraper)(nil)
+)
+
+// mirrorSet is an immutable list of base URLs, the primary one first.
+type mirrorSet struct {
+	urls    []*url.URL
+	current *atomic.Int64 // index of the working mirror.
+}
+
+func newMirrorSet(urls []*url.URL) *mirrorSet {
+	return &mirrorSet{urls: urls, current: atomic.NewInt64(0)}
+}
+
+// match returns the index of the base URL that u belongs to, or -1.
+func (ms *mirrorSet) match(u *url.URL) int {
+	for idx, base := range ms.urls {
+		if strings.EqualFold(u.Host, base.Host) &&
+			strings.HasPrefix(u.Path, base.Path) {
+			return idx
+		}
+	}
+	return -1
+}
+
+// rewrite rewrites u of the from base URL to the to base URL.
+func (ms *mirrorSet) rewrite(u *url.URL, from, to int) *url.URL {
+	if from == to {
+		return u
+	}
+	src, dst := ms.urls[from], ms.urls[to]
+	r := *u
+	r.Scheme, r.Host = dst.Scheme, dst.Host
+	r.Path = dst.Path + strings.TrimPrefix(u.Path, src.Path)
+	if u.RawPath != "" {
+		r.RawPath = dst.EscapedPath() + strings.TrimPrefix(u.RawPath, src.EscapedPath())
+	}
+	return &r
+}
+
+// SetMirrors sets extra mirror base URLs of the provider, requests to
+// any of the base URLs are sent to the working mirror, and switched
+// to the next one automatically when it fails.
+func (s *Scraper) SetMirrors(mirrors []string) error {
+	urls := []*url.URL{s.baseURL}
+	for _, mirror := range mirrors {
+		u, err := url.Parse(mirror)
+		if err != nil {
+			return fmt.Errorf("invalid mirror url %q: %w", mirror, err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid mirror url: %s", mirror)
+		}
+		if u.Path == "" {
+			u.Path = "/"
+		}
+		if newMirrorSet(urls).match(u) >= 0 {
+			continue // duplicated.
+		}
+		urls = append(urls, u)
+	}
+	s.mirrors.Store(newMirrorSet(urls))
+	s.setDefaultRateLimit()
+	return nil
+}
+
+// Mirrors returns all base URLs, the primary one first.
+func (s *Scraper) Mirrors() []*url.URL {
+	return s.mirrors.Load().urls
+}
+
+// PreferredURL rewrites the URL of any base URL to the working mirror.
+func (s *Scraper) PreferredURL(rawURL string) string {
+	ms := s.mirrors.Load()
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	idx := ms.match(u)
+	if idx < 0 {
+		return rawURL
+	}
+	return ms.rewrite(u, idx, int(ms.current.Load())).String()
+}
+
+// wrapMirrors returns a http.RoundTripper which sends requests to the
+// working mirror, and fails over to the next mirror on errors.
+func (s *Scraper) wrapMirrors(rt http.RoundTripper) http.RoundTripper {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	return &mirrorTransport{mirrors: s.mirrors, base: rt}
+}
+
+type mirrorTransport struct {
+	mirrors *atomic.Pointer[mirrorSet]
+	base    http.RoundTripper
+}
+
+// mirrorFailed reports whether the response indicates that the mirror is
+// down or unusable, e.g. blocked by region or a parked domain answering
+// 403/404 to every path. The request is then retried on the next mirror.
+func mirrorFailed(resp *http.Response) bool {
+	return resp.StatusCode >= http.StatusInternalServerError ||
+		resp.StatusCode == http.StatusForbidden ||
+		resp.StatusCode == http.StatusNotFound
+}
+
+func (mt *mirrorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ms := mt.mirrors.Load()
+	from := ms.match(req.URL)
+	if len(ms.urls) < 2 || from < 0 {
+		return mt.base.RoundTrip(req)
+	}
+	// request body can't be replayed.
+	replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+
+	start := int(ms.current.Load())
+	var (
+		// first is the failed response of the working mirror, which is
+		// returned if all mirrors fail, e.g. the page is really missing.
+		first *http.Response
+		err   error
+	)
+	for i := 0; i < len(ms.urls); i++ {
+		idx := (start + i) % len(ms.urls)
+		r := req.Clone(req.Context())
+		r.URL = ms.rewrite(req.URL, from, idx)
+		r.Host = "" // use the host of URL.
+		if i > 0 && req.GetBody != nil {
+			if r.Body, err = req.GetBody(); err != nil {
+				break
+			}
+		}
+		var resp *http.Response
+		if resp, err = mt.base.RoundTrip(r); err == nil && !mirrorFailed(resp) {
+			if first != nil {
+				_ = first.Body.Close()
+			}
+			ms.current.Store(int64(idx)) // sticky.
+			resp.Request = req
+			return resp, nil
+		}
+		if resp != nil {
+			if first == nil {
+				first = resp
+			} else {
+				_ = resp.Body.Close()
+			}
+		}
+		if req.Context().Err() != nil || !replayable {
+			break
+		}
+	}
+	if first != nil {
+		first.Request = req
+		return first, nil
+	}
+	return nil, err
+}
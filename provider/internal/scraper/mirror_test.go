@@ -0,0 +1,77 @@
+package scraper
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gocolly/colly/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestScraper_Mirrors(t *testing.T) {
+	// reserve an unused address as a dead primary site.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	dead := "http://" + l.Addr().String() + "/"
+	require.NoError(t, l.Close())
+
+	var hits atomic.Int32
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if r.URL.Path == "/site/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+	defer mirror.Close()
+
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer broken.Close()
+
+	// blocked by region, and a parked domain.
+	blocked := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer blocked.Close()
+	parked := httptest.NewServer(http.NotFoundHandler())
+	defer parked.Close()
+
+	s := NewDefaultScraper("test", dead, 1, language.Und)
+	require.NoError(t, s.SetMirrors([]string{broken.URL, blocked.URL, parked.URL, mirror.URL + "/site/", dead}))
+	require.Len(t, s.Mirrors(), 5) // duplicates are ignored.
+
+	visit := func(rawURL string) (body string) {
+		c := s.ClonedCollector()
+		c.OnResponse(func(r *colly.Response) { body = string(r.Body) })
+		require.NoError(t, c.Visit(rawURL))
+		return
+	}
+
+	// fail over to the working mirror.
+	assert.Equal(t, "/site/movie/ABC-123", visit(dead+"movie/ABC-123"))
+	assert.Equal(t, mirror.URL+"/site/movie/ABC-123", s.PreferredURL(dead+"movie/ABC-123"))
+	assert.Equal(t, mirror.URL+"/site/x", s.PreferredURL(broken.URL+"/x"))
+	assert.Equal(t, "https://example.com/x", s.PreferredURL("https://example.com/x"))
+
+	// the working mirror is sticky.
+	assert.Equal(t, "/site/a", visit(dead+"a"))
+	assert.EqualValues(t, 2, hits.Load())
+
+	// missing pages are answered by the working mirror, which is kept.
+	c := s.ClonedCollector()
+	var status int
+	c.OnError(func(r *colly.Response, _ error) { status = r.StatusCode })
+	assert.Error(t, c.Visit(dead+"missing"))
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, mirror.URL+"/site/missing", s.PreferredURL(dead+"missing"))
+	assert.Equal(t, "/site/b", visit(dead+"b"))
+	assert.EqualValues(t, 4, hits.Load())
+}
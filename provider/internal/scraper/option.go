@@ -8,6 +8,7 @@ import (
 	"github.com/gocolly/colly/v2/debug"
 
 	"github.com/metatube-community/metatube-sdk-go/common/random"
+	"github.com/metatube-community/metatube-sdk-go/common/ratelimit"
 )
 
 type Option func(*Scraper) error
@@ -66,7 +67,7 @@ func WithCookies(url string, cookies []*http.Cookie) Option {
 
 func WithRequestTimeout(timeout time.Duration) Option {
 	return func(s *Scraper) error {
-		s.c.SetRequestTimeout(timeout)
+		s.SetRequestTimeout(timeout)
 		return nil
 	}
 }
@@ -90,11 +91,31 @@ func WithDisableRedirects() Option {
 
 func WithTransport(transport http.RoundTripper) Option {
 	return func(s *Scraper) error {
+		s.transport = transport
 		s.c.WithTransport(transport)
 		return nil
 	}
 }
 
+// WithRateLimit sets the default limits shared by the base URL, mirror
+// and resource hosts, it applies to all clients and can be overridden by
+// provider configs.
+func WithRateLimit(cfg ratelimit.Config) Option {
+	return func(s *Scraper) error {
+		s.rateLimit = &cfg
+		return nil
+	}
+}
+
+// WithResourceHosts adds extra hosts of resources, e.g. image
+// CDNs, which share the rate limits of the base URL host.
+func WithResourceHosts(hosts ...string) Option {
+	return func(s *Scraper) error {
+		s.resourceHosts = append(s.resourceHosts, hosts...)
+		return nil
+	}
+}
+
 func WithLimit(rule *colly.LimitRule) Option {
 	return func(s *Scraper) error {
 		return s.c.Limit(rule)
@@ -1,6 +1,9 @@
 package scraper
 
 import (
+	"context"
+	"io"
+	"net/http"
 	"net/url"
 	"time"
 
@@ -8,12 +11,18 @@ import (
 	"go.uber.org/atomic"
 	"golang.org/x/text/language"
 
+	"github.com/metatube-community/metatube-sdk-go/common/fetch"
+	"github.com/metatube-community/metatube-sdk-go/common/proxy"
+	"github.com/metatube-community/metatube-sdk-go/common/ratelimit"
 	"github.com/metatube-community/metatube-sdk-go/provider"
 )
 
 var (
 	_ provider.Provider             = (*Scraper)(nil)
 	_ provider.RequestTimeoutSetter = (*Scraper)(nil)
+	_ provider.RequestTimeoutGetter = (*Scraper)(nil)
+	_ provider.ProxySetter          = (*Scraper)(nil)
+	_ provider.ResourceHostsGetter  = (*Scraper)(nil)
 )
 
 // Scraper implements the basic Provider interface.
@@ -23,6 +32,19 @@ type Scraper struct {
 	priority *atomic.Float64
 	language language.Tag
 	c        *colly.Collector
+	timeout  *atomic.Duration
+	// transport is the custom HTTP transport, nil means default.
+	transport http.RoundTripper
+	// proxy is the proxy switch shared with provider's Fetcher.
+	proxy *proxy.Switch
+	// session handles login and cookies, nil means disabled.
+	session *session
+	// mirrors are the base URLs in failover order.
+	mirrors *atomic.Pointer[mirrorSet]
+	// resourceHosts are extra hosts of resources, e.g. images.
+	resourceHosts []string
+	// rateLimit is the default limits shared by all hosts, nil means unlimited.
+	rateLimit *ratelimit.Config
 }
 
 // NewScraper returns a *Scraper that implements provider.Provider.
@@ -37,6 +59,9 @@ func NewScraper(name, base string, priority float64, lang language.Tag, opts ...
 		priority: atomic.NewFloat64(priority),
 		language: lang,
 		c:        colly.NewCollector(),
+		timeout:  atomic.NewDuration(0),
+		proxy:    proxy.NewSwitch(),
+		mirrors:  atomic.NewPointer(newMirrorSet([]*url.URL{baseURL})),
 	}
 	for _, opt := range opts {
 		// Apply options.
@@ -44,6 +69,17 @@ func NewScraper(name, base string, priority float64, lang language.Tag, opts ...
 			panic(err)
 		}
 	}
+	s.setDefaultRateLimit()
+	// Apply proxy switch, mirrors and process-wide transport wrapper, if any.
+	transport := fetch.WrapTransport(s.wrapMirrors(s.proxy.Wrap(s.transport)))
+	if s.session != nil {
+		transport = s.session.wrap(transport)
+	}
+	// The timeout is applied per request instead of by the client, which
+	// is shared by all cloned collectors, so that it can be changed while
+	// requests are in flight.
+	s.c.SetRequestTimeout(0)
+	s.c.WithTransport(s.WrapTimeout(transport))
 	return s
 }
 
@@ -77,5 +113,78 @@ func (s *Scraper) ParseActorIDFromURL(string) (string, error) { panic("unimpleme
 // ClonedCollector returns cloned internal collector.
 func (s *Scraper) ClonedCollector() *colly.Collector { return s.c.Clone() }
 
-// SetRequestTimeout sets timeout for HTTP requests.
-func (s *Scraper) SetRequestTimeout(timeout time.Duration) { s.c.SetRequestTimeout(timeout) }
+// SetRequestTimeout sets timeout for HTTP requests, it's safe to
+// call while requests are in flight.
+func (s *Scraper) SetRequestTimeout(timeout time.Duration) { s.timeout.Store(timeout) }
+
+// RequestTimeout returns timeout for HTTP requests, 0 means default.
+func (s *Scraper) RequestTimeout() time.Duration { return s.timeout.Load() }
+
+// WrapTimeout returns a http.RoundTripper which applies the request
+// timeout of the scraper to every request over rt, it should be used
+// by the other HTTP clients of the provider, if any.
+func (s *Scraper) WrapTimeout(rt http.RoundTripper) http.RoundTripper {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	return &timeoutTransport{timeout: s.timeout, next: rt}
+}
+
+// SetProxy sets the proxy pool for HTTP requests, nil means no proxy.
+func (s *Scraper) SetProxy(pool *proxy.Pool) { s.proxy.Set(pool) }
+
+// Proxy returns the proxy switch, which should be shared
+// with provider's Fetcher via fetch.Config.Proxy.
+func (s *Scraper) Proxy() *proxy.Switch { return s.proxy }
+
+// ResourceHosts returns extra hosts of resources, e.g. images.
+func (s *Scraper) ResourceHosts() []string { return s.resourceHosts }
+
+// setDefaultRateLimit applies the default limits to the base URL,
+// mirror and resource hosts, the hosts join the existing limits of
+// the others, e.g. mirrors added after provider configs.
+func (s *Scraper) setDefaultRateLimit() {
+	if s.rateLimit == nil {
+		return
+	}
+	var hosts []string
+	for _, u := range s.Mirrors() {
+		hosts = append(hosts, u.Hostname())
+	}
+	hosts = append(hosts, s.resourceHosts...)
+	ratelimit.DefaultRegistry.SetDefaultGroup(hosts, *s.rateLimit)
+}
+
+// defaultRequestTimeout is the same as the default of colly.
+const defaultRequestTimeout = 10 * time.Second
+
+type timeoutTransport struct {
+	timeout *atomic.Duration
+	next    http.RoundTripper
+}
+
+func (t *timeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	timeout := t.timeout.Load()
+	if timeout <= 0 {
+		timeout = defaultRequestTimeout
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// the body is read within the timeout as well.
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,28 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+func TestScraper_SetRequestTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		_, _ = w.Write([]byte("content"))
+	}))
+	defer server.Close()
+
+	s := NewDefaultScraper("test", server.URL+"/", 1, language.Und)
+	assert.NoError(t, s.ClonedCollector().Visit(server.URL))
+
+	// applies to the collectors cloned before.
+	c := s.ClonedCollector()
+	s.SetRequestTimeout(50 * time.Millisecond)
+	assert.ErrorIs(t, c.Visit(server.URL), context.DeadlineExceeded)
+}
@@ -0,0 +1,294 @@
+package scraper
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+
+	"github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+var _ provider.SessionSetter = (*Scraper)(nil)
+
+// LoginFunc logs in with the given collector, cookies set by the
+// responses are shared with the scraper via the cookie jar. It's
+// also called without credentials to refresh cookie based sessions,
+// e.g. age gates, and should return an error if that's unsupported.
+type LoginFunc func(c *colly.Collector, username, password string) error
+
+// ErrLoginRejected is returned by LoginForm if the login form is
+// shown again after submitting, e.g. the credentials are wrong.
+var ErrLoginRejected = errors.New("login rejected")
+
+// SessionExpiredFunc reports whether the response indicates that
+// the session has expired and a re-login is required.
+type SessionExpiredFunc func(resp *http.Response) bool
+
+// reloginInterval is the minimum interval between two logins.
+const reloginInterval = 10 * time.Second
+
+var sessionLogger = log.New(os.Stdout, "[SESSION]\u0020", log.LstdFlags)
+
+// loginContextKey marks requests made by the LoginFunc,
+// so that they bypass the session handling.
+type loginContextKey struct{}
+
+type session struct {
+	s       *Scraper
+	login   LoginFunc
+	expired SessionExpiredFunc
+
+	mu       sync.Mutex
+	store    provider.SessionStore
+	username string
+	password string
+	loaded   bool
+	// skipStore skips the stored cookies on the next load, as
+	// they may belong to the account of previous credentials.
+	skipStore bool
+	lastLogin time.Time
+}
+
+// WithSession enables session handling for the scraper. The stored cookies
+// are loaded before the first request, or if there are none and credentials
+// are set, the login func is called. It's also called whenever a response
+// is considered expired, then the request is retried once. Cookies obtained
+// are persisted to the session store.
+func WithSession(login LoginFunc, expired SessionExpiredFunc) Option {
+	return func(s *Scraper) error {
+		s.session = &session{
+			s:       s,
+			login:   login,
+			expired: expired,
+		}
+		return nil
+	}
+}
+
+// SetSessionStore sets the store to persist session cookies.
+func (s *Scraper) SetSessionStore(store provider.SessionStore) {
+	if s.session == nil {
+		return
+	}
+	s.session.mu.Lock()
+	defer s.session.mu.Unlock()
+	s.session.store = store
+	s.session.loaded = false // reload from the new store.
+}
+
+// SetCredentials sets the credentials to log in. Changed credentials
+// take effect on the next request, without using the stored cookies.
+func (s *Scraper) SetCredentials(username, password string) {
+	if s.session == nil {
+		return
+	}
+	s.session.mu.Lock()
+	defer s.session.mu.Unlock()
+	if s.session.username == username && s.session.password == password {
+		return
+	}
+	s.session.username, s.session.password = username, password
+	if s.session.loaded {
+		s.session.loaded, s.session.skipStore = false, true
+	}
+}
+
+// SetSessionCookies sets the session cookies directly, e.g. cookies
+// exported from a browser, they take precedence over the stored ones.
+func (s *Scraper) SetSessionCookies(cookies []*http.Cookie) {
+	if err := s.c.SetCookies(s.baseURL.String(), cookies); err != nil {
+		sessionLogger.Printf("set cookies for %s: %v", s.name, err)
+		return
+	}
+	if s.session == nil {
+		return
+	}
+	s.session.mu.Lock()
+	defer s.session.mu.Unlock()
+	s.session.loaded = true // skip the stored ones.
+}
+
+// SessionEnabled reports whether session handling is enabled.
+func (s *Scraper) SessionEnabled() bool { return s.session != nil }
+
+func (ss *session) hasCredentials() bool {
+	return ss.username != "" && ss.password != ""
+}
+
+// ensure loads the stored cookies or logs in, only once. It
+// reports whether the cookies in the jar may have changed.
+func (ss *session) ensure() bool {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if ss.loaded {
+		return false
+	}
+	ss.loaded = true
+	if ss.store != nil && !ss.skipStore {
+		cookies, err := ss.store.LoadCookies(ss.s.name)
+		if err != nil {
+			sessionLogger.Printf("load cookies for %s: %v", ss.s.name, err)
+		} else if len(cookies) > 0 {
+			_ = ss.s.c.SetCookies(ss.s.baseURL.String(), cookies)
+			return true
+		}
+	}
+	ss.skipStore = false
+	if ss.hasCredentials() {
+		return ss.doLogin() == nil
+	}
+	return false
+}
+
+// relogin logs in again, unless another login was made recently.
+func (ss *session) relogin() error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if time.Since(ss.lastLogin) < reloginInterval {
+		return nil
+	}
+	return ss.doLogin()
+}
+
+// doLogin must be called with ss.mu held.
+func (ss *session) doLogin() error {
+	ss.lastLogin = time.Now()
+	c := ss.s.c.Clone()
+	c.Context = context.WithValue(c.Context, loginContextKey{}, true)
+	if err := ss.login(c, ss.username, ss.password); err != nil {
+		sessionLogger.Printf("login to %s: %v", ss.s.name, err)
+		return err
+	}
+	ss.save()
+	return nil
+}
+
+// save must be called with ss.mu held.
+func (ss *session) save() {
+	if ss.store == nil {
+		return
+	}
+	if err := ss.store.SaveCookies(ss.s.name, ss.s.c.Cookies(ss.s.baseURL.String())); err != nil {
+		sessionLogger.Printf("save cookies for %s: %v", ss.s.name, err)
+	}
+}
+
+// wrap returns a http.RoundTripper which handles the session over rt.
+func (ss *session) wrap(rt http.RoundTripper) http.RoundTripper {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	return &sessionTransport{session: ss, base: rt}
+}
+
+type sessionTransport struct {
+	session *session
+	base    http.RoundTripper
+}
+
+func (st *sessionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if v, _ := req.Context().Value(loginContextKey{}).(bool); v {
+		return st.base.RoundTrip(req)
+	}
+	if st.session.ensure() {
+		// cookies were added after the request was built.
+		req = st.withJarCookies(req)
+	}
+	resp, err := st.base.RoundTrip(req)
+	if err != nil || st.session.expired == nil || !st.session.expired(resp) {
+		return resp, err
+	}
+	// request body can't be replayed.
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+	if err = st.session.relogin(); err != nil {
+		return resp, nil
+	}
+	retry := st.withJarCookies(req)
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return resp, nil
+		}
+	}
+	_ = resp.Body.Close()
+	return st.base.RoundTrip(retry)
+}
+
+// withJarCookies returns a clone of req with the Cookie header rebuilt from the jar.
+func (st *sessionTransport) withJarCookies(req *http.Request) *http.Request {
+	r := req.Clone(req.Context())
+	r.Header.Del("Cookie")
+	for _, cookie := range st.session.s.c.Cookies(req.URL.String()) {
+		r.AddCookie(cookie)
+	}
+	return r
+}
+
+// LoginForm logs in by the form with a password input on the login page.
+// The password input and the first text or email input are filled with
+// the credentials, and the others are submitted as they are, e.g. CSRF
+// tokens. It returns ErrLoginRejected if the response has the form again.
+func LoginForm(c *colly.Collector, loginURL, username, password string) error {
+	const formSelector = `form:has(input[type="password"])`
+
+	var (
+		action string
+		data   map[string]string
+	)
+	page := c.Clone()
+	page.OnHTML(formSelector, func(e *colly.HTMLElement) {
+		if data != nil {
+			return // the first form only.
+		}
+		action = e.Request.AbsoluteURL(e.Attr("action"))
+		data = make(map[string]string)
+		filled := false
+		e.ForEach("input[name]", func(_ int, input *colly.HTMLElement) {
+			name, value := input.Attr("name"), input.Attr("value")
+			switch strings.ToLower(input.Attr("type")) {
+			case "password":
+				data[name] = password
+			case "", "text", "email":
+				if !filled {
+					data[name], filled = username, true
+				} else {
+					data[name] = value
+				}
+			case "checkbox", "radio":
+				if _, checked := input.DOM.Attr("checked"); checked {
+					data[name] = cmp.Or(value, "on")
+				}
+			case "submit", "button", "image", "reset", "file":
+			default:
+				data[name] = value
+			}
+		})
+	})
+	if err := page.Visit(loginURL); err != nil {
+		return err
+	}
+	if data == nil {
+		return fmt.Errorf("no login form at %s", loginURL)
+	}
+
+	rejected := false
+	submit := c.Clone()
+	submit.OnHTML(formSelector, func(*colly.HTMLElement) { rejected = true })
+	if err := submit.Post(cmp.Or(action, loginURL), data); err != nil {
+		return err
+	}
+	if rejected {
+		return ErrLoginRejected
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+type memoryStore struct {
+	mu      sync.Mutex
+	cookies map[string][]*http.Cookie
+}
+
+func (m *memoryStore) LoadCookies(name string) ([]*http.Cookie, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.cookies[name], nil
+}
+
+func (m *memoryStore) SaveCookies(name string, cookies []*http.Cookie) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cookies[name] = cookies
+	return nil
+}
+
+func TestScraper_Session(t *testing.T) {
+	var logins atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/agecheck":
+			logins.Add(1)
+			http.SetCookie(w, &http.Cookie{Name: "token", Value: "ok", Path: "/"})
+		case "/expired":
+			http.Redirect(w, r, "/agecheck", http.StatusFound)
+		default:
+			if c, err := r.Cookie("token"); err != nil || c.Value != "ok" {
+				http.Redirect(w, r, "/agecheck", http.StatusFound)
+				return
+			}
+			_, _ = w.Write([]byte("content"))
+		}
+	}))
+	defer server.Close()
+
+	login := func(c *colly.Collector, _, _ string) error {
+		return c.Visit(server.URL + "/agecheck")
+	}
+	expired := func(resp *http.Response) bool {
+		return resp.StatusCode == http.StatusFound
+	}
+	store := &memoryStore{cookies: map[string][]*http.Cookie{}}
+
+	s := NewDefaultScraper("test", server.URL+"/", 1, language.Und, WithSession(login, expired))
+	s.SetSessionStore(store)
+
+	visit := func(path string) (body string) {
+		c := s.ClonedCollector()
+		c.OnResponse(func(r *colly.Response) { body = string(r.Body) })
+		require.NoError(t, c.Visit(server.URL+path))
+		return
+	}
+
+	// login when the first request is redirected, and retry.
+	assert.Equal(t, "content", visit("/"))
+	assert.EqualValues(t, 1, logins.Load())
+	assert.Len(t, store.cookies["test"], 1)
+
+	// the session is still valid.
+	assert.Equal(t, "content", visit("/"))
+	assert.EqualValues(t, 1, logins.Load())
+
+	// stored cookies are reused by a new scraper.
+	s2 := NewDefaultScraper("test", server.URL+"/", 1, language.Und, WithSession(login, expired))
+	s2.SetSessionStore(store)
+	s = s2
+	assert.Equal(t, "content", visit("/"))
+	assert.EqualValues(t, 1, logins.Load())
+
+	// invalidate the session, re-login and retry once.
+	s.SetSessionCookies([]*http.Cookie{{Name: "token", Value: "bad"}})
+	s.session.lastLogin = time.Time{}
+	assert.Equal(t, "content", visit("/"))
+	assert.EqualValues(t, 2, logins.Load())
+}
+
+func TestScraper_SessionCredentials(t *testing.T) {
+	var logins atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			if r.Method == http.MethodPost &&
+				r.FormValue("csrf") == "token" &&
+				r.FormValue("remember") == "on" &&
+				r.FormValue("user") == "alice" && r.FormValue("pass") == "secret" {
+				logins.Add(1)
+				http.SetCookie(w, &http.Cookie{Name: "member", Value: "alice", Path: "/"})
+				return
+			}
+			_, _ = fmt.Fprint(w, `<html><body><form method="post" action="/login">
+<input type="hidden" name="csrf" value="token">
+<input type="text" name="user"><input type="password" name="pass">
+<input type="checkbox" name="remember" checked><input type="submit" name="go" value="Login">
+</form></body></html>`)
+		default:
+			if c, err := r.Cookie("member"); err != nil || c.Value != "alice" {
+				http.Redirect(w, r, "/login", http.StatusFound)
+				return
+			}
+			_, _ = w.Write([]byte("member"))
+		}
+	}))
+	defer server.Close()
+
+	login := func(c *colly.Collector, username, password string) error {
+		return LoginForm(c, server.URL+"/login", username, password)
+	}
+	expired := func(resp *http.Response) bool {
+		return resp.StatusCode == http.StatusFound
+	}
+	store := &memoryStore{cookies: map[string][]*http.Cookie{
+		"test": {{Name: "member", Value: "stale"}},
+	}}
+
+	s := NewDefaultScraper("test", server.URL+"/", 1, language.Und, WithSession(login, expired))
+	s.SetSessionStore(store)
+	s.SetCredentials("alice", "secret")
+
+	visit := func() (body string) {
+		c := s.ClonedCollector()
+		c.OnResponse(func(r *colly.Response) { body = string(r.Body) })
+		require.NoError(t, c.Visit(server.URL+"/"))
+		return
+	}
+
+	// stale stored cookies are replaced by a form login.
+	assert.Equal(t, "member", visit())
+	assert.EqualValues(t, 1, logins.Load())
+	require.Len(t, store.cookies["test"], 1)
+	assert.Equal(t, "alice", store.cookies["test"][0].Value)
+
+	// wrong credentials are rejected by the login form.
+	c := s.ClonedCollector()
+	assert.ErrorIs(t, LoginForm(c, server.URL+"/login", "alice", "wrong"), ErrLoginRejected)
+}
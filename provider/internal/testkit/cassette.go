@@ -0,0 +1,76 @@
+package testkit
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/metatube-community/metatube-sdk-go/common/cassette"
+	"github.com/metatube-community/metatube-sdk-go/common/fetch"
+)
+
+// modeEnvKey is the ENV key to choose the HTTP cassette mode,
+// available values are: live, record and replay. By default, the
+// tests replay their cassettes, and run live if they have none yet,
+// except in GitHub Actions where live tests are skipped. In replay
+// mode, missing cassettes fail the tests. Run `make record-cassettes`
+// to record.
+const modeEnvKey = "TESTKIT_MODE"
+
+// cassetteMu serializes the tests using cassettes, as the transport
+// wrapper is process-wide, see fetch.SetTransportWrapper.
+var cassetteMu sync.Mutex
+
+func cassettePath(t *testing.T) string {
+	return filepath.Join("testdata", "cassettes", t.Name()+".json")
+}
+
+func cassetteMode(t *testing.T) cassette.Mode {
+	if v, ok := os.LookupEnv(modeEnvKey); ok {
+		mode, err := cassette.ParseMode(v)
+		require.NoError(t, err)
+		return mode
+	}
+	if cassette.Exists(cassettePath(t)) {
+		return cassette.ModeReplay
+	}
+	if ci, _ := strconv.ParseBool(os.Getenv("GITHUB_ACTIONS")); ci {
+		t.Skipf("no cassette at %s, skip live test in GitHub Actions", cassettePath(t))
+	}
+	return cassette.ModeDisabled
+}
+
+// setupCassette installs the cassette transport for all Fetchers and
+// scrapers used during the test, and saves the recorded exchanges on
+// cleanup. Tests using cassettes run one at a time, even if parallel.
+func setupCassette(t *testing.T) cassette.Mode {
+	mode := cassetteMode(t)
+	if mode == cassette.ModeDisabled {
+		return mode
+	}
+	require.Truef(t, mode != cassette.ModeReplay || cassette.Exists(cassettePath(t)),
+		"no cassette at %s, set %s=record to record it", cassettePath(t), modeEnvKey)
+	c, err := cassette.New(cassettePath(t), mode)
+	require.NoError(t, err)
+
+	cassetteMu.Lock()
+	fetch.SetTransportWrapper(c.Wrap)
+	t.Cleanup(func() {
+		fetch.SetTransportWrapper(nil)
+		cassetteMu.Unlock()
+		if !t.Failed() && !t.Skipped() {
+			require.NoError(t, c.Save())
+		}
+	})
+	return mode
+}
+
+// UseCassette records or replays the HTTP traffic of tests which
+// don't go through Test, e.g. the ones of internal packages.
+func UseCassette(t *testing.T) {
+	setupCassette(t)
+}
@@ -5,21 +5,23 @@ import (
 	"io"
 	"math/rand"
 	"net/http"
-	"os"
 	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/metatube-community/metatube-sdk-go/common/cassette"
 	"github.com/metatube-community/metatube-sdk-go/model"
 	mt "github.com/metatube-community/metatube-sdk-go/provider"
 )
 
 type internalTestSuite struct {
-	t *testing.T
+	t    *testing.T
+	mode cassette.Mode
 }
 
 func (s *internalTestSuite) T() *testing.T {
@@ -31,7 +33,7 @@ func (s *internalTestSuite) testItems(items []string, call func(*testing.T, stri
 		s.T().Run(item, func(t *testing.T) {
 			call(t, item)
 		})
-		if i < len(items)-1 {
+		if i < len(items)-1 && s.mode != cassette.ModeReplay {
 			// Add random delay milliseconds.
 			time.Sleep(time.Duration(rand.Intn(500)+500) * time.Millisecond)
 		}
@@ -129,6 +131,53 @@ func (s *internalTestSuite) TestSearchMovie(p mt.MovieSearcher, items []string,
 	})
 }
 
+func (s *internalTestSuite) TestGetActorFilmography(p mt.ActorFilmographer, items []string, vfs ...ValidateFunc) {
+	s.testItems(items, func(t *testing.T, item string) {
+		results, err := p.GetActorFilmography(item, 1)
+		require.NoError(t, err)
+		require.NotEmpty(t, results)
+		for _, vf := range append([]ValidateFunc{
+			logJSONContent(),
+			assertIsValid(),
+		}, vfs...) {
+			vf(t, results)
+		}
+	})
+}
+
+// TestBrowseMovies tests items in the form of collection:id, e.g.: series:123.
+func (s *internalTestSuite) TestBrowseMovies(p mt.MovieBrowser, items []string, vfs ...ValidateFunc) {
+	s.testItems(items, func(t *testing.T, item string) {
+		collection, id, _ := strings.Cut(item, ":")
+		results, err := p.BrowseMovies(collection, id, 1)
+		require.NoError(t, err)
+		require.NotEmpty(t, results)
+		for _, vf := range append([]ValidateFunc{
+			logJSONContent(),
+			assertIsValid(),
+		}, vfs...) {
+			vf(t, results)
+		}
+	})
+}
+
+// TestGetReleaseFeed tests items of page numbers, e.g.: 1.
+func (s *internalTestSuite) TestGetReleaseFeed(p mt.ReleaseFeeder, items []string, vfs ...ValidateFunc) {
+	s.testItems(items, func(t *testing.T, item string) {
+		page, err := strconv.Atoi(item)
+		require.NoError(t, err)
+		results, err := p.GetReleaseFeed(page)
+		require.NoError(t, err)
+		require.NotEmpty(t, results)
+		for _, vf := range append([]ValidateFunc{
+			logJSONContent(),
+			assertIsValid(),
+		}, vfs...) {
+			vf(t, results)
+		}
+	})
+}
+
 func (s *internalTestSuite) TestFetch(p mt.Fetcher, items []string, vfs ...ValidateFunc) {
 	s.testItems(items, func(t *testing.T, item string) {
 		resp, err := p.Fetch(item)
@@ -148,9 +197,7 @@ func (s *internalTestSuite) TestFetch(p mt.Fetcher, items []string, vfs ...Valid
 }
 
 func Test[T mt.Provider](t *testing.T, new func() T, items []string, vfs ...ValidateFunc) {
-	if ci, _ := strconv.ParseBool(os.Getenv("GITHUB_ACTIONS")); ci {
-		t.SkipNow() // Skip in GitHub Actions
-	}
+	mode := setupCassette(t)
 
 	functionName := getFrame(1).Function
 	providerName, testMethod, err := parseTestFunction(functionName)
@@ -160,7 +207,7 @@ func Test[T mt.Provider](t *testing.T, new func() T, items []string, vfs ...Vali
 	structName := reflect.TypeOf(provider).Elem().Name()
 	require.Equal(t, providerName, structName)
 
-	s := &internalTestSuite{t}
+	s := &internalTestSuite{t: t, mode: mode}
 	m := reflect.ValueOf(s).MethodByName("Test" + testMethod)
 	require.Truef(t, m.IsValid(), "invalid test method: %s", testMethod)
 
@@ -21,9 +21,12 @@ import (
 )
 
 var (
-	_ provider.MovieProvider = (*JavBus)(nil)
-	_ provider.MovieSearcher = (*JavBus)(nil)
-	_ provider.Fetcher       = (*JavBus)(nil)
+	_ provider.MovieProvider     = (*JavBus)(nil)
+	_ provider.MovieSearcher     = (*JavBus)(nil)
+	_ provider.ActorSearcher     = (*JavBus)(nil)
+	_ provider.ActorFilmographer = (*JavBus)(nil)
+	_ provider.MovieBrowser      = (*JavBus)(nil)
+	_ provider.Fetcher           = (*JavBus)(nil)
 )
 
 const (
@@ -36,6 +39,11 @@ const (
 	movieURL            = "https://www.javbus.com/ja/%s"
 	searchURL           = "https://www.javbus.com/ja/search/%s"
 	searchUncensoredURL = "https://www.javbus.com/ja/uncensored/search/%s"
+	searchStarURL       = "https://www.javbus.com/ja/searchstar/%s"
+	starURL             = "https://www.javbus.com/ja/star/%s/%d"
+	starUncensoredURL   = "https://www.javbus.com/ja/uncensored/star/%s/%d"
+	browseURL           = "https://www.javbus.com/ja/%s/%s/%d"
+	browseUncensoredURL = "https://www.javbus.com/ja/uncensored/%s/%s/%d"
 )
 
 type JavBus struct {
@@ -44,19 +52,20 @@ type JavBus struct {
 }
 
 func New() *JavBus {
+	s := scraper.NewDefaultScraper(
+		Name, baseURL, Priority,
+		language.Japanese,
+		scraper.WithDisableRedirects(),
+		scraper.WithHeaders(map[string]string{
+			"Referer": baseURL,
+		}),
+		scraper.WithCookies(baseURL, []*http.Cookie{
+			// existmag=all
+			{Name: "existmag", Value: "all"},
+		}))
 	return &JavBus{
-		Fetcher: fetch.Default(&fetch.Config{Referer: baseURL}),
-		Scraper: scraper.NewDefaultScraper(
-			Name, baseURL, Priority,
-			language.Japanese,
-			scraper.WithDisableRedirects(),
-			scraper.WithHeaders(map[string]string{
-				"Referer": baseURL,
-			}),
-			scraper.WithCookies(baseURL, []*http.Cookie{
-				// existmag=all
-				{Name: "existmag", Value: "all"},
-			})),
+		Fetcher: fetch.Default(&fetch.Config{Referer: baseURL, Proxy: s.Proxy()}),
+		Scraper: s,
 	}
 }
 
@@ -172,7 +181,71 @@ func (bus *JavBus) NormalizeMovieKeyword(keyword string) string {
 	return strings.ToUpper(keyword)
 }
 
-func (bus *JavBus) SearchMovie(keyword string) (results []*model.MovieSearchResult, err error) {
+func (bus *JavBus) SearchMovie(keyword string) ([]*model.MovieSearchResult, error) {
+	return bus.visitMovieBoxes(
+		fmt.Sprintf(searchURL, keyword),
+		fmt.Sprintf(searchUncensoredURL, keyword))
+}
+
+// GetActorFilmography impls ActorFilmographer.GetActorFilmography,
+// the id is the star id, e.g.: okq.
+func (bus *JavBus) GetActorFilmography(id string, page int) ([]*model.MovieSearchResult, error) {
+	if page < 1 {
+		page = 1
+	}
+	return bus.visitMovieBoxes(
+		fmt.Sprintf(starURL, id, page),
+		fmt.Sprintf(starUncensoredURL, id, page))
+}
+
+// BrowseMovies impls MovieBrowser.BrowseMovies, the id is the
+// id of studio, label or series, e.g.: 7q.
+func (bus *JavBus) BrowseMovies(collection, id string, page int) ([]*model.MovieSearchResult, error) {
+	var category string
+	switch collection {
+	case provider.MakerCollection:
+		category = "studio"
+	case provider.LabelCollection:
+		category = "label"
+	case provider.SeriesCollection:
+		category = "series"
+	default:
+		return nil, provider.ErrInvalidCollection
+	}
+	if page < 1 {
+		page = 1
+	}
+	return bus.visitMovieBoxes(
+		fmt.Sprintf(browseURL, category, id, page),
+		fmt.Sprintf(browseUncensoredURL, category, id, page))
+}
+
+// SearchActor impls ActorSearcher.SearchActor, which is used to
+// look up star ids only, as JavBus is not an actor provider.
+func (bus *JavBus) SearchActor(keyword string) (results []*model.ActorSearchResult, err error) {
+	c := bus.ClonedCollector()
+
+	c.OnXML(`//a[@class="avatar-box text-center"]`, func(e *colly.XMLElement) {
+		homepage := e.Request.AbsoluteURL(e.Attr("href"))
+		result := &model.ActorSearchResult{
+			ID:       path.Base(homepage),
+			Name:     strings.TrimSpace(e.ChildText(`.//div[@class="photo-info"]/span`)),
+			Provider: bus.Name(),
+			Homepage: homepage,
+		}
+		if img := e.ChildAttr(`.//div[@class="photo-frame"]/img`, "src"); img != "" &&
+			!strings.Contains(img, "nowprinting") {
+			result.Images = []string{e.Request.AbsoluteURL(img)}
+		}
+		results = append(results, result)
+	})
+
+	err = c.Visit(fmt.Sprintf(searchStarURL, url.PathEscape(keyword)))
+	return
+}
+
+// visitMovieBoxes visits the list pages and collects the movie boxes.
+func (bus *JavBus) visitMovieBoxes(urls ...string) (results []*model.MovieSearchResult, err error) {
 	c := bus.ClonedCollector()
 	c.Async = true /* ASYNC */
 
@@ -201,10 +274,7 @@ func (bus *JavBus) SearchMovie(keyword string) (results []*model.MovieSearchResu
 		})
 	})
 
-	for _, u := range []string{
-		fmt.Sprintf(searchURL, keyword),
-		fmt.Sprintf(searchUncensoredURL, keyword),
-	} {
+	for _, u := range urls {
 		if err = c.Visit(u); err != nil {
 			return nil, err
 		}
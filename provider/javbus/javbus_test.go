@@ -22,3 +22,22 @@ func TestJavBus_SearchMovie(t *testing.T) {
 		"MIDV-005",
 	})
 }
+
+func TestJavBus_SearchActor(t *testing.T) {
+	testkit.Test(t, New, []string{
+		"三上悠亜",
+	})
+}
+
+func TestJavBus_GetActorFilmography(t *testing.T) {
+	testkit.Test(t, New, []string{
+		"okq",
+	})
+}
+
+func TestJavBus_BrowseMovies(t *testing.T) {
+	testkit.Test(t, New, []string{
+		"maker:7q",
+		"series:2ej",
+	})
+}
@@ -40,9 +40,10 @@ type JAVFREE struct {
 }
 
 func New() *JAVFREE {
+	s := scraper.NewDefaultScraper(Name, baseURL, Priority, language.Japanese)
 	return &JAVFREE{
-		Fetcher: fetch.Default(&fetch.Config{Referer: baseURL}),
-		Scraper: scraper.NewDefaultScraper(Name, baseURL, Priority, language.Japanese),
+		Fetcher: fetch.Default(&fetch.Config{Referer: baseURL, Proxy: s.Proxy()}),
+		Scraper: s,
 	}
 }
 
@@ -38,6 +38,7 @@ const (
 	movieURL  = "https://www.mgstage.com/product/product_detail/%s/"
 	searchURL = "https://www.mgstage.com/search/cSearch.php?search_word=%s"
 	sampleURL = "https://www.mgstage.com/sampleplayer/sampleRespons.php?pid=%s"
+	loginURL  = "https://www.mgstage.com/login/login.php"
 )
 
 type MGS struct {
@@ -48,12 +49,36 @@ func New() *MGS {
 	return &MGS{scraper.NewDefaultScraper(
 		Name, baseURL, Priority,
 		language.Japanese,
-		scraper.WithCookies(baseURL, []*http.Cookie{
-			{Name: "adc", Value: "1"},
-		}),
+		scraper.WithCookies(baseURL, ageCheckCookies()),
+		scraper.WithSession(login, isSessionExpired),
 	)}
 }
 
+func ageCheckCookies() []*http.Cookie {
+	return []*http.Cookie{
+		{Name: "adc", Value: "1"},
+	}
+}
+
+// login passes the age check again, and logs in to the member account
+// if the credentials are set.
+func login(c *colly.Collector, username, password string) error {
+	if err := c.SetCookies(baseURL, ageCheckCookies()); err != nil {
+		return err
+	}
+	if username == "" || password == "" {
+		return nil
+	}
+	return scraper.LoginForm(c, loginURL, username, password)
+}
+
+// isSessionExpired reports whether the request is redirected to the
+// age check page, or to the login page if the member session expired.
+func isSessionExpired(resp *http.Response) bool {
+	location := resp.Header.Get("Location")
+	return strings.Contains(location, "agecheck") || strings.Contains(location, "/login/")
+}
+
 func (mgs *MGS) GetMovieReviewsByID(id string) (reviews []*model.MovieReviewDetail, err error) {
 	c := mgs.ClonedCollector()
 
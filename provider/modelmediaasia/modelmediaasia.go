@@ -51,9 +51,10 @@ type ModelMediaAsia struct {
 }
 
 func New() *ModelMediaAsia {
+	s := scraper.NewDefaultScraper(Name, baseURL, Priority, language.Chinese)
 	return &ModelMediaAsia{
-		Fetcher: fetch.Default(&fetch.Config{Referer: baseURL}),
-		Scraper: scraper.NewDefaultScraper(Name, baseURL, Priority, language.Chinese),
+		Fetcher: fetch.Default(&fetch.Config{Referer: baseURL, Proxy: s.Proxy()}),
+		Scraper: s,
 	}
 }
 
@@ -11,6 +11,7 @@ import (
 var (
 	_ provider.MovieProvider = (*MuraMura)(nil)
 	_ provider.MovieReviewer = (*MuraMura)(nil)
+	_ provider.ReleaseFeeder = (*MuraMura)(nil)
 )
 
 const (
@@ -10,6 +10,7 @@ import (
 var (
 	_ provider.MovieProvider = (*Pacopacomama)(nil)
 	_ provider.MovieReviewer = (*Pacopacomama)(nil)
+	_ provider.ReleaseFeeder = (*Pacopacomama)(nil)
 )
 
 const (
@@ -7,6 +7,7 @@ import (
 
 	"golang.org/x/text/language"
 
+	"github.com/metatube-community/metatube-sdk-go/common/proxy"
 	"github.com/metatube-community/metatube-sdk-go/model"
 )
 
@@ -82,6 +83,31 @@ type ActorProvider interface {
 	GetActorInfoByURL(url string) (*model.ActorInfo, error)
 }
 
+type ActorFilmographer interface {
+	// GetActorFilmography gets the movies of given actor id by page,
+	// page starts from 1, and an empty result means no more pages.
+	GetActorFilmography(id string, page int) ([]*model.MovieSearchResult, error)
+}
+
+// Movie collection types for browsing.
+const (
+	MakerCollection  = "maker"
+	LabelCollection  = "label"
+	SeriesCollection = "series"
+)
+
+type MovieBrowser interface {
+	// BrowseMovies lists the movies of given collection type and id by
+	// page, page starts from 1, and an empty result means no more pages.
+	BrowseMovies(collection, id string, page int) ([]*model.MovieSearchResult, error)
+}
+
+type ReleaseFeeder interface {
+	// GetReleaseFeed gets the recently released or upcoming movies by page,
+	// newest first, page starts from 1, and an empty result means no more pages.
+	GetReleaseFeed(page int) ([]*model.MovieSearchResult, error)
+}
+
 type Fetcher interface {
 	// Fetch fetches media resources from url.
 	Fetch(url string) (*http.Response, error)
@@ -92,6 +118,59 @@ type RequestTimeoutSetter interface {
 	SetRequestTimeout(timeout time.Duration)
 }
 
+type RequestTimeoutGetter interface {
+	// RequestTimeout returns timeout for HTTP requests, 0 means default.
+	RequestTimeout() time.Duration
+}
+
+type ProxySetter interface {
+	// SetProxy sets the proxy pool for HTTP requests, nil means no proxy.
+	SetProxy(pool *proxy.Pool)
+}
+
+type MirrorSetter interface {
+	// SetMirrors sets extra mirror base URLs, which are
+	// switched to automatically when the current one fails.
+	SetMirrors(mirrors []string) error
+}
+
+type MirrorGetter interface {
+	// Mirrors returns all base URLs, the primary one first.
+	Mirrors() []*url.URL
+
+	// PreferredURL rewrites the URL of any base URL to the working mirror.
+	PreferredURL(rawURL string) string
+}
+
+type ResourceHostsGetter interface {
+	// ResourceHosts returns extra hosts of the provider resources,
+	// e.g. image CDNs, which share the rate limits of the provider.
+	ResourceHosts() []string
+}
+
+type SessionStore interface {
+	// LoadCookies loads the persisted session cookies of the provider.
+	LoadCookies(name string) ([]*http.Cookie, error)
+
+	// SaveCookies persists the session cookies of the provider.
+	SaveCookies(name string, cookies []*http.Cookie) error
+}
+
+type SessionSetter interface {
+	// SetSessionStore sets the store to persist session cookies.
+	SetSessionStore(store SessionStore)
+
+	// SetCredentials sets the credentials to log in.
+	SetCredentials(username, password string)
+
+	// SetSessionCookies sets the session cookies directly.
+	SetSessionCookies(cookies []*http.Cookie)
+
+	// SessionEnabled reports whether the provider handles sessions,
+	// providers may implement the setters without using them.
+	SessionEnabled() bool
+}
+
 type Config interface {
 	Has(string) bool
 	GetString(string) (string, error)
@@ -47,9 +47,10 @@ type SOD struct {
 }
 
 func New() *SOD {
+	s := scraper.NewDefaultScraper(Name, baseURL, Priority, language.Japanese)
 	return &SOD{
-		Fetcher: fetch.Default(&fetch.Config{Referer: baseURL}),
-		Scraper: scraper.NewDefaultScraper(Name, baseURL, Priority, language.Japanese),
+		Fetcher: fetch.Default(&fetch.Config{Referer: baseURL, Proxy: s.Proxy()}),
+		Scraper: s,
 	}
 }
 
@@ -10,6 +10,7 @@ import (
 	"strconv"
 
 	"github.com/gocolly/colly/v2"
+	"go.uber.org/atomic"
 	"golang.org/x/text/language"
 
 	"github.com/metatube-community/metatube-sdk-go/model"
@@ -18,8 +19,10 @@ import (
 )
 
 var (
-	_ provider.ActorProvider = (*ThePornDBActor)(nil)
-	_ provider.ActorSearcher = (*ThePornDBActor)(nil)
+	_ provider.ActorProvider     = (*ThePornDBActor)(nil)
+	_ provider.ActorSearcher     = (*ThePornDBActor)(nil)
+	_ provider.ActorFilmographer = (*ThePornDBActor)(nil)
+	_ provider.ConfigSetter      = (*ThePornDBActor)(nil)
 )
 
 const (
@@ -28,24 +31,27 @@ const (
 	actorPageURL      = "https://theporndb.net/performers/%s"
 	apiGetActorURL    = "https://api.theporndb.net/performers/%s"
 	apiSearchActorURL = "https://api.theporndb.net/performers?q=%s"
+	apiActorScenesURL = "https://api.theporndb.net/performers/%s/scenes?page=%d"
 )
 
 type ThePornDBActor struct {
 	*scraper.Scraper
 
-	accessToken string
+	// accessToken may be changed at runtime by SetConfig.
+	accessToken *atomic.String
 }
 
 func NewThePornDBActor() *ThePornDBActor {
 	return &ThePornDBActor{
 		Scraper:     scraper.NewDefaultScraper(ActorProviderName, actorBaseURL, Priority, language.English),
-		accessToken: "",
+		accessToken: atomic.NewString(""),
 	}
 }
 
-func (s *ThePornDBActor) SetConfig(config map[string]string) error {
-	if accessToken, ok := config["ACCESS_TOKEN"]; ok {
-		s.accessToken = accessToken
+// SetConfig impls ConfigSetter.SetConfig.
+func (s *ThePornDBActor) SetConfig(config provider.Config) error {
+	if accessToken, err := config.GetString(accessTokenConfigKey); err == nil {
+		s.accessToken.Store(accessToken)
 	}
 	return nil
 }
@@ -61,7 +67,7 @@ func (s *ThePornDBActor) ParseActorIDFromURL(rawURL string) (string, error) {
 
 // GetActorInfoByID impls ActorProvider.GetActorInfoByID.
 func (s *ThePornDBActor) GetActorInfoByID(id string) (info *model.ActorInfo, err error) {
-	if s.accessToken == "" {
+	if s.accessToken.Load() == "" {
 		return nil, nil
 	}
 
@@ -118,7 +124,7 @@ func (s *ThePornDBActor) GetActorInfoByID(id string) (info *model.ActorInfo, err
 	})
 
 	headers := http.Header{}
-	headers.Set("Authorization", fmt.Sprintf("Bearer %s", s.accessToken))
+	headers.Set("Authorization", fmt.Sprintf("Bearer %s", s.accessToken.Load()))
 	err = c.Request(http.MethodGet, fmt.Sprintf(apiGetActorURL, id), nil, nil, headers)
 	return
 }
@@ -135,7 +141,7 @@ func (s *ThePornDBActor) GetActorInfoByURL(rawURL string) (*model.ActorInfo, err
 
 // SearchActor impls ActorSearcher.SearchActor.
 func (s *ThePornDBActor) SearchActor(keyword string) (results []*model.ActorSearchResult, err error) {
-	if s.accessToken == "" {
+	if s.accessToken.Load() == "" {
 		return nil, nil
 	}
 
@@ -174,11 +180,53 @@ func (s *ThePornDBActor) SearchActor(keyword string) (results []*model.ActorSear
 	})
 
 	headers := http.Header{}
-	headers.Set("Authorization", fmt.Sprintf("Bearer %s", s.accessToken))
+	headers.Set("Authorization", fmt.Sprintf("Bearer %s", s.accessToken.Load()))
 	err = c.Request(http.MethodGet, fmt.Sprintf(apiSearchActorURL, url.QueryEscape(keyword)), nil, nil, headers)
 	return
 }
 
+// GetActorFilmography impls ActorFilmographer.GetActorFilmography,
+// the results are scenes of the ThePornDBScene provider.
+func (s *ThePornDBActor) GetActorFilmography(id string, page int) (results []*model.MovieSearchResult, err error) {
+	if s.accessToken.Load() == "" {
+		return nil, nil
+	}
+	if page < 1 {
+		page = 1
+	}
+
+	c := s.ClonedCollector()
+
+	c.OnResponse(func(r *colly.Response) {
+		resp := &searchVideosResponse{}
+		if err = json.Unmarshal(r.Body, resp); err != nil {
+			return
+		}
+		for _, video := range resp.Data {
+			releaseDate, _ := video.ReleaseDate()
+			result := &model.MovieSearchResult{
+				ID:          video.Slug,
+				Number:      video.Slug,
+				Title:       video.Title,
+				Provider:    SceneProviderName,
+				Homepage:    fmt.Sprintf(scenePageURL, video.Slug),
+				ThumbURL:    video.Poster,
+				CoverURL:    video.Image,
+				ReleaseDate: releaseDate,
+			}
+			for _, performer := range video.Performers {
+				result.Actors = append(result.Actors, performer.Name)
+			}
+			results = append(results, result)
+		}
+	})
+
+	headers := http.Header{}
+	headers.Set("Authorization", fmt.Sprintf("Bearer %s", s.accessToken.Load()))
+	err = c.Request(http.MethodGet, fmt.Sprintf(apiActorScenesURL, url.PathEscape(id), page), nil, nil, headers)
+	return
+}
+
 var chestSizeRE = regexp.MustCompile(`^(\d+)([A-Z])$`)
 
 func parseChestSize(s string) (int, string, error) {
@@ -11,6 +11,9 @@ import (
 
 const Priority = 1000
 
+// accessTokenConfigKey is the config key of the API token.
+const accessTokenConfigKey = "access_token"
+
 func init() {
 	provider.Register(SceneProviderName, NewThePornDBScene)
 	provider.Register(MovieProviderName, NewThePornDBMovie)
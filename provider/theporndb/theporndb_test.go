@@ -16,7 +16,7 @@ func TestThePornDBVideo_GetMovieInfoByID(t *testing.T) {
 	}
 	testkit.Test(t, func() *ThePornDBVideo {
 		res := NewThePornDBScene()
-		res.accessToken = accessToken
+		res.accessToken.Store(accessToken)
 		return res
 	}, []string{
 		"i-want-clips-leaking-into-debt",
@@ -26,7 +26,7 @@ func TestThePornDBVideo_GetMovieInfoByID(t *testing.T) {
 
 	testkit.Test(t, func() *ThePornDBVideo {
 		res := NewThePornDBMovie()
-		res.accessToken = accessToken
+		res.accessToken.Store(accessToken)
 		return res
 	}, []string{
 		"digital-sin-sisterly-love-4",
@@ -41,7 +41,7 @@ func TestThePornDBVideo_GetMovieInfoByURL(t *testing.T) {
 	}
 	testkit.Test(t, func() *ThePornDBVideo {
 		res := NewThePornDBScene()
-		res.accessToken = accessToken
+		res.accessToken.Store(accessToken)
 		return res
 	}, []string{
 		sceneBaseURL + "i-want-clips-leaking-into-debt",
@@ -51,7 +51,7 @@ func TestThePornDBVideo_GetMovieInfoByURL(t *testing.T) {
 
 	testkit.Test(t, func() *ThePornDBVideo {
 		res := NewThePornDBMovie()
-		res.accessToken = accessToken
+		res.accessToken.Store(accessToken)
 		return res
 	}, []string{
 		movieBaseURL + "digital-sin-sisterly-love-4",
@@ -66,7 +66,7 @@ func TestThePornDBVideo_SearchMovie(t *testing.T) {
 	}
 	testkit.Test(t, func() *ThePornDBVideo {
 		res := NewThePornDBScene()
-		res.accessToken = accessToken
+		res.accessToken.Store(accessToken)
 		return res
 	}, []string{
 		"The Three Evil Dragon",
@@ -76,7 +76,7 @@ func TestThePornDBVideo_SearchMovie(t *testing.T) {
 
 	testkit.Test(t, func() *ThePornDBVideo {
 		res := NewThePornDBMovie()
-		res.accessToken = accessToken
+		res.accessToken.Store(accessToken)
 		return res
 	}, []string{
 		"Sisterly Love 4",
@@ -91,7 +91,7 @@ func TestThePornDBActor_GetActorInfoByID(t *testing.T) {
 	}
 	testkit.Test(t, func() *ThePornDBActor {
 		res := NewThePornDBActor()
-		res.accessToken = accessToken
+		res.accessToken.Store(accessToken)
 		return res
 	}, []string{
 		"adf8435e-d5df-42b9-b46b-8440dee5a271",
@@ -106,7 +106,7 @@ func TestThePornDBActor_GetActorInfoByURL(t *testing.T) {
 	}
 	testkit.Test(t, func() *ThePornDBActor {
 		res := NewThePornDBActor()
-		res.accessToken = accessToken
+		res.accessToken.Store(accessToken)
 		return res
 	}, []string{
 		actorBaseURL + "adf8435e-d5df-42b9-b46b-8440dee5a271",
@@ -121,7 +121,7 @@ func TestThePornDBActor_SearchActor(t *testing.T) {
 	}
 	testkit.Test(t, func() *ThePornDBActor {
 		res := NewThePornDBActor()
-		res.accessToken = accessToken
+		res.accessToken.Store(accessToken)
 		return res
 	}, []string{
 		"Harley",
@@ -130,3 +130,16 @@ func TestThePornDBActor_SearchActor(t *testing.T) {
 		"adf8435e-d5df-42b9-b46b-8440dee5a271",
 	})
 }
+
+func TestThePornDBActor_GetActorFilmography(t *testing.T) {
+	if accessToken == "" {
+		t.Skip("MT_THEPORNDB_ACCESS_TOKEN is not set")
+	}
+	testkit.Test(t, func() *ThePornDBActor {
+		res := NewThePornDBActor()
+		res.accessToken.Store(accessToken)
+		return res
+	}, []string{
+		"harley-king",
+	})
+}
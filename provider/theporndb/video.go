@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/gocolly/colly/v2"
+	"go.uber.org/atomic"
 	"golang.org/x/text/language"
 
 	"github.com/metatube-community/metatube-sdk-go/common/number"
@@ -20,6 +21,7 @@ import (
 var (
 	_ provider.MovieProvider = (*ThePornDBVideo)(nil)
 	_ provider.MovieSearcher = (*ThePornDBVideo)(nil)
+	_ provider.ConfigSetter  = (*ThePornDBVideo)(nil)
 )
 
 const (
@@ -49,7 +51,8 @@ type ThePornDBVideo struct {
 	apiGetURL    string
 	apiSearchURL string
 
-	accessToken string
+	// accessToken may be changed at runtime by SetConfig.
+	accessToken *atomic.String
 }
 
 func new(name, baseURL, pageURL, apiGetURL, apiSearchURL string) *ThePornDBVideo {
@@ -58,7 +61,7 @@ func new(name, baseURL, pageURL, apiGetURL, apiSearchURL string) *ThePornDBVideo
 		pageURL:      pageURL,
 		apiGetURL:    apiGetURL,
 		apiSearchURL: apiSearchURL,
-		accessToken:  "",
+		accessToken:  atomic.NewString(""),
 	}
 }
 
@@ -70,17 +73,17 @@ func NewThePornDBMovie() *ThePornDBVideo {
 	return new(MovieProviderName, movieBaseURL, moviePageURL, apiGetMovieURL, apiSearchMovieURL)
 }
 
-func (s *ThePornDBVideo) SetConfig(config map[string]string) error {
-	if accessToken, ok := config["ACCESS_TOKEN"]; ok {
-		fmt.Println(s.Name(), "set token")
-		s.accessToken = accessToken
+// SetConfig impls ConfigSetter.SetConfig.
+func (s *ThePornDBVideo) SetConfig(config provider.Config) error {
+	if accessToken, err := config.GetString(accessTokenConfigKey); err == nil {
+		s.accessToken.Store(accessToken)
 	}
 	return nil
 }
 
 // GetMovieInfoByID impls MovieProvider.GetMovieInfoByID.
 func (s *ThePornDBVideo) GetMovieInfoByID(id string) (info *model.MovieInfo, err error) {
-	if s.accessToken == "" {
+	if s.accessToken.Load() == "" {
 		return nil, nil
 	}
 
@@ -129,7 +132,7 @@ func (s *ThePornDBVideo) GetMovieInfoByID(id string) (info *model.MovieInfo, err
 	})
 
 	headers := http.Header{}
-	headers.Set("Authorization", fmt.Sprintf("Bearer %s", s.accessToken))
+	headers.Set("Authorization", fmt.Sprintf("Bearer %s", s.accessToken.Load()))
 	err = c.Request(http.MethodGet, fmt.Sprintf(s.apiGetURL, id), nil, nil, headers)
 	return
 }
@@ -163,7 +166,7 @@ func (s *ThePornDBVideo) NormalizeMovieKeyword(keyword string) string {
 
 // SearchMovie impls MovieSearcher.SearchMovie.
 func (s *ThePornDBVideo) SearchMovie(keyword string) (results []*model.MovieSearchResult, err error) {
-	if s.accessToken == "" {
+	if s.accessToken.Load() == "" {
 		return nil, nil
 	}
 
@@ -190,7 +193,7 @@ func (s *ThePornDBVideo) SearchMovie(keyword string) (results []*model.MovieSear
 	})
 
 	headers := http.Header{}
-	headers.Set("Authorization", fmt.Sprintf("Bearer %s", s.accessToken))
+	headers.Set("Authorization", fmt.Sprintf("Bearer %s", s.accessToken.Load()))
 	err = c.Request(http.MethodGet, fmt.Sprintf(s.apiSearchURL, url.QueryEscape(keyword)), nil, nil, headers)
 	return
 }
@@ -12,7 +12,7 @@ import (
 
 func authentication(v auth.Validator) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		if v != nil /* auth enabled */ {
+		if auth.Enabled(v) {
 			header := c.GetHeader("Authorization")
 			bearer, token, found := strings.Cut(header, " ")
 
@@ -26,3 +26,15 @@ func authentication(v auth.Validator) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// requireToken rejects the requests if no token is configured, it's
+// used by the admin and mutating routes, which must never be public.
+func requireToken(v auth.Validator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !auth.Enabled(v) {
+			abortWithError(c, errors.New(http.StatusForbidden, "token is required for this API"))
+			return
+		}
+		c.Next()
+	}
+}
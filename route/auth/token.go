@@ -1,8 +1,13 @@
 package auth
 
+import (
+	"go.uber.org/atomic"
+)
+
 var (
 	_ Validator = (*Token)(nil)
 	_ Validator = (*TokenStore)(nil)
+	_ Validator = (*AtomicToken)(nil)
 )
 
 type Token string
@@ -33,3 +38,28 @@ func (store TokenStore) Valid(token string) (ok bool) {
 	_, ok = store[token]
 	return
 }
+
+// AtomicToken is a Token which can be changed at runtime, an empty
+// token disables the auth.
+type AtomicToken struct {
+	token atomic.String
+}
+
+func NewAtomicToken(token string) *AtomicToken {
+	t := &AtomicToken{}
+	t.Set(token)
+	return t
+}
+
+func (t *AtomicToken) Set(token string) {
+	t.token.Store(token)
+}
+
+func (t *AtomicToken) Enabled() bool {
+	return t.token.Load() != ""
+}
+
+func (t *AtomicToken) Valid(token string) bool {
+	v := t.token.Load()
+	return v != "" && v == token
+}
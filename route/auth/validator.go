@@ -3,3 +3,12 @@ package auth
 type Validator interface {
 	Valid(string) bool
 }
+
+// Enabled reports whether the validator requires a token. A nil
+// validator, or an AtomicToken without a token, disables the auth.
+func Enabled(v Validator) bool {
+	if e, ok := v.(interface{ Enabled() bool }); ok {
+		return e.Enabled()
+	}
+	return v != nil
+}
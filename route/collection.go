@@ -0,0 +1,126 @@
+package route
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/metatube-community/metatube-sdk-go/engine"
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+const (
+	defaultPageSize = 50
+	// maxBrowsePageSize limits the movies browsed from providers at a
+	// time, as a page may take several requests to the provider.
+	maxBrowsePageSize = 100
+)
+
+type pageQuery struct {
+	Page int `form:"page" binding:"min=1"`
+	Size int `form:"size" binding:"min=1,max=500"`
+}
+
+// browseQuery is a pageQuery whose size is clamped to maxBrowsePageSize
+// rather than rejected.
+type browseQuery struct {
+	Page int `form:"page" binding:"min=1"`
+	Size int `form:"size" binding:"min=1"`
+}
+
+type collectionQuery struct {
+	pageQuery
+	Q     string `form:"q"`
+	Maker string `form:"maker"`
+}
+
+type collectionMoviesQuery struct {
+	pageQuery
+	Name  string `form:"name" binding:"required"`
+	Maker string `form:"maker"`
+}
+
+type collectionUri struct {
+	infoUri // same as info uri
+}
+
+func getMakers(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := &collectionQuery{
+			pageQuery: pageQuery{Page: 1, Size: defaultPageSize},
+		}
+		if err := c.ShouldBindQuery(query); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+		makers, err := app.ListMakers(query.Q, query.Page, query.Size)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, &responseMessage{Data: makers})
+	}
+}
+
+func getSeries(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := &collectionQuery{
+			pageQuery: pageQuery{Page: 1, Size: defaultPageSize},
+		}
+		if err := c.ShouldBindQuery(query); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+		series, err := app.ListSeries(query.Q, query.Maker, query.Page, query.Size)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, &responseMessage{Data: series})
+	}
+}
+
+// getCollectionMovies lists the saved movies of the collection by name.
+func getCollectionMovies(app *engine.Engine, collection string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := &collectionMoviesQuery{
+			pageQuery: pageQuery{Page: 1, Size: defaultPageSize},
+		}
+		if err := c.ShouldBindQuery(query); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+		results, err := app.ListCollectionMovies(collection, query.Name, query.Maker, query.Page, query.Size)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, &responseMessage{Data: results})
+	}
+}
+
+// browseCollectionMovies lists the movies of the collection from provider by id.
+func browseCollectionMovies(app *engine.Engine, collection string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := &collectionUri{}
+		if err := c.ShouldBindUri(uri); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+		query := &browseQuery{Page: 1, Size: defaultPageSize}
+		if err := c.ShouldBindQuery(query); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+		results, err := app.BrowseMovies(collection, uri.AsProviderID(), query.Page, min(query.Size, maxBrowsePageSize))
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		if results == nil {
+			// empty page means no more results.
+			results = []*model.MovieSearchResult{}
+		}
+		c.JSON(http.StatusOK, &responseMessage{Data: results})
+	}
+}
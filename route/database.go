@@ -1,11 +1,17 @@
 package route
 
 import (
+	"encoding/json"
+	goerr "errors"
+	"io"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/metatube-community/metatube-sdk-go/engine"
+	"github.com/metatube-community/metatube-sdk-go/engine/dbengine"
+	"github.com/metatube-community/metatube-sdk-go/errors"
 )
 
 func getDBVersion(app *engine.Engine) gin.HandlerFunc {
@@ -22,3 +28,144 @@ func getDBVersion(app *engine.Engine) gin.HandlerFunc {
 		})
 	}
 }
+
+type dbSearchQuery struct {
+	Q        string `form:"q" binding:"required"`
+	Provider string `form:"provider"`
+	Limit    int    `form:"limit" binding:"min=1,max=100"`
+	Offset   int    `form:"offset" binding:"min=0"`
+}
+
+func getDBSearch(app *engine.Engine, typ searchType) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := &dbSearchQuery{
+			Limit: 20,
+		}
+		if err := c.ShouldBindQuery(query); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
+		var (
+			results any
+			err     error
+		)
+		switch typ {
+		case actorSearchType:
+			results, err = app.SearchActorFromDB(query.Q, query.Provider, query.Limit, query.Offset)
+		case movieSearchType:
+			results, err = app.SearchMovieFromDB(query.Q, query.Provider, query.Limit, query.Offset)
+		default:
+			panic("invalid search type")
+		}
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, &responseMessage{Data: results})
+	}
+}
+
+// ndjsonContentType is the content type of the export and import progress.
+const ndjsonContentType = "application/x-ndjson"
+
+type dbExportQuery struct {
+	Tables   []string `form:"tables" binding:"dive,oneof=movie_metadata actor_metadata movie_reviews movie_overrides actor_overrides"`
+	Provider string   `form:"provider"`
+	// Since is either a date or an RFC 3339 time.
+	Since string `form:"since"`
+}
+
+func getDBExport(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := &dbExportQuery{}
+		if err := c.ShouldBindQuery(query); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+		var since time.Time
+		if query.Since != "" {
+			var err error
+			if since, err = dbengine.ParseSince(query.Since); err != nil {
+				abortWithStatusMessage(c, http.StatusBadRequest, err)
+				return
+			}
+		}
+
+		c.Header("Content-Type", ndjsonContentType)
+		c.Header("Content-Disposition", `attachment; filename="metatube.ndjson"`)
+		c.Status(http.StatusOK)
+		if _, err := app.ExportDB(c.Writer, dbengine.ExportOptions{
+			Tables:       query.Tables,
+			Provider:     query.Provider,
+			UpdatedSince: since,
+		}); err != nil {
+			// the response is partially written already, which
+			// is rejected by import as the trailer is missing.
+			_ = c.Error(err)
+		}
+	}
+}
+
+type dbImportQuery struct {
+	Policy string `form:"policy" binding:"oneof=overwrite keep-newer skip"`
+}
+
+// postDBImport imports the NDJSON export. If the client accepts NDJSON,
+// the progress is streamed as lines once the input is verified, and the
+// last line is the result.
+func postDBImport(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := &dbImportQuery{
+			Policy: string(dbengine.ConflictOverwrite),
+		}
+		if err := c.ShouldBindQuery(query); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
+		opts := dbengine.ImportOptions{
+			Policy: dbengine.ConflictPolicy(query.Policy),
+		}
+		var encoder *json.Encoder
+		if c.NegotiateFormat(gin.MIMEJSON, ndjsonContentType) == ndjsonContentType {
+			opts.Progress = func(progress dbengine.ImportProgress) {
+				if encoder == nil {
+					c.Header("Content-Type", ndjsonContentType)
+					c.Status(http.StatusOK)
+					encoder = json.NewEncoder(c.Writer)
+				}
+				_ = encoder.Encode(&responseMessage{Data: &progress})
+				c.Writer.Flush()
+			}
+		}
+
+		progress, err := app.ImportDB(c.Request.Body, opts)
+		if err != nil {
+			code := http.StatusInternalServerError
+			if goerr.Is(err, dbengine.ErrTruncatedInput) ||
+				goerr.Is(err, dbengine.ErrTrailerMismatch) ||
+				goerr.Is(err, io.ErrUnexpectedEOF) {
+				code = http.StatusBadRequest
+			}
+			// with the progress, as the batches before are imported.
+			resp := &responseMessage{
+				Data:  progress,
+				Error: errors.New(code, err.Error()),
+			}
+			if encoder != nil {
+				// the status is sent already.
+				_ = encoder.Encode(resp)
+				_ = c.Error(err)
+				return
+			}
+			c.AbortWithStatusJSON(code, resp)
+			return
+		}
+		if encoder != nil {
+			_ = encoder.Encode(&responseMessage{Data: progress})
+			return
+		}
+		c.JSON(http.StatusOK, &responseMessage{Data: progress})
+	}
+}
@@ -0,0 +1,56 @@
+package route
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/metatube-community/metatube-sdk-go/engine"
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+type filmographyUri struct {
+	infoUri // same as info uri
+}
+
+type filmographyQuery struct {
+	Page int  `form:"page" binding:"min=1"`
+	All  bool `form:"all"`
+}
+
+func getActorFilmography(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := &filmographyUri{}
+		if err := c.ShouldBindUri(uri); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+		query := &filmographyQuery{
+			Page: 1, // first page by default.
+		}
+		if err := c.ShouldBindQuery(query); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
+		var (
+			results []*model.MovieSearchResult
+			err     error
+		)
+		if query.All {
+			results, err = app.GetActorFilmographyAll(uri.AsProviderID(), query.Page)
+		} else {
+			results, err = app.GetActorFilmography(uri.AsProviderID(), query.Page)
+		}
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		if results == nil {
+			// empty page means no more results.
+			results = []*model.MovieSearchResult{}
+		}
+
+		c.JSON(http.StatusOK, &responseMessage{Data: results})
+	}
+}
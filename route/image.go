@@ -68,14 +68,17 @@ func getImage(app *engine.Engine, typ imageType) gin.HandlerFunc {
 
 		// TODO: how to handle providers that implement
 		//   both actor and movie provider interfaces?
-		var isActorProvider bool
-		switch {
-		case app.IsActorProvider(uri.Provider):
-			isActorProvider = true
-		case app.IsMovieProvider(uri.Provider):
-			isActorProvider = false
-		default:
-			abortWithError(c, mt.ErrProviderNotFound)
+		var (
+			provider        mt.Provider
+			isActorProvider bool
+		)
+		// the provider may be disabled at runtime, so it's got only once.
+		if p, err := app.GetActorProviderByName(uri.Provider); err == nil {
+			provider, isActorProvider = p, true
+		} else if p, err := app.GetMovieProviderByName(uri.Provider); err == nil {
+			provider = p
+		} else {
+			abortWithError(c, err)
 			return
 		}
 
@@ -84,12 +87,6 @@ func getImage(app *engine.Engine, typ imageType) gin.HandlerFunc {
 			err error
 		)
 		if query.URL != "" /* specified URL */ {
-			var provider mt.Provider
-			if isActorProvider {
-				provider = app.MustGetActorProviderByName(uri.Provider)
-			} else {
-				provider = app.MustGetMovieProviderByName(uri.Provider)
-			}
 			// query.Ratio should apply only to the primary images.
 			if typ != primaryImageType || query.Ratio < 0 {
 				query.Ratio = ratio
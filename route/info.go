@@ -2,11 +2,13 @@ package route
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/metatube-community/metatube-sdk-go/engine"
 	"github.com/metatube-community/metatube-sdk-go/engine/providerid"
+	"github.com/metatube-community/metatube-sdk-go/model"
 )
 
 type infoType uint8
@@ -45,14 +47,21 @@ func getInfo(app *engine.Engine, typ infoType) gin.HandlerFunc {
 		}
 
 		var (
-			info any
-			err  error
+			info      any
+			freshness engine.Freshness
+			err       error
 		)
 		switch typ {
 		case actorInfoType:
-			info, err = app.GetActorInfoByProviderID(uri.AsProviderID(), query.Lazy)
+			var actor *model.ActorInfo
+			if actor, err = app.GetActorInfoByProviderID(uri.AsProviderID(), query.Lazy); err == nil {
+				info, freshness = actor, app.ActorInfoFreshness(actor)
+			}
 		case movieInfoType:
-			info, err = app.GetMovieInfoByProviderID(uri.AsProviderID(), query.Lazy)
+			var movie *model.MovieInfo
+			if movie, err = app.GetMovieInfoByProviderID(uri.AsProviderID(), query.Lazy); err == nil {
+				info, freshness = movie, app.MovieInfoFreshness(movie)
+			}
 		default:
 			panic("invalid info/metadata type")
 		}
@@ -60,6 +69,19 @@ func getInfo(app *engine.Engine, typ infoType) gin.HandlerFunc {
 			abortWithError(c, err)
 			return
 		}
+		setFreshnessHeaders(c, freshness)
 		c.JSON(http.StatusOK, &responseMessage{Data: info})
 	}
 }
+
+// setFreshnessHeaders sets the age of the info in seconds, and whether
+// it is stale, i.e. being refreshed in the background. The standard Age
+// header is not used, as it means the age in HTTP caches to proxies.
+func setFreshnessHeaders(c *gin.Context, freshness engine.Freshness) {
+	c.Header("X-Metadata-Age", strconv.Itoa(int(freshness.Age.Seconds())))
+	state := "fresh"
+	if freshness.Stale {
+		state = "stale"
+	}
+	c.Header("X-Metadata-Freshness", state)
+}
@@ -0,0 +1,116 @@
+package route
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+	"github.com/metatube-community/metatube-sdk-go/task"
+)
+
+type jobUri struct {
+	Name string `uri:"name" binding:"required"`
+}
+
+type jobRunUri struct {
+	jobUri
+	ID uint64 `uri:"id" binding:"required"`
+}
+
+type jobRunsQuery struct {
+	Limit  int `form:"limit" binding:"min=1,max=100"`
+	Offset int `form:"offset" binding:"min=0"`
+}
+
+type jobAction uint8
+
+const (
+	triggerJobAction jobAction = iota
+	pauseJobAction
+	resumeJobAction
+)
+
+func getJobs(queue *task.Queue) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		jobs, err := queue.Jobs()
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, &responseMessage{Data: jobs})
+	}
+}
+
+func getJobRuns(queue *task.Queue) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := &jobUri{}
+		if err := c.ShouldBindUri(uri); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+		query := &jobRunsQuery{
+			Limit: 20,
+		}
+		if err := c.ShouldBindQuery(query); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+		runs, err := queue.Runs(uri.Name, query.Limit, query.Offset)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, &responseMessage{Data: runs})
+	}
+}
+
+func postJobAction(queue *task.Queue, action jobAction) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := &jobUri{}
+		if err := c.ShouldBindUri(uri); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
+		var (
+			data any
+			err  error
+			code = http.StatusOK
+		)
+		switch action {
+		case triggerJobAction:
+			var run *model.JobRun
+			if run, err = queue.Trigger(uri.Name); err == nil {
+				data, code = run, http.StatusAccepted
+			}
+		case pauseJobAction:
+			data, err = queue.Pause(uri.Name)
+		case resumeJobAction:
+			data, err = queue.Resume(uri.Name)
+		default:
+			panic("invalid job action")
+		}
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		c.JSON(code, &responseMessage{Data: data})
+	}
+}
+
+func cancelJobRun(queue *task.Queue) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := &jobRunUri{}
+		if err := c.ShouldBindUri(uri); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+		run, err := queue.Cancel(uri.Name, uri.ID)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, &responseMessage{Data: run})
+	}
+}
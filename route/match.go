@@ -0,0 +1,150 @@
+package route
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/metatube-community/metatube-sdk-go/engine"
+)
+
+type matchQuery struct {
+	Filename  string  `form:"filename" binding:"required"`
+	Duration  int     `form:"duration" binding:"min=0"`
+	Threshold float64 `form:"threshold" binding:"min=0,max=1"`
+}
+
+func getMatch(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := &matchQuery{
+			Threshold: engine.DefaultMatchThreshold,
+		}
+		if err := c.ShouldBindQuery(query); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+		result, err := app.MatchMovie(query.Filename, query.Duration, query.Threshold)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, &responseMessage{Data: result})
+	}
+}
+
+type matchBatchBody struct {
+	Items       []string `json:"items" binding:"required,min=1"`
+	Threshold   *float64 `json:"threshold" binding:"omitempty,min=0,max=1"`
+	Concurrency int      `json:"concurrency" binding:"min=0"`
+}
+
+type matchJobUri struct {
+	ID string `uri:"id" binding:"required"`
+}
+
+type matchJobQuery struct {
+	Limit  int `form:"limit" binding:"min=1,max=1000"`
+	Offset int `form:"offset" binding:"min=0"`
+}
+
+// matchJobResultsPageSize is the number of items read at a time
+// when the results are written.
+const matchJobResultsPageSize = 500
+
+type matchJobResponse struct {
+	engine.MatchJobProgress
+	Items []engine.MatchJobItem `json:"items,omitempty"`
+}
+
+func postMatchBatch(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body := &matchBatchBody{}
+		if err := c.ShouldBindJSON(body); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+		threshold := engine.DefaultMatchThreshold
+		if body.Threshold != nil {
+			threshold = *body.Threshold
+		}
+		job, err := app.StartMatchJob(body.Items, threshold, body.Concurrency)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		c.JSON(http.StatusAccepted, &responseMessage{
+			Data: &matchJobResponse{MatchJobProgress: job.Progress()},
+		})
+	}
+}
+
+// getMatchBatch returns the job progress with a page of the completed items.
+func getMatchBatch(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := &matchJobUri{}
+		if err := c.ShouldBindUri(uri); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+		query := &matchJobQuery{
+			Limit: 100,
+		}
+		if err := c.ShouldBindQuery(query); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+		job, err := app.GetMatchJob(uri.ID)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		items, err := app.GetMatchJobItems(uri.ID, query.Limit, query.Offset)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, &responseMessage{
+			Data: &matchJobResponse{
+				MatchJobProgress: job.Progress(),
+				Items:            items,
+			},
+		})
+	}
+}
+
+// getMatchBatchResults writes the completed items as NDJSON.
+func getMatchBatchResults(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := &matchJobUri{}
+		if err := c.ShouldBindUri(uri); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+		items, err := app.GetMatchJobItems(uri.ID, matchJobResultsPageSize, 0)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		c.Header("Content-Type", ndjsonContentType)
+		c.Status(http.StatusOK)
+		encoder := json.NewEncoder(c.Writer)
+		for offset := 0; ; {
+			for _, item := range items {
+				if err = encoder.Encode(item); err != nil {
+					_ = c.Error(err)
+					return
+				}
+			}
+			if len(items) < matchJobResultsPageSize {
+				return
+			}
+			offset += len(items)
+			if items, err = app.GetMatchJobItems(uri.ID, matchJobResultsPageSize, offset); err != nil {
+				// the response is partially written already.
+				_ = c.Error(err)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package route
+
+import (
+	goerr "errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/metatube-community/metatube-sdk-go/engine"
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+type numberPrefixUri struct {
+	Prefix string `uri:"prefix" binding:"required,alphanum"`
+}
+
+type numberPrefixBody struct {
+	Digits    *int  `json:"digits" binding:"omitempty,min=1"`
+	MinSuffix *int  `json:"min_suffix" binding:"omitempty,min=0"`
+	MaxSuffix *int  `json:"max_suffix" binding:"omitempty,min=0"`
+	MaxMisses *int  `json:"max_misses" binding:"omitempty,min=0"`
+	Paused    *bool `json:"paused"`
+	Reset     bool  `json:"reset"`
+}
+
+func (b *numberPrefixBody) update() *engine.NumberPrefixUpdate {
+	return &engine.NumberPrefixUpdate{
+		Digits:    b.Digits,
+		MinSuffix: b.MinSuffix,
+		MaxSuffix: b.MaxSuffix,
+		MaxMisses: b.MaxMisses,
+		Paused:    b.Paused,
+		Reset:     b.Reset,
+	}
+}
+
+func getNumberPrefixes(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		prefixes, err := app.GetNumberPrefixes()
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, &responseMessage{Data: prefixes})
+	}
+}
+
+func getNumberPrefix(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := &numberPrefixUri{}
+		if err := c.ShouldBindUri(uri); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+		prefix, err := app.GetNumberPrefix(uri.Prefix)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, &responseMessage{Data: prefix})
+	}
+}
+
+func saveNumberPrefix(app *engine.Engine, create bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := &numberPrefixUri{}
+		if err := c.ShouldBindUri(uri); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+		body := &numberPrefixBody{}
+		// the body is optional on creation to use the defaults.
+		if err := c.ShouldBindJSON(body); err != nil && !(create && goerr.Is(err, io.EOF)) {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
+		var (
+			prefix *model.NumberStatus
+			err    error
+			code   = http.StatusOK
+		)
+		if create {
+			prefix, err = app.AddNumberPrefix(uri.Prefix, body.update())
+			code = http.StatusCreated
+		} else {
+			prefix, err = app.UpdateNumberPrefix(uri.Prefix, body.update())
+		}
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		c.JSON(code, &responseMessage{Data: prefix})
+	}
+}
+
+func deleteNumberPrefix(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := &numberPrefixUri{}
+		if err := c.ShouldBindUri(uri); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+		if err := app.DeleteNumberPrefix(uri.Prefix); err != nil {
+			abortWithError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
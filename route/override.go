@@ -0,0 +1,45 @@
+package route
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/metatube-community/metatube-sdk-go/engine"
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+// overrideInfo replaces (PUT) or merges (PATCH) the manual overrides
+// of the info, the body is a JSON object of the fields to override.
+func overrideInfo(app *engine.Engine, typ infoType, merge bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := &infoUri{}
+		if err := c.ShouldBindUri(uri); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+		fields := make(model.OverrideFields)
+		if err := c.ShouldBindJSON(&fields); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
+		var (
+			info any
+			err  error
+		)
+		switch typ {
+		case actorInfoType:
+			info, err = app.OverrideActorInfo(uri.AsProviderID(), fields, merge)
+		case movieInfoType:
+			info, err = app.OverrideMovieInfo(uri.AsProviderID(), fields, merge)
+		default:
+			panic("invalid info/metadata type")
+		}
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, &responseMessage{Data: info})
+	}
+}
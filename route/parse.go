@@ -0,0 +1,24 @@
+package route
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/metatube-community/metatube-sdk-go/common/number"
+)
+
+type parseQuery struct {
+	Filename string `form:"filename" binding:"required"`
+}
+
+func getParse() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := &parseQuery{}
+		if err := c.ShouldBindQuery(query); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+		c.JSON(http.StatusOK, &responseMessage{Data: number.Parse(query.Filename)})
+	}
+}
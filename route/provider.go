@@ -0,0 +1,64 @@
+package route
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/metatube-community/metatube-sdk-go/engine"
+)
+
+type providerUri struct {
+	Type string `uri:"type" binding:"required,oneof=actor movie"`
+	Name string `uri:"name" binding:"required"`
+}
+
+type providerUpdateBody struct {
+	Enabled  *bool             `json:"enabled"`
+	Priority *float64          `json:"priority"`
+	Timeout  string            `json:"timeout"`
+	Config   map[string]string `json:"config"`
+}
+
+func getProviderCapabilities(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, &responseMessage{Data: app.GetProviderCapabilities()})
+	}
+}
+
+func updateProvider(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := &providerUri{}
+		if err := c.ShouldBindUri(uri); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+		body := &providerUpdateBody{}
+		if err := c.ShouldBindJSON(body); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
+		update := &engine.ProviderUpdate{
+			Enabled:  body.Enabled,
+			Priority: body.Priority,
+			Config:   body.Config,
+		}
+		if body.Timeout != "" {
+			timeout, err := time.ParseDuration(body.Timeout)
+			if err != nil {
+				abortWithStatusMessage(c, http.StatusBadRequest, err)
+				return
+			}
+			update.Timeout = &timeout
+		}
+
+		capability, err := app.UpdateProvider(uri.Type, uri.Name, update)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, &responseMessage{Data: capability})
+	}
+}
@@ -0,0 +1,43 @@
+package route
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/metatube-community/metatube-sdk-go/common/ratelimit"
+)
+
+type rateLimitResponse struct {
+	// Hosts are all the hosts sharing the limits.
+	Hosts  []string         `json:"hosts"`
+	Config ratelimit.Config `json:"config"`
+	Stats  ratelimit.Stats  `json:"stats"`
+	// TotalWait is the total queueing delay in milliseconds.
+	TotalWait float64 `json:"total_wait_ms"`
+	// AvgWait is the average queueing delay in milliseconds.
+	AvgWait float64 `json:"avg_wait_ms"`
+	// MaxWait is the max queueing delay in milliseconds.
+	MaxWait float64 `json:"max_wait_ms"`
+}
+
+func getRateLimits() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		data := make(map[string]*rateLimitResponse)
+		for host, stats := range ratelimit.DefaultRegistry.Stats() {
+			config, _ := ratelimit.DefaultRegistry.Config(host)
+			resp := &rateLimitResponse{
+				Hosts:     ratelimit.DefaultRegistry.Hosts(host),
+				Config:    config,
+				Stats:     stats,
+				TotalWait: float64(stats.TotalWait.Microseconds()) / 1e3,
+				MaxWait:   float64(stats.MaxWait.Microseconds()) / 1e3,
+			}
+			if stats.Requests > 0 {
+				resp.AvgWait = float64(stats.TotalWait.Microseconds()) / 1e3 / float64(stats.Requests)
+			}
+			data[host] = resp
+		}
+		c.JSON(http.StatusOK, &responseMessage{Data: data})
+	}
+}
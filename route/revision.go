@@ -0,0 +1,82 @@
+package route
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/metatube-community/metatube-sdk-go/engine"
+)
+
+type revisionUri struct {
+	infoUri
+	Revision uint64 `uri:"revision" binding:"required"`
+}
+
+type revisionQuery struct {
+	Limit  int `form:"limit" binding:"min=1,max=100"`
+	Offset int `form:"offset" binding:"min=0"`
+}
+
+func getRevisions(app *engine.Engine, typ infoType) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := &infoUri{}
+		if err := c.ShouldBindUri(uri); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+		query := &revisionQuery{
+			Limit: 20,
+		}
+		if err := c.ShouldBindQuery(query); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
+		var (
+			revisions any
+			err       error
+		)
+		switch typ {
+		case actorInfoType:
+			revisions, err = app.GetActorInfoRevisions(uri.AsProviderID(), query.Limit, query.Offset)
+		case movieInfoType:
+			revisions, err = app.GetMovieInfoRevisions(uri.AsProviderID(), query.Limit, query.Offset)
+		default:
+			panic("invalid info/metadata type")
+		}
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, &responseMessage{Data: revisions})
+	}
+}
+
+func rollbackRevision(app *engine.Engine, typ infoType) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := &revisionUri{}
+		if err := c.ShouldBindUri(uri); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
+		var (
+			info any
+			err  error
+		)
+		switch typ {
+		case actorInfoType:
+			info, err = app.RollbackActorInfo(uri.AsProviderID(), uri.Revision)
+		case movieInfoType:
+			info, err = app.RollbackMovieInfo(uri.AsProviderID(), uri.Revision)
+		default:
+			panic("invalid info/metadata type")
+		}
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, &responseMessage{Data: info})
+	}
+}
@@ -12,10 +12,12 @@ import (
 	"github.com/metatube-community/metatube-sdk-go/engine"
 	"github.com/metatube-community/metatube-sdk-go/errors"
 	V "github.com/metatube-community/metatube-sdk-go/internal/version"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
 	"github.com/metatube-community/metatube-sdk-go/route/auth"
+	"github.com/metatube-community/metatube-sdk-go/task"
 )
 
-func New(app *engine.Engine, v auth.Validator) *gin.Engine {
+func New(app *engine.Engine, queue *task.Queue, v auth.Validator) *gin.Engine {
 	r := gin.New()
 	{
 		// support CORS
@@ -37,6 +39,7 @@ func New(app *engine.Engine, v auth.Validator) *gin.Engine {
 	{
 		system.GET("/modules", getModules())
 		system.GET("/providers", getProviders(app))
+		system.GET("/ratelimits", getRateLimits())
 	}
 
 	public := r.Group("/v1",
@@ -54,29 +57,92 @@ func New(app *engine.Engine, v auth.Validator) *gin.Engine {
 		}
 	}
 
+	// Videos are public for players, but must not be cached, as
+	// the proxied URLs in playlists are signed per process.
+	videos := r.Group("/v1/videos", cacheNoStore())
+	{
+		videos.GET("/preview/:provider/:id", getPreviewVideo(app))
+	}
+
 	private := r.Group("/v1", authentication(v))
 	{
+		private.GET("/parse", getParse())
+
 		db := private.Group("/db")
 		{
 			db.GET("/version", getDBVersion(app))
+			db.GET("/movies/search", getDBSearch(app, movieSearchType))
+			db.GET("/actors/search", getDBSearch(app, actorSearchType))
 		}
 
 		actors := private.Group("/actors")
 		{
 			actors.GET("/:provider/:id", getInfo(app, actorInfoType))
+			actors.PUT("/:provider/:id", requireToken(v), overrideInfo(app, actorInfoType, false))
+			actors.PATCH("/:provider/:id", requireToken(v), overrideInfo(app, actorInfoType, true))
+			actors.GET("/:provider/:id/movies", getActorFilmography(app))
+			actors.GET("/:provider/:id/history", getRevisions(app, actorInfoType))
+			actors.POST("/:provider/:id/history/:revision/rollback", requireToken(v), rollbackRevision(app, actorInfoType))
 			actors.GET("/search", getSearch(app, actorSearchType))
 		}
 
 		movies := private.Group("/movies")
 		{
 			movies.GET("/:provider/:id", getInfo(app, movieInfoType))
+			movies.PUT("/:provider/:id", requireToken(v), overrideInfo(app, movieInfoType, false))
+			movies.PATCH("/:provider/:id", requireToken(v), overrideInfo(app, movieInfoType, true))
+			movies.GET("/:provider/:id/history", getRevisions(app, movieInfoType))
+			movies.POST("/:provider/:id/history/:revision/rollback", requireToken(v), rollbackRevision(app, movieInfoType))
 			movies.GET("/search", getSearch(app, movieSearchType))
+			movies.GET("/match", getMatch(app))
+			movies.POST("/match/batch", requireToken(v), postMatchBatch(app))
+			movies.GET("/match/batch/:id", getMatchBatch(app))
+			movies.GET("/match/batch/:id/results", getMatchBatchResults(app))
+		}
+
+		makers := private.Group("/makers")
+		{
+			makers.GET("", getMakers(app))
+			makers.GET("/movies", getCollectionMovies(app, mt.MakerCollection))
+			makers.GET("/:provider/:id/movies", browseCollectionMovies(app, mt.MakerCollection))
+		}
+
+		labels := private.Group("/labels")
+		{
+			labels.GET("/movies", getCollectionMovies(app, mt.LabelCollection))
+			labels.GET("/:provider/:id/movies", browseCollectionMovies(app, mt.LabelCollection))
+		}
+
+		series := private.Group("/series")
+		{
+			series.GET("", getSeries(app))
+			series.GET("/movies", getCollectionMovies(app, mt.SeriesCollection))
+			series.GET("/:provider/:id/movies", browseCollectionMovies(app, mt.SeriesCollection))
 		}
 
 		reviews := private.Group("/reviews")
 		{
 			reviews.GET("/:provider/:id", getReview(app))
 		}
+
+		admin := private.Group("/admin", requireToken(v))
+		{
+			admin.GET("/providers", getProviderCapabilities(app))
+			admin.PATCH("/providers/:type/:name", updateProvider(app))
+			admin.GET("/db/export", getDBExport(app))
+			admin.POST("/db/import", postDBImport(app))
+			admin.GET("/jobs", getJobs(queue))
+			admin.GET("/jobs/:name/runs", getJobRuns(queue))
+			admin.POST("/jobs/:name/trigger", postJobAction(queue, triggerJobAction))
+			admin.POST("/jobs/:name/pause", postJobAction(queue, pauseJobAction))
+			admin.POST("/jobs/:name/resume", postJobAction(queue, resumeJobAction))
+			admin.POST("/jobs/:name/runs/:id/cancel", cancelJobRun(queue))
+			admin.GET("/numbers", getNumberPrefixes(app))
+			admin.GET("/numbers/:prefix", getNumberPrefix(app))
+			admin.POST("/numbers/:prefix", saveNumberPrefix(app, true))
+			admin.PATCH("/numbers/:prefix", saveNumberPrefix(app, false))
+			admin.DELETE("/numbers/:prefix", deleteNumberPrefix(app))
+		}
 	}
 
 	return r
@@ -126,20 +192,23 @@ func getModules() gin.HandlerFunc {
 }
 
 func getProviders(app *engine.Engine) gin.HandlerFunc {
-	data := struct {
-		ActorProviders map[string]string `json:"actor_providers"`
-		MovieProviders map[string]string `json:"movie_providers"`
-	}{
-		ActorProviders: make(map[string]string),
-		MovieProviders: make(map[string]string),
-	}
-	for _, provider := range app.GetActorProviders() {
-		data.ActorProviders[provider.Name()] = provider.URL().String()
-	}
-	for _, provider := range app.GetMovieProviders() {
-		data.MovieProviders[provider.Name()] = provider.URL().String()
-	}
 	return func(c *gin.Context) {
+		data := struct {
+			ActorProviders map[string]string            `json:"actor_providers"`
+			MovieProviders map[string]string            `json:"movie_providers"`
+			Capabilities   []*engine.ProviderCapability `json:"capabilities"`
+		}{
+			ActorProviders: make(map[string]string),
+			MovieProviders: make(map[string]string),
+			// includes disabled providers.
+			Capabilities: app.GetProviderCapabilities(),
+		}
+		for _, provider := range app.GetActorProviders() {
+			data.ActorProviders[provider.Name()] = provider.URL().String()
+		}
+		for _, provider := range app.GetMovieProviders() {
+			data.MovieProviders[provider.Name()] = provider.URL().String()
+		}
 		c.JSON(http.StatusOK, &responseMessage{Data: data})
 	}
 }
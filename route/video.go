@@ -0,0 +1,150 @@
+package route
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/metatube-community/metatube-sdk-go/common/fetch"
+	"github.com/metatube-community/metatube-sdk-go/common/m3u8"
+	"github.com/metatube-community/metatube-sdk-go/engine"
+	"github.com/metatube-community/metatube-sdk-go/errors"
+)
+
+// maxPlaylistSize limits the size of HLS playlists to rewrite.
+const maxPlaylistSize = 8 << 20
+
+// videoSignKey signs the URLs rewritten into playlists, so that the
+// proxy only fetches URLs issued by itself, not arbitrary ones.
+var videoSignKey = func() []byte {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(err)
+	}
+	return key
+}()
+
+type videoUri struct {
+	infoUri // same as info uri
+}
+
+type videoQuery struct {
+	URL       string `form:"url"`
+	Signature string `form:"sig"`
+	HLS       bool   `form:"hls"`
+}
+
+func signVideoURL(provider, id, rawURL string) string {
+	mac := hmac.New(sha256.New, videoSignKey)
+	mac.Write([]byte(provider + "\x00" + id + "\x00" + rawURL))
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+func getPreviewVideo(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := &videoUri{}
+		if err := c.ShouldBindUri(uri); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+		query := &videoQuery{}
+		if err := c.ShouldBindQuery(query); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
+		// the provider may be disabled at runtime, so it's got only once.
+		provider, err := app.GetMovieProviderByName(uri.Provider)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		var videoURL string
+		if query.URL != "" /* segment, key or sub-playlist */ {
+			if !hmac.Equal([]byte(query.Signature),
+				[]byte(signVideoURL(provider.Name(), uri.ID, query.URL))) {
+				abortWithStatusMessage(c, http.StatusForbidden, "invalid signature")
+				return
+			}
+			videoURL = query.URL
+		} else if videoURL, err = app.GetMoviePreviewVideoURL(uri.AsProviderID(), query.HLS); err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		// status is checked below, as 206 is expected for ranges.
+		opts := []fetch.Option{fetch.WithRaiseForStatus(false)}
+		if r := c.GetHeader("Range"); r != "" {
+			opts = append(opts, fetch.WithHeader("Range", r))
+		}
+		resp, err := app.FetchWithOptions(videoURL, provider, opts...)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+			abortWithError(c, errors.FromCode(resp.StatusCode))
+			return
+		}
+
+		if isPlaylist(resp, videoURL) {
+			base, _ := url.Parse(videoURL)
+			if resp.Request != nil /* after redirects, if any */ {
+				base = resp.Request.URL
+			}
+			data, _, err := m3u8.Rewrite(io.LimitReader(resp.Body, maxPlaylistSize), func(ref string) string {
+				u, err := base.Parse(ref)
+				if err != nil {
+					return ref
+				}
+				abs := u.String()
+				return c.Request.URL.Path + "?" + url.Values{
+					"url": {abs},
+					"sig": {signVideoURL(provider.Name(), uri.ID, abs)},
+				}.Encode()
+			})
+			if err != nil {
+				abortWithStatusMessage(c, http.StatusBadGateway, err)
+				return
+			}
+			c.Data(http.StatusOK, "application/vnd.apple.mpegurl", data)
+			return
+		}
+
+		extraHeaders := make(map[string]string)
+		for _, key := range []string{
+			"Accept-Ranges",
+			"Content-Range",
+			"ETag",
+			"Last-Modified",
+		} {
+			if value := resp.Header.Get(key); value != "" {
+				extraHeaders[key] = value
+			}
+		}
+		c.DataFromReader(resp.StatusCode, resp.ContentLength,
+			resp.Header.Get("Content-Type"), resp.Body, extraHeaders)
+	}
+}
+
+// isPlaylist reports whether the response is an HLS playlist.
+func isPlaylist(resp *http.Response, rawURL string) bool {
+	if strings.Contains(strings.ToLower(resp.Header.Get("Content-Type")), "mpegurl") {
+		return true
+	}
+	if u, err := url.Parse(rawURL); err == nil {
+		return strings.EqualFold(path.Ext(u.Path), ".m3u8")
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule(t *testing.T) {
+	// Saturday.
+	from := time.Date(2024, 6, 1, 10, 30, 15, 0, time.UTC)
+	for _, unit := range []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2024, 6, 1, 10, 31, 0, 0, time.UTC)},
+		{"0 1 * * *", time.Date(2024, 6, 2, 1, 0, 0, 0, time.UTC)},
+		{"45 10 * * *", time.Date(2024, 6, 1, 10, 45, 0, 0, time.UTC)},
+		{"*/20 * * * *", time.Date(2024, 6, 1, 10, 40, 0, 0, time.UTC)},
+		{"10-20/5 11 * * *", time.Date(2024, 6, 1, 11, 10, 0, 0, time.UTC)},
+		{"0 0 * * 1", time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC)},
+		{"0 0 15 * 1", time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+		{"@monthly", time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{"@every 90m", time.Date(2024, 6, 1, 12, 0, 15, 0, time.UTC)},
+	} {
+		s, err := ParseSchedule(unit.spec)
+		require.NoError(t, err, unit.spec)
+		assert.Equal(t, unit.want, s.Next(from), unit.spec)
+	}
+}
+
+func TestParseSchedule_Invalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"@every 1ms",
+		"@every abc",
+	} {
+		_, err := ParseSchedule(spec)
+		assert.Error(t, err, spec)
+	}
+}
@@ -0,0 +1,32 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+
+	"gorm.io/gorm"
+
+	"github.com/metatube-community/metatube-sdk-go/engine"
+)
+
+// Built-in job types, the jobs are named the same as their types.
+const (
+	// NumberStatusJob fetches the movies of the unfinished number
+	// prefixes in the number_status table.
+	NumberStatusJob = "number_status"
+	// ReleaseFeedJob ingests the release feeds of feeder providers.
+	ReleaseFeedJob = "release_feed"
+)
+
+// DefaultNumberStatusSchedule runs the number status job at 01:00 daily.
+const DefaultNumberStatusSchedule = "0 1 * * *"
+
+// RegisterJobs registers the handlers of the built-in job types.
+func RegisterJobs(q *Queue, db *gorm.DB, e *engine.Engine) {
+	q.Register(NumberStatusJob, func(ctx context.Context, _ json.RawMessage) error {
+		return runNumberStatusTask(ctx, db, e)
+	})
+	q.Register(ReleaseFeedJob, func(ctx context.Context, _ json.RawMessage) error {
+		return runReleaseFeedTask(ctx, db, e)
+	})
+}
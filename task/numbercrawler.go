@@ -0,0 +1,368 @@
+package task
+
+import (
+	"context"
+	goerr "errors"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	"github.com/metatube-community/metatube-sdk-go/engine"
+	"github.com/metatube-community/metatube-sdk-go/engine/providerid"
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+const (
+	// crawlConcurrency is the number of numbers looked up at a time,
+	// which is also the window size of the forward walk.
+	crawlConcurrency = 4
+	// crawlProviderConcurrency limits the info fetches per provider,
+	// as the lookups of a prefix mostly hit the same providers.
+	crawlProviderConcurrency = 2
+)
+
+// runNumberStatusTask crawls the numbers of the active prefixes in the
+// number_status table, the errors of prefixes are joined.
+func runNumberStatusTask(ctx context.Context, db *gorm.DB, e *engine.Engine) error {
+	var statuses []*model.NumberStatus
+	if err := db.Where("status = ?", model.NumberStatusActive).Order("number_prefix").Find(&statuses).Error; err != nil {
+		return fmt.Errorf("query number_status table: %w", err)
+	}
+	log.Printf("Found %d active number prefixes", len(statuses))
+
+	var (
+		c    = newNumberCrawler(db, e)
+		errs []error
+	)
+	for _, status := range statuses {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := c.crawl(ctx, status); err != nil {
+			errs = append(errs, fmt.Errorf("crawl %s: %w", status.NumberPrefix, err))
+		}
+	}
+	return goerr.Join(errs...)
+}
+
+type numberCrawler struct {
+	db *gorm.DB
+	e  *engine.Engine
+
+	mu sync.Mutex
+	// Semaphores of info fetches by upper case provider names.
+	providers map[string]chan struct{}
+}
+
+func newNumberCrawler(db *gorm.DB, e *engine.Engine) *numberCrawler {
+	return &numberCrawler{
+		db:        db,
+		e:         e,
+		providers: make(map[string]chan struct{}),
+	}
+}
+
+// crawl fills the gaps below the last found number first, and then walks
+// forward until the max suffix, or MaxMisses consecutive misses, so that
+// the next run starts from the last found number again.
+func (c *numberCrawler) crawl(ctx context.Context, status *model.NumberStatus) error {
+	present, err := c.presentSuffixes(status)
+	if err != nil {
+		return c.fail(status, err)
+	}
+	missed := make(map[int]bool)
+	for _, suffix := range status.Gaps.Data() {
+		missed[suffix] = true
+	}
+	frontier, gaps := planCrawl(status, present, missed)
+	// drop the gaps found meanwhile, or out of the range changed.
+	maps.DeleteFunc(missed, func(suffix int, _ bool) bool {
+		_, ok := present[suffix]
+		return ok || suffix < status.MinSuffix || suffix > frontier
+	})
+	log.Printf("Crawling %s from %s with %d gaps", status.NumberPrefix, status.Number(frontier+1), len(gaps))
+
+	hint := &idHint{}
+	if pid, ok := present[frontier]; ok {
+		hint.set(pid, frontier)
+	}
+	save := func() error {
+		status.LastFound = frontier
+		status.Gaps = datatypes.NewJSONType(slices.Sorted(maps.Keys(missed)))
+		return c.saveProgress(status)
+	}
+
+	for batch := range slices.Chunk(gaps, crawlConcurrency) {
+		found := c.lookupAll(ctx, status, batch, hint)
+		if ctx.Err() != nil {
+			break // results of canceled lookups are unreliable.
+		}
+		for i, suffix := range batch {
+			if found[i] {
+				status.Found++
+			} else {
+				missed[suffix] = true
+			}
+		}
+		if err = save(); err != nil {
+			return c.fail(status, err)
+		}
+	}
+
+	var misses []int
+	for next := frontier + 1; next <= status.MaxSuffix && ctx.Err() == nil; {
+		if status.MaxMisses > 0 && len(misses) >= status.MaxMisses {
+			break
+		}
+		batch := make([]int, 0, crawlConcurrency)
+		for ; next <= status.MaxSuffix && len(batch) < crawlConcurrency; next++ {
+			batch = append(batch, next)
+		}
+		found := c.lookupAll(ctx, status, batch, hint)
+		if ctx.Err() != nil {
+			break
+		}
+		for i, suffix := range batch {
+			if !found[i] {
+				misses = append(misses, suffix)
+				continue
+			}
+			// the misses before a found number are gaps.
+			for _, m := range misses {
+				missed[m] = true
+			}
+			misses, frontier = misses[:0], suffix
+			status.Found++
+		}
+		if err = save(); err != nil {
+			return c.fail(status, err)
+		}
+	}
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	status.LastRunAt, status.LastError = &now, ""
+	if frontier >= status.MaxSuffix {
+		status.Status = model.NumberStatusDone
+	}
+	if err = save(); err != nil {
+		return c.fail(status, err)
+	}
+	log.Printf("Crawled %s: %d found, last found %s", status.NumberPrefix, status.Found, status.Number(frontier))
+	return nil
+}
+
+// planCrawl returns the frontier, i.e. the largest suffix known to
+// exist, and the gaps below it which are not tried yet. The last found
+// is not a gap, even if it's saved under a different number form.
+func planCrawl(status *model.NumberStatus, present map[int]providerid.ProviderID, missed map[int]bool) (int, []int) {
+	frontier := max(status.LastFound, status.MinSuffix-1)
+	for suffix := range present {
+		frontier = max(frontier, suffix)
+	}
+	frontier = min(frontier, status.MaxSuffix)
+	var gaps []int
+	for suffix := status.MinSuffix; suffix <= frontier; suffix++ {
+		if _, ok := present[suffix]; !ok && !missed[suffix] && suffix != status.LastFound {
+			gaps = append(gaps, suffix)
+		}
+	}
+	return frontier, gaps
+}
+
+// saveProgress saves the progress of the prefix, and the status only
+// if it's done, so that the changes by API meanwhile are kept.
+func (c *numberCrawler) saveProgress(status *model.NumberStatus) error {
+	updates := map[string]any{
+		"last_found":  status.LastFound,
+		"found":       status.Found,
+		"gaps":        status.Gaps,
+		"last_run_at": status.LastRunAt,
+		"last_error":  status.LastError,
+	}
+	if status.Status == model.NumberStatusDone {
+		updates["status"] = status.Status
+	}
+	return c.db.Model(&model.NumberStatus{}).
+		Where("number_prefix = ?", status.NumberPrefix).
+		Updates(updates).Error
+}
+
+func (c *numberCrawler) fail(status *model.NumberStatus, err error) error {
+	if e := c.db.Model(&model.NumberStatus{}).
+		Where("number_prefix = ?", status.NumberPrefix).
+		Update("last_error", err.Error()).Error; e != nil {
+		log.Printf("Failed to save error of %s: %v", status.NumberPrefix, e)
+	}
+	return err
+}
+
+// presentSuffixes returns the suffixes in range of the movies saved
+// already, with the ids of any of them.
+func (c *numberCrawler) presentSuffixes(status *model.NumberStatus) (map[int]providerid.ProviderID, error) {
+	rows, err := c.e.GetNumberPrefixMovies(status.NumberPrefix)
+	if err != nil {
+		return nil, err
+	}
+	present := make(map[int]providerid.ProviderID)
+	for _, row := range rows {
+		suffix, ok := parseNumberSuffix(row.Number, status.NumberPrefix)
+		if !ok || suffix < status.MinSuffix || suffix > status.MaxSuffix {
+			continue
+		}
+		if _, ok = present[suffix]; !ok {
+			present[suffix] = providerid.ProviderID{Provider: row.Provider, ID: row.ID}
+		}
+	}
+	return present, nil
+}
+
+func (c *numberCrawler) lookupAll(ctx context.Context, status *model.NumberStatus, suffixes []int, hint *idHint) []bool {
+	var (
+		wg    sync.WaitGroup
+		found = make([]bool, len(suffixes))
+	)
+	for i, suffix := range suffixes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			found[i] = c.lookup(ctx, status.Number(suffix), suffix, hint)
+		}()
+	}
+	wg.Wait()
+	return found
+}
+
+// lookup finds the movie of the number and saves it. The id derived from
+// the last found one is tried first, and then the search of all providers.
+func (c *numberCrawler) lookup(ctx context.Context, number string, suffix int, hint *idHint) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if pid, ok := hint.next(suffix); ok {
+		if info, err := c.fetch(pid); err == nil && sameNumber(info.Number, number) {
+			hint.set(pid, suffix)
+			return true
+		}
+	}
+
+	results, err := c.e.SearchMovieAll(number, false)
+	if err != nil {
+		if !goerr.Is(err, mt.ErrInfoNotFound) {
+			log.Printf("Failed to search movie for %s: %v", number, err)
+		}
+		return false
+	}
+	for _, result := range results {
+		if !sameNumber(result.Number, number) {
+			continue
+		}
+		pid := providerid.ProviderID{Provider: result.Provider, ID: result.ID}
+		if _, err = c.fetch(pid); err != nil {
+			log.Printf("Failed to fetch movie info of %s from %s: %v", number, pid.Provider, err)
+			continue
+		}
+		hint.set(pid, suffix)
+		return true
+	}
+	return false
+}
+
+// fetch gets and saves the movie info with bounded concurrency per provider.
+func (c *numberCrawler) fetch(pid providerid.ProviderID) (*model.MovieInfo, error) {
+	c.mu.Lock()
+	key := strings.ToUpper(pid.Provider)
+	sem, ok := c.providers[key]
+	if !ok {
+		sem = make(chan struct{}, crawlProviderConcurrency)
+		c.providers[key] = sem
+	}
+	c.mu.Unlock()
+
+	sem <- struct{}{}
+	defer func() { <-sem }()
+	return c.e.GetMovieInfoByProviderID(pid, true)
+}
+
+// idHint derives the provider id of a number from a found one, as ids
+// usually end with the zero-padded suffix, e.g. abp00123 for ABP-123.
+type idHint struct {
+	mu     sync.Mutex
+	pid    providerid.ProviderID
+	suffix int
+}
+
+func (h *idHint) set(pid providerid.ProviderID, suffix int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.pid, h.suffix = pid, suffix
+}
+
+func (h *idHint) next(suffix int) (providerid.ProviderID, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	id := h.pid.ID
+	i := len(id)
+	for i > 0 && isDigit(id[i-1]) {
+		i--
+	}
+	if v, err := strconv.Atoi(id[i:]); err != nil || v != h.suffix {
+		return providerid.ProviderID{}, false
+	}
+	return providerid.ProviderID{
+		Provider: h.pid.Provider,
+		ID:       fmt.Sprintf("%s%0*d", id[:i], len(id)-i, suffix),
+	}, true
+}
+
+// parseNumberSuffix returns the suffix of the number of the prefix,
+// e.g. 1 for ABP-001.
+func parseNumberSuffix(number, prefix string) (int, bool) {
+	if len(number) <= len(prefix)+1 || !strings.EqualFold(number[:len(prefix)+1], prefix+"-") {
+		return 0, false
+	}
+	s := number[len(prefix)+1:]
+	for i := range len(s) {
+		if !isDigit(s[i]) {
+			return 0, false
+		}
+	}
+	v, err := strconv.Atoi(s)
+	return v, err == nil
+}
+
+// sameNumber reports whether the numbers are the same regardless of the
+// case, separators and zero-padding, e.g. abp00123 and ABP-123.
+func sameNumber(a, b string) bool {
+	return normalizeNumber(a) == normalizeNumber(b)
+}
+
+func normalizeNumber(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return -1
+	}, s)
+	i := len(s)
+	for i > 0 && isDigit(s[i-1]) {
+		i--
+	}
+	return s[:i] + strings.TrimLeft(s[i:], "0")
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
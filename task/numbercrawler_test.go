@@ -0,0 +1,109 @@
+package task
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/datatypes"
+
+	"github.com/metatube-community/metatube-sdk-go/engine/providerid"
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+func TestPlanCrawl(t *testing.T) {
+	pid := providerid.ProviderID{Provider: "FANZA", ID: "abp00001"}
+	for _, unit := range []struct {
+		status   *model.NumberStatus
+		present  []int
+		missed   []int
+		frontier int
+		gaps     []int
+	}{
+		{&model.NumberStatus{MinSuffix: 1, MaxSuffix: 999}, nil, nil, 0, nil},
+		{&model.NumberStatus{MinSuffix: 100, MaxSuffix: 999}, nil, nil, 99, nil},
+		{&model.NumberStatus{MinSuffix: 1, MaxSuffix: 999}, []int{1, 3, 6}, []int{4}, 6, []int{2, 5}},
+		{&model.NumberStatus{MinSuffix: 1, MaxSuffix: 999, LastFound: 8}, []int{1, 3, 6}, []int{4}, 8, []int{2, 5, 7}},
+		{&model.NumberStatus{MinSuffix: 1, MaxSuffix: 5, LastFound: 8}, []int{1}, nil, 5, []int{2, 3, 4, 5}},
+	} {
+		present := make(map[int]providerid.ProviderID)
+		for _, suffix := range unit.present {
+			present[suffix] = pid
+		}
+		missed := make(map[int]bool)
+		for _, suffix := range unit.missed {
+			missed[suffix] = true
+		}
+		frontier, gaps := planCrawl(unit.status, present, missed)
+		assert.Equal(t, unit.frontier, frontier)
+		assert.Equal(t, unit.gaps, gaps)
+	}
+}
+
+func TestIDHint(t *testing.T) {
+	h := &idHint{}
+	_, ok := h.next(2)
+	assert.False(t, ok)
+
+	h.set(providerid.ProviderID{Provider: "FANZA", ID: "118abp00099"}, 99)
+	pid, ok := h.next(100)
+	assert.True(t, ok)
+	assert.Equal(t, providerid.ProviderID{Provider: "FANZA", ID: "118abp00100"}, pid)
+
+	// the trailing digits are not the suffix.
+	h.set(providerid.ProviderID{Provider: "JAV321", ID: "abp099x"}, 99)
+	_, ok = h.next(100)
+	assert.False(t, ok)
+}
+
+func TestParseNumberSuffix(t *testing.T) {
+	for _, unit := range []struct {
+		number string
+		suffix int
+		ok     bool
+	}{
+		{"ABP-001", 1, true},
+		{"abp-1234", 1234, true},
+		{"ABP-", 0, false},
+		{"ABP-01A", 0, false},
+		{"ABPX-001", 0, false},
+		{"AB-001", 0, false},
+	} {
+		suffix, ok := parseNumberSuffix(unit.number, "ABP")
+		assert.Equal(t, unit.ok, ok, unit.number)
+		assert.Equal(t, unit.suffix, suffix, unit.number)
+	}
+}
+
+func TestSameNumber(t *testing.T) {
+	assert.True(t, sameNumber("ABP-123", "abp00123"))
+	assert.True(t, sameNumber("abp_123", "ABP-123"))
+	assert.False(t, sameNumber("ABP-123", "ABP-1234"))
+	assert.False(t, sameNumber("ABP-123", "ABW-123"))
+}
+
+func TestNumberCrawler_SaveProgress(t *testing.T) {
+	db := openTestDB(t)
+	c := newNumberCrawler(db, nil)
+
+	status := &model.NumberStatus{
+		NumberPrefix: "ABP",
+		Status:       model.NumberStatusActive,
+		Digits:       3,
+		MinSuffix:    1,
+		MaxSuffix:    999,
+	}
+	assert.NoError(t, db.Create(status).Error)
+
+	// paused by API meanwhile.
+	assert.NoError(t, db.Model(status).Update("status", model.NumberStatusPaused).Error)
+	status.LastFound, status.Found = 10, 9
+	status.Gaps = datatypes.NewJSONType([]int{4})
+	assert.NoError(t, c.saveProgress(status))
+
+	got := &model.NumberStatus{}
+	assert.NoError(t, db.First(got, "number_prefix = ?", "ABP").Error)
+	assert.Equal(t, model.NumberStatusPaused, got.Status)
+	assert.Equal(t, 10, got.LastFound)
+	assert.Equal(t, int64(9), got.Found)
+	assert.Equal(t, []int{4}, got.Gaps.Data())
+}
@@ -0,0 +1,637 @@
+package task
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	goerr "errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/metatube-community/metatube-sdk-go/errors"
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+const (
+	// DefaultWorkers is the number of jobs run at a time per instance.
+	DefaultWorkers = 2
+	// DefaultMaxAttempts is the number of attempts of a run before it
+	// is marked as failed.
+	DefaultMaxAttempts = 3
+
+	defaultPollInterval = 5 * time.Second
+	// defaultLeaseDuration is how long a run is held by its owner
+	// without heartbeats, before it's considered abandoned, e.g. the
+	// instance crashed, and retried by any instance.
+	defaultLeaseDuration = time.Minute
+
+	retryBaseDelay = 30 * time.Second
+	retryMaxDelay  = time.Hour
+
+	// defaultRunRetention is how long finished runs are kept, the
+	// older ones are pruned every pruneInterval.
+	defaultRunRetention = 30 * 24 * time.Hour
+	pruneInterval       = time.Hour
+)
+
+var (
+	ErrJobNotFound    = errors.New(http.StatusNotFound, "job not found")
+	ErrJobRunNotFound = errors.New(http.StatusNotFound, "job run not found")
+	ErrJobRunActive   = errors.New(http.StatusConflict, "job has a pending or running run")
+	ErrJobRunFinished = errors.New(http.StatusConflict, "job run already finished")
+	ErrJobChanged     = errors.New(http.StatusConflict, "job changed concurrently, try again")
+)
+
+var activeRunStatuses = []model.JobRunStatus{model.JobRunPending, model.JobRunRunning}
+
+// Handler runs a job with its payload. The context is canceled when
+// the run is canceled, or the queue is stopped.
+type Handler func(ctx context.Context, payload json.RawMessage) error
+
+// Queue is a DB-backed job queue shared by all server instances. Due
+// scheduled jobs are enqueued by only one instance, and every run is
+// claimed by only one instance, by conditional updates of the rows.
+type Queue struct {
+	db      *gorm.DB
+	owner   string
+	workers int
+
+	pollInterval  time.Duration
+	leaseDuration time.Duration
+	retryDelay    func(attempts int) time.Duration
+	runRetention  time.Duration
+	// prunedAt is when finished runs were last pruned, only accessed
+	// by the poll loop.
+	prunedAt time.Time
+
+	mu       sync.Mutex
+	handlers map[string]Handler
+	// Cancel functions of the runs of this instance by ids.
+	running map[uint64]context.CancelFunc
+
+	wake   chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewQueue returns a queue running at most workers jobs at a time,
+// DefaultWorkers is used if workers <= 0.
+func NewQueue(db *gorm.DB, workers int) *Queue {
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Queue{
+		db:            db,
+		owner:         newOwnerID(),
+		workers:       workers,
+		pollInterval:  defaultPollInterval,
+		leaseDuration: defaultLeaseDuration,
+		retryDelay:    backoff,
+		runRetention:  defaultRunRetention,
+		handlers:      make(map[string]Handler),
+		running:       make(map[uint64]context.CancelFunc),
+		wake:          make(chan struct{}, 1),
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+}
+
+func newOwnerID() string {
+	hostname, _ := os.Hostname()
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), hex.EncodeToString(b))
+}
+
+// backoff returns the exponential delay before the next attempt.
+func backoff(attempts int) time.Duration {
+	return min(retryBaseDelay<<min(max(attempts-1, 0), 16), retryMaxDelay)
+}
+
+// Register sets the handler of the job type, only the runs of the
+// registered types are claimed by this instance.
+func (q *Queue) Register(typ string, handler Handler) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlers[typ] = handler
+}
+
+// Schedule creates or updates the job by its name, while its paused
+// state and runs are kept. An empty spec means the job runs only when
+// triggered, see ParseSchedule for the spec format. A new scheduled job
+// runs right away, rather than waiting for its first due time.
+func (q *Queue) Schedule(name, typ, spec string, payload any) error {
+	var first, next *time.Time
+	if spec != "" {
+		s, err := ParseSchedule(spec)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		t := s.Next(now)
+		first, next = &now, &t
+	}
+	var data datatypes.JSON
+	if payload != nil {
+		var err error
+		if data, err = json.Marshal(payload); err != nil {
+			return err
+		}
+	}
+
+	return q.db.Transaction(func(tx *gorm.DB) error {
+		job, err := findJob(tx, name)
+		if goerr.Is(err, ErrJobNotFound) {
+			return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.Job{
+				Name:        name,
+				Type:        typ,
+				Schedule:    spec,
+				Payload:     data,
+				MaxAttempts: DefaultMaxAttempts,
+				NextRunAt:   first,
+			}).Error
+		} else if err != nil {
+			return err
+		}
+		updates := map[string]any{
+			"type":    typ,
+			"payload": data,
+		}
+		if job.Schedule != spec {
+			updates["schedule"] = spec
+			updates["next_run_at"] = next
+			updates["version"] = gorm.Expr("version + 1")
+		}
+		return tx.Model(job).Updates(updates).Error
+	})
+}
+
+// Start starts polling for due jobs in the background.
+func (q *Queue) Start() {
+	q.wg.Add(1)
+	go func() {
+		defer q.wg.Done()
+		log.Printf("Job queue started, workers: %d, owner: %s", q.workers, q.owner)
+		ticker := time.NewTicker(q.pollInterval)
+		defer ticker.Stop()
+		for {
+			q.poll()
+			select {
+			case <-q.ctx.Done():
+				return
+			case <-ticker.C:
+			case <-q.wake:
+			}
+		}
+	}()
+}
+
+// Stop stops polling and cancels the running jobs, which are released
+// to be run again, it returns after all of them have returned.
+func (q *Queue) Stop() {
+	q.cancel()
+	q.wg.Wait()
+}
+
+func (q *Queue) notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Jobs returns all jobs ordered by names.
+func (q *Queue) Jobs() ([]*model.Job, error) {
+	var jobs []*model.Job
+	if err := q.db.Order("name").Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// Runs returns the runs of the job, newest first.
+func (q *Queue) Runs(name string, limit, offset int) ([]*model.JobRun, error) {
+	if _, err := findJob(q.db, name); err != nil {
+		return nil, err
+	}
+	tx := q.db.Where("job_name = ?", name).Order("id DESC")
+	if limit > 0 {
+		tx = tx.Limit(limit)
+	}
+	if offset > 0 {
+		tx = tx.Offset(offset)
+	}
+	var runs []*model.JobRun
+	if err := tx.Find(&runs).Error; err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+// Trigger enqueues a run of the job now, it fails if the job already
+// has a pending or running run. Runs of paused jobs wait until resumed.
+func (q *Queue) Trigger(name string) (*model.JobRun, error) {
+	var run *model.JobRun
+	if err := q.db.Transaction(func(tx *gorm.DB) error {
+		job, err := findJob(tx, name)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		if ok, err := compareAndUpdateJob(tx, job, map[string]any{"last_run_at": now}); err != nil {
+			return err
+		} else if !ok {
+			return ErrJobChanged
+		}
+		if active, err := hasActiveRun(tx, name); err != nil {
+			return err
+		} else if active {
+			return ErrJobRunActive
+		}
+		run = newRun(job, now)
+		return tx.Create(run).Error
+	}); err != nil {
+		return nil, err
+	}
+	q.notify()
+	return run, nil
+}
+
+// Pause stops the job from being scheduled, and its pending runs from
+// being claimed, the running one is not affected.
+func (q *Queue) Pause(name string) (*model.Job, error) {
+	return q.setPaused(name, true)
+}
+
+// Resume resumes the paused job, the runs missed meanwhile are skipped.
+func (q *Queue) Resume(name string) (*model.Job, error) {
+	job, err := q.setPaused(name, false)
+	if err == nil {
+		q.notify()
+	}
+	return job, err
+}
+
+func (q *Queue) setPaused(name string, paused bool) (*model.Job, error) {
+	var job *model.Job
+	if err := q.db.Transaction(func(tx *gorm.DB) (err error) {
+		if job, err = findJob(tx, name); err != nil {
+			return err
+		}
+		updates := map[string]any{"paused": paused}
+		if !paused && job.Schedule != "" {
+			s, err := ParseSchedule(job.Schedule)
+			if err != nil {
+				return err
+			}
+			next := s.Next(time.Now())
+			updates["next_run_at"] = &next
+		}
+		if ok, err := compareAndUpdateJob(tx, job, updates); err != nil {
+			return err
+		} else if !ok {
+			return ErrJobChanged
+		}
+		job, err = findJob(tx, name)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Cancel cancels the pending or running run of the job. Running jobs
+// of other instances are canceled on their next heartbeats.
+func (q *Queue) Cancel(name string, id uint64) (*model.JobRun, error) {
+	result := q.db.Model(&model.JobRun{}).
+		Where("id = ? AND job_name = ? AND status IN ?", id, name, activeRunStatuses).
+		Updates(map[string]any{
+			"status":      model.JobRunCanceled,
+			"owner":       "",
+			"lease_until": nil,
+			"finished_at": time.Now(),
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	q.mu.Lock()
+	if cancel, ok := q.running[id]; ok {
+		cancel()
+	}
+	q.mu.Unlock()
+
+	var runs []*model.JobRun
+	if err := q.db.Where("id = ? AND job_name = ?", id, name).Limit(1).Find(&runs).Error; err != nil {
+		return nil, err
+	}
+	if len(runs) == 0 {
+		return nil, ErrJobRunNotFound
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrJobRunFinished
+	}
+	return runs[0], nil
+}
+
+func (q *Queue) poll() {
+	now := time.Now()
+	if err := q.enqueueDue(now); err != nil {
+		log.Printf("Failed to enqueue scheduled jobs: %v", err)
+	}
+	if err := q.reclaimExpired(now); err != nil {
+		log.Printf("Failed to reclaim expired job runs: %v", err)
+	}
+	if err := q.claimPending(now); err != nil {
+		log.Printf("Failed to claim pending job runs: %v", err)
+	}
+	if now.Sub(q.prunedAt) >= pruneInterval {
+		q.prunedAt = now
+		if err := q.pruneFinished(now); err != nil {
+			log.Printf("Failed to prune finished job runs: %v", err)
+		}
+	}
+}
+
+// pruneFinished deletes the runs finished longer than the retention ago.
+func (q *Queue) pruneFinished(now time.Time) error {
+	result := q.db.
+		Where("status IN ? AND finished_at < ?",
+			[]model.JobRunStatus{model.JobRunSucceeded, model.JobRunFailed, model.JobRunCanceled},
+			now.Add(-q.runRetention)).
+		Delete(&model.JobRun{})
+	if result.RowsAffected > 0 {
+		log.Printf("Pruned %d finished job runs", result.RowsAffected)
+	}
+	return result.Error
+}
+
+// enqueueDue enqueues the runs of the due scheduled jobs, and advances
+// their schedules. Jobs still having active runs are skipped this time.
+func (q *Queue) enqueueDue(now time.Time) error {
+	var jobs []*model.Job
+	if err := q.db.Where("paused = ? AND next_run_at <= ?", false, now).Find(&jobs).Error; err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		s, err := ParseSchedule(job.Schedule)
+		if err != nil {
+			log.Printf("Invalid schedule of job %s: %v", job.Name, err)
+			continue
+		}
+		next := s.Next(now)
+		if err = q.db.Transaction(func(tx *gorm.DB) error {
+			// other instances may have enqueued it already.
+			if ok, err := compareAndUpdateJob(tx, job, map[string]any{
+				"next_run_at": &next,
+				"last_run_at": now,
+			}); err != nil || !ok {
+				return err
+			}
+			if active, err := hasActiveRun(tx, job.Name); err != nil || active {
+				if active {
+					log.Printf("Skip scheduled run of job %s: previous run is still active", job.Name)
+				}
+				return err
+			}
+			return tx.Create(newRun(job, now)).Error
+		}); err != nil {
+			return fmt.Errorf("enqueue job %s: %w", job.Name, err)
+		}
+	}
+	return nil
+}
+
+// reclaimExpired retries or fails the runs whose owners stopped
+// renewing their leases.
+func (q *Queue) reclaimExpired(now time.Time) error {
+	var runs []*model.JobRun
+	if err := q.db.Where("status = ? AND lease_until < ?", model.JobRunRunning, now).Find(&runs).Error; err != nil {
+		return err
+	}
+	for _, run := range runs {
+		result := q.db.Model(&model.JobRun{}).
+			Where("id = ? AND status = ? AND lease_until < ?", run.ID, model.JobRunRunning, now).
+			Updates(q.failure(run, fmt.Errorf("lease of %s expired", run.Owner), now))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			log.Printf("Reclaimed job %s run %d from %s", run.JobName, run.ID, run.Owner)
+		}
+	}
+	return nil
+}
+
+// claimPending claims the due pending runs up to the free workers.
+func (q *Queue) claimPending(now time.Time) error {
+	q.mu.Lock()
+	free := q.workers - len(q.running)
+	types := make([]string, 0, len(q.handlers))
+	for typ := range q.handlers {
+		types = append(types, typ)
+	}
+	q.mu.Unlock()
+	if free <= 0 || len(types) == 0 {
+		return nil
+	}
+
+	var runs []*model.JobRun
+	if err := q.db.
+		Where("status = ? AND run_at <= ? AND type IN ?", model.JobRunPending, now, slices.Sorted(slices.Values(types))).
+		Where("job_name NOT IN (?)", q.db.Model(&model.Job{}).Select("name").Where("paused = ?", true)).
+		Order("run_at").Order("id").
+		Limit(free).
+		Find(&runs).Error; err != nil {
+		return err
+	}
+	for _, run := range runs {
+		lease := now.Add(q.leaseDuration)
+		result := q.db.Model(&model.JobRun{}).
+			Where("id = ? AND status = ?", run.ID, model.JobRunPending).
+			Updates(map[string]any{
+				"status":      model.JobRunRunning,
+				"owner":       q.owner,
+				"lease_until": lease,
+				"attempts":    gorm.Expr("attempts + 1"),
+				"started_at":  now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue // claimed by others.
+		}
+		run.Status, run.Owner, run.LeaseUntil = model.JobRunRunning, q.owner, &lease
+		run.Attempts++
+		q.execute(run)
+	}
+	return nil
+}
+
+func (q *Queue) execute(run *model.JobRun) {
+	q.mu.Lock()
+	handler := q.handlers[run.Type]
+	ctx, cancel := context.WithCancel(q.ctx)
+	q.running[run.ID] = cancel
+	q.mu.Unlock()
+
+	q.wg.Add(1)
+	go func() {
+		defer q.wg.Done()
+		defer func() {
+			q.mu.Lock()
+			delete(q.running, run.ID)
+			q.mu.Unlock()
+			cancel()
+			// claim the next one.
+			q.notify()
+		}()
+
+		log.Printf("Running job %s run %d (attempt %d/%d)", run.JobName, run.ID, run.Attempts, run.MaxAttempts)
+		done := make(chan struct{})
+		go q.heartbeat(run.ID, cancel, done)
+		err := runHandler(ctx, handler, run.Payload)
+		close(done)
+		q.complete(run, err)
+	}()
+}
+
+// heartbeat renews the lease of the run until done, and cancels it if
+// the lease is lost, i.e. the run is canceled or reclaimed.
+func (q *Queue) heartbeat(id uint64, cancel context.CancelFunc, done <-chan struct{}) {
+	ticker := time.NewTicker(q.leaseDuration / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			result := q.db.Model(&model.JobRun{}).
+				Where("id = ? AND owner = ? AND status = ?", id, q.owner, model.JobRunRunning).
+				Update("lease_until", time.Now().Add(q.leaseDuration))
+			if result.Error != nil {
+				log.Printf("Failed to renew lease of job run %d: %v", id, result.Error)
+				continue
+			}
+			if result.RowsAffected == 0 {
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+func runHandler(ctx context.Context, handler Handler, payload []byte) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return handler(ctx, payload)
+}
+
+// complete updates the run by the result, unless it's no longer owned.
+func (q *Queue) complete(run *model.JobRun, err error) {
+	now := time.Now()
+	var updates map[string]any
+	switch {
+	case err == nil:
+		log.Printf("Job %s run %d succeeded", run.JobName, run.ID)
+		updates = map[string]any{
+			"status":      model.JobRunSucceeded,
+			"owner":       "",
+			"lease_until": nil,
+			"finished_at": now,
+			"error":       "",
+		}
+	case q.ctx.Err() != nil:
+		// stopped, run it again without counting the attempt.
+		updates = map[string]any{
+			"status":      model.JobRunPending,
+			"owner":       "",
+			"lease_until": nil,
+			"attempts":    gorm.Expr("attempts - 1"),
+		}
+	default:
+		log.Printf("Job %s run %d failed (attempt %d/%d): %v", run.JobName, run.ID, run.Attempts, run.MaxAttempts, err)
+		updates = q.failure(run, err, now)
+	}
+	if err = q.db.Model(&model.JobRun{}).
+		Where("id = ? AND owner = ? AND status = ?", run.ID, q.owner, model.JobRunRunning).
+		Updates(updates).Error; err != nil {
+		log.Printf("Failed to update job %s run %d: %v", run.JobName, run.ID, err)
+	}
+}
+
+// failure returns the updates to retry the run with backoff, or to
+// mark it as failed if it has no attempts left.
+func (q *Queue) failure(run *model.JobRun, err error, now time.Time) map[string]any {
+	updates := map[string]any{
+		"owner":       "",
+		"lease_until": nil,
+		"error":       err.Error(),
+	}
+	if run.Attempts < run.MaxAttempts {
+		updates["status"] = model.JobRunPending
+		updates["run_at"] = now.Add(q.retryDelay(run.Attempts))
+	} else {
+		updates["status"] = model.JobRunFailed
+		updates["finished_at"] = now
+	}
+	return updates
+}
+
+func newRun(job *model.Job, now time.Time) *model.JobRun {
+	return &model.JobRun{
+		JobName:     job.Name,
+		Type:        job.Type,
+		Payload:     job.Payload,
+		Status:      model.JobRunPending,
+		MaxAttempts: max(job.MaxAttempts, 1),
+		RunAt:       now,
+	}
+}
+
+func findJob(tx *gorm.DB, name string) (*model.Job, error) {
+	var jobs []*model.Job
+	if err := tx.Where("name = ?", name).Limit(1).Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, ErrJobNotFound
+	}
+	return jobs[0], nil
+}
+
+// compareAndUpdateJob updates the job only if its version is unchanged
+// since read, and reports whether it's updated.
+func compareAndUpdateJob(tx *gorm.DB, job *model.Job, updates map[string]any) (bool, error) {
+	updates["version"] = gorm.Expr("version + 1")
+	result := tx.Model(&model.Job{}).
+		Where("name = ? AND version = ?", job.Name, job.Version).
+		Updates(updates)
+	return result.RowsAffected > 0, result.Error
+}
+
+func hasActiveRun(tx *gorm.DB, name string) (bool, error) {
+	var count int64
+	if err := tx.Model(&model.JobRun{}).
+		Where("job_name = ? AND status IN ?", name, activeRunStatuses).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,259 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	goerr "errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/metatube-community/metatube-sdk-go/database"
+	"github.com/metatube-community/metatube-sdk-go/database/migrate"
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+func openTestDB(t *testing.T) *gorm.DB {
+	db, err := database.Open(&database.Config{
+		DSN:                  ":memory:",
+		MaxOpenConns:         1, // keep the memory DB.
+		DisableAutomaticPing: true,
+		LogLevel:             logger.Warn,
+	})
+	require.NoError(t, err)
+	_, err = migrate.New(db).Up()
+	require.NoError(t, err)
+	return db
+}
+
+func newTestQueue(t *testing.T, db *gorm.DB) *Queue {
+	q := NewQueue(db, 2)
+	q.pollInterval = 10 * time.Millisecond
+	q.leaseDuration = 300 * time.Millisecond
+	q.retryDelay = func(int) time.Duration { return 0 }
+	t.Cleanup(q.Stop)
+	return q
+}
+
+func waitRun(t *testing.T, q *Queue, name string, status model.JobRunStatus) *model.JobRun {
+	var run *model.JobRun
+	require.Eventually(t, func() bool {
+		runs, err := q.Runs(name, 1, 0)
+		if err != nil || len(runs) == 0 {
+			return false
+		}
+		run = runs[0]
+		return run.Status == status
+	}, 5*time.Second, 10*time.Millisecond)
+	return run
+}
+
+func TestQueue_Trigger(t *testing.T) {
+	q := newTestQueue(t, openTestDB(t))
+
+	var payload atomic.Value
+	q.Register("echo", func(_ context.Context, data json.RawMessage) error {
+		payload.Store(string(data))
+		return nil
+	})
+	require.NoError(t, q.Schedule("echo", "echo", "", map[string]string{"k": "v"}))
+	q.Start()
+
+	_, err := q.Trigger("unknown")
+	assert.ErrorIs(t, err, ErrJobNotFound)
+
+	run, err := q.Trigger("echo")
+	require.NoError(t, err)
+	assert.Equal(t, model.JobRunPending, run.Status)
+
+	run = waitRun(t, q, "echo", model.JobRunSucceeded)
+	assert.Equal(t, 1, run.Attempts)
+	assert.NotNil(t, run.FinishedAt)
+	assert.JSONEq(t, `{"k":"v"}`, payload.Load().(string))
+}
+
+func TestQueue_Retry(t *testing.T) {
+	q := newTestQueue(t, openTestDB(t))
+
+	var calls atomic.Int32
+	q.Register("flaky", func(context.Context, json.RawMessage) error {
+		if calls.Add(1) < 2 {
+			return goerr.New("temporary")
+		}
+		return nil
+	})
+	q.Register("broken", func(context.Context, json.RawMessage) error {
+		panic("boom")
+	})
+	require.NoError(t, q.Schedule("flaky", "flaky", "", nil))
+	require.NoError(t, q.Schedule("broken", "broken", "", nil))
+	q.Start()
+
+	_, err := q.Trigger("flaky")
+	require.NoError(t, err)
+	run := waitRun(t, q, "flaky", model.JobRunSucceeded)
+	assert.Equal(t, 2, run.Attempts)
+
+	_, err = q.Trigger("broken")
+	require.NoError(t, err)
+	run = waitRun(t, q, "broken", model.JobRunFailed)
+	assert.Equal(t, DefaultMaxAttempts, run.Attempts)
+	assert.Equal(t, "panic: boom", run.Error)
+}
+
+func TestQueue_PauseCancel(t *testing.T) {
+	q := newTestQueue(t, openTestDB(t))
+
+	started := make(chan struct{}, 1)
+	q.Register("block", func(ctx context.Context, _ json.RawMessage) error {
+		started <- struct{}{}
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	require.NoError(t, q.Schedule("block", "block", "", nil))
+	q.Start()
+
+	job, err := q.Pause("block")
+	require.NoError(t, err)
+	assert.True(t, job.Paused)
+
+	run, err := q.Trigger("block")
+	require.NoError(t, err)
+	_, err = q.Trigger("block")
+	assert.ErrorIs(t, err, ErrJobRunActive)
+
+	// not claimed while paused.
+	time.Sleep(100 * time.Millisecond)
+	waitRun(t, q, "block", model.JobRunPending)
+
+	_, err = q.Resume("block")
+	require.NoError(t, err)
+	<-started
+
+	_, err = q.Cancel("block", run.ID)
+	require.NoError(t, err)
+	waitRun(t, q, "block", model.JobRunCanceled)
+
+	_, err = q.Cancel("block", run.ID)
+	assert.ErrorIs(t, err, ErrJobRunFinished)
+	_, err = q.Cancel("block", run.ID+1)
+	assert.ErrorIs(t, err, ErrJobRunNotFound)
+}
+
+func TestQueue_ReclaimExpired(t *testing.T) {
+	db := openTestDB(t)
+	q := newTestQueue(t, db)
+
+	var calls atomic.Int32
+	q.Register("job", func(context.Context, json.RawMessage) error {
+		calls.Add(1)
+		return nil
+	})
+	require.NoError(t, q.Schedule("job", "job", "", nil))
+
+	// held by a crashed instance.
+	expired := time.Now().Add(-time.Minute)
+	require.NoError(t, db.Create(&model.JobRun{
+		JobName:     "job",
+		Type:        "job",
+		Status:      model.JobRunRunning,
+		Attempts:    1,
+		MaxAttempts: 3,
+		RunAt:       expired,
+		Owner:       "crashed",
+		LeaseUntil:  &expired,
+	}).Error)
+	q.Start()
+
+	run := waitRun(t, q, "job", model.JobRunSucceeded)
+	assert.Equal(t, 2, run.Attempts)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestQueue_ScheduleOnce(t *testing.T) {
+	db := openTestDB(t)
+
+	var calls atomic.Int32
+	queues := make([]*Queue, 3)
+	for i := range queues {
+		queues[i] = newTestQueue(t, db)
+		queues[i].Register("job", func(context.Context, json.RawMessage) error {
+			calls.Add(1)
+			return nil
+		})
+		require.NoError(t, queues[i].Schedule("job", "job", "@every 1h", nil))
+	}
+	// due now.
+	require.NoError(t, db.Model(&model.Job{}).Where("name = ?", "job").
+		Update("next_run_at", time.Now().Add(-time.Second)).Error)
+	for _, q := range queues {
+		q.Start()
+	}
+
+	waitRun(t, queues[0], "job", model.JobRunSucceeded)
+	time.Sleep(100 * time.Millisecond)
+	runs, err := queues[0].Runs("job", 0, 0)
+	require.NoError(t, err)
+	assert.Len(t, runs, 1)
+	assert.Equal(t, int32(1), calls.Load())
+
+	jobs, err := queues[0].Jobs()
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.True(t, jobs[0].NextRunAt.After(time.Now().Add(59*time.Minute)))
+}
+
+func TestQueue_ScheduleFirstRun(t *testing.T) {
+	q := newTestQueue(t, openTestDB(t))
+
+	var calls atomic.Int32
+	q.Register("job", func(context.Context, json.RawMessage) error {
+		calls.Add(1)
+		return nil
+	})
+	require.NoError(t, q.Schedule("job", "job", "@every 1h", nil))
+	q.Start()
+
+	// a new job runs without waiting for its first due time.
+	waitRun(t, q, "job", model.JobRunSucceeded)
+	assert.Equal(t, int32(1), calls.Load())
+
+	// rescheduling keeps the due time.
+	jobs, err := q.Jobs()
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	next := *jobs[0].NextRunAt
+	require.NoError(t, q.Schedule("job", "job", "@every 1h", nil))
+	jobs, err = q.Jobs()
+	require.NoError(t, err)
+	assert.True(t, next.Equal(*jobs[0].NextRunAt))
+}
+
+func TestQueue_PruneFinished(t *testing.T) {
+	db := openTestDB(t)
+	q := newTestQueue(t, db)
+	require.NoError(t, q.Schedule("job", "job", "", nil))
+
+	now := time.Now()
+	old, recent := now.Add(-q.runRetention-time.Hour), now.Add(-time.Hour)
+	for _, run := range []*model.JobRun{
+		{JobName: "job", Status: model.JobRunSucceeded, RunAt: old, FinishedAt: &old},
+		{JobName: "job", Status: model.JobRunFailed, RunAt: old, FinishedAt: &old},
+		{JobName: "job", Status: model.JobRunSucceeded, RunAt: recent, FinishedAt: &recent},
+		{JobName: "job", Status: model.JobRunPending, RunAt: old},
+	} {
+		require.NoError(t, db.Create(run).Error)
+	}
+	require.NoError(t, q.pruneFinished(now))
+
+	runs, err := q.Runs("job", 0, 0)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, model.JobRunPending, runs[0].Status)
+	assert.Equal(t, model.JobRunSucceeded, runs[1].Status)
+}
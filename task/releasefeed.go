@@ -0,0 +1,121 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	"github.com/metatube-community/metatube-sdk-go/engine"
+	"github.com/metatube-community/metatube-sdk-go/engine/providerid"
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+const (
+	// feedMaxPages limits the pages walked per provider in one run.
+	feedMaxPages = 10
+	// feedOverlap is how far before the checkpoint the feed is still
+	// walked, as sites may list late additions with earlier dates.
+	feedOverlap = 7 * 24 * time.Hour
+)
+
+// runReleaseFeedTask ingests the release feeds of all feeder providers,
+// the errors of providers are joined after all of them are tried.
+func runReleaseFeedTask(ctx context.Context, db *gorm.DB, e *engine.Engine) error {
+	var errs []error
+	for _, provider := range e.GetMovieProviders() {
+		if _, ok := provider.(mt.ReleaseFeeder); !ok {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := ingestReleaseFeed(ctx, db, e, provider.Name()); err != nil {
+			errs = append(errs, fmt.Errorf("ingest release feed of %s: %w", provider.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// releaseFeedEngine is the part of the engine used to ingest feeds.
+type releaseFeedEngine interface {
+	GetReleaseFeed(name string, page int) ([]*model.MovieSearchResult, error)
+	GetMovieInfoByProviderID(pid providerid.ProviderID, lazy bool) (*model.MovieInfo, error)
+}
+
+var _ releaseFeedEngine = (*engine.Engine)(nil)
+
+// ingestReleaseFeed walks the feed of the provider from the newest, and
+// saves the movies until it reaches the checkpoint of the last run. The
+// progress is saved after each page, so a failed walk is resumed from
+// the failed page by the next run, and the checkpoint only moves to the
+// newest movie when the walk is done.
+func ingestReleaseFeed(ctx context.Context, db *gorm.DB, e releaseFeedEngine, name string) error {
+	checkpoint := &model.FeedCheckpoint{Provider: name}
+	if err := db.FirstOrInit(checkpoint, "provider = ?", name).Error; err != nil {
+		return err
+	}
+
+	var (
+		stopDate   time.Time
+		ingested   int64
+		reachedEnd bool
+	)
+	if last := time.Time(checkpoint.LastReleaseDate); !last.IsZero() {
+		stopDate = last.Add(-feedOverlap)
+	}
+
+	page := max(checkpoint.ResumePage, 1)
+	for ; page <= feedMaxPages && !reachedEnd; page++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		results, err := e.GetReleaseFeed(name, page)
+		if err != nil {
+			return err
+		}
+		if len(results) == 0 {
+			break
+		}
+		for _, result := range results {
+			releaseDate := time.Time(result.ReleaseDate)
+			if (checkpoint.LastID != "" && result.ID == checkpoint.LastID) ||
+				(!stopDate.IsZero() && !releaseDate.IsZero() && releaseDate.Before(stopDate)) {
+				reachedEnd = true
+				break
+			}
+			pid := providerid.ProviderID{Provider: name, ID: result.ID}
+			if _, err = e.GetMovieInfoByProviderID(pid, true); err != nil {
+				log.Printf("Failed to ingest %s:%s from release feed: %v", pid.Provider, pid.ID, err)
+				continue
+			}
+			ingested++
+			checkpoint.Ingested++
+			if checkpoint.PendingID == "" || releaseDate.After(time.Time(checkpoint.PendingReleaseDate)) {
+				checkpoint.PendingID = result.ID
+				checkpoint.PendingReleaseDate = result.ReleaseDate
+			}
+		}
+		checkpoint.ResumePage = page + 1
+		if err = db.Save(checkpoint).Error; err != nil {
+			return err
+		}
+	}
+
+	log.Printf("Ingested %d movies from release feed of %s", ingested, name)
+
+	if checkpoint.PendingID != "" &&
+		!time.Time(checkpoint.PendingReleaseDate).Before(time.Time(checkpoint.LastReleaseDate)) {
+		checkpoint.LastID = checkpoint.PendingID
+		checkpoint.LastReleaseDate = checkpoint.PendingReleaseDate
+	}
+	checkpoint.ResumePage = 0
+	checkpoint.PendingID = ""
+	checkpoint.PendingReleaseDate = datatypes.Date{}
+	return db.Save(checkpoint).Error
+}
@@ -0,0 +1,79 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+
+	"github.com/metatube-community/metatube-sdk-go/engine/providerid"
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+// fakeFeed serves pages of two movies, released one per day backwards
+// from the newest, and fails the pages in failPages.
+type fakeFeed struct {
+	newest    time.Time
+	total     int
+	failPages map[int]bool
+	ingested  []string
+}
+
+func (f *fakeFeed) GetReleaseFeed(_ string, page int) ([]*model.MovieSearchResult, error) {
+	if f.failPages[page] {
+		return nil, errors.New("page failed")
+	}
+	var results []*model.MovieSearchResult
+	for i := (page - 1) * 2; i < page*2 && i < f.total; i++ {
+		results = append(results, &model.MovieSearchResult{
+			ID:          fmt.Sprintf("ID-%03d", f.total-i),
+			ReleaseDate: datatypes.Date(f.newest.AddDate(0, 0, -i)),
+		})
+	}
+	return results, nil
+}
+
+func (f *fakeFeed) GetMovieInfoByProviderID(pid providerid.ProviderID, _ bool) (*model.MovieInfo, error) {
+	f.ingested = append(f.ingested, pid.ID)
+	return &model.MovieInfo{}, nil
+}
+
+func TestIngestReleaseFeed(t *testing.T) {
+	db := openTestDB(t)
+	newest := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	feed := &fakeFeed{newest: newest, total: 6, failPages: map[int]bool{3: true}}
+
+	// the pages before the failed one are kept for the next run.
+	assert.Error(t, ingestReleaseFeed(context.Background(), db, feed, "TEST"))
+	checkpoint := &model.FeedCheckpoint{}
+	require.NoError(t, db.First(checkpoint, "provider = ?", "TEST").Error)
+	assert.Equal(t, 3, checkpoint.ResumePage)
+	assert.Equal(t, "ID-006", checkpoint.PendingID)
+	assert.Empty(t, checkpoint.LastID)
+	assert.Equal(t, int64(4), checkpoint.Ingested)
+
+	// the walk resumes from the failed page.
+	feed.failPages, feed.ingested = nil, nil
+	require.NoError(t, ingestReleaseFeed(context.Background(), db, feed, "TEST"))
+	assert.Equal(t, []string{"ID-002", "ID-001"}, feed.ingested)
+	checkpoint = &model.FeedCheckpoint{}
+	require.NoError(t, db.First(checkpoint, "provider = ?", "TEST").Error)
+	assert.Zero(t, checkpoint.ResumePage)
+	assert.Empty(t, checkpoint.PendingID)
+	assert.Equal(t, "ID-006", checkpoint.LastID)
+	assert.Equal(t, newest, time.Time(checkpoint.LastReleaseDate).UTC())
+	assert.Equal(t, int64(6), checkpoint.Ingested)
+
+	// the next walk stops at the checkpoint.
+	feed.total, feed.newest, feed.ingested = 7, newest.AddDate(0, 0, 1), nil
+	require.NoError(t, ingestReleaseFeed(context.Background(), db, feed, "TEST"))
+	assert.Equal(t, []string{"ID-007"}, feed.ingested)
+	checkpoint = &model.FeedCheckpoint{}
+	require.NoError(t, db.First(checkpoint, "provider = ?", "TEST").Error)
+	assert.Equal(t, "ID-007", checkpoint.LastID)
+}